@@ -0,0 +1,181 @@
+package querier
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Audit actions.
+const (
+	AuditInsert = "insert"
+	AuditUpdate = "update"
+	AuditDelete = "delete"
+)
+
+// AuditTable is the table of the audit log.
+const AuditTable = "audit_log"
+
+var (
+	auditorMu sync.RWMutex
+	auditor   *Auditor
+)
+
+// Auditor configures the audit log. When it is set with SetAuditor, InsertStruct, UpdateStruct and DeleteStruct write
+// an AuditRecord of the change to AuditTable, using the same Executor, after the statement executed successfully.
+// Run them in a transaction, so the record is written with the change and the old values can't change in between.
+type Auditor struct {
+	// Tables configures auditing per table. If Tables is nil all tables are audited, otherwise only the tables in it.
+	Tables map[string]AuditOptions
+}
+
+// AuditOptions are the audit options of a table.
+type AuditOptions struct {
+	// Omit contains the fields that are left out of the recorded values.
+	Omit []string
+}
+
+// AuditRecord is a record of the audit log. It implements the migrator's Model, so the audit log can be migrated with
+// the other models. Values are recorded as JSON objects, with sensitive and encrypted fields redacted.
+type AuditRecord struct {
+	Table     string    `db:"table_name"`
+	Action    string    `db:"action,,enum=insert;update;delete"`
+	RecordKey string    `db:"record_key,TEXT NOT NULL"`
+	OldValues *string   `db:"old_values,TEXT NULL"`
+	NewValues *string   `db:"new_values,TEXT NULL"`
+	Actor     string    `db:"actor"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// TableName returns AuditTable.
+func (*AuditRecord) TableName() string {
+	return AuditTable
+}
+
+// CreateTable does nothing, the audit log has no primary key.
+func (*AuditRecord) CreateTable(*Q) {}
+
+// Migrate does nothing.
+func (*AuditRecord) Migrate(*Q, string) error {
+	return nil
+}
+
+// SetAuditor sets the Auditor, nil disables auditing. Statements that are already written keep the Auditor of when
+// they were written.
+func SetAuditor(a *Auditor) {
+	auditorMu.Lock()
+	defer auditorMu.Unlock()
+	auditor = a
+}
+
+func currentAuditor() *Auditor {
+	auditorMu.RLock()
+	defer auditorMu.RUnlock()
+	return auditor
+}
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx with the actor of changes, which is recorded in the audit log.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// Actor returns the actor of changes in ctx, see WithActor.
+func Actor(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+// audit records the change of struct i in table when the statement executes. The old values of updated and deleted
+// rows are read right before the statement, with the same Executor, so they're consistent when the statement runs in
+// a transaction. Computed and generated fields aren't recorded.
+func (q *Q) audit(action, table string, i interface{}, fields []Field, values ValueMap) {
+	a := currentAuditor()
+	if a == nil || table == AuditTable || q.buildErr != nil {
+		// Nothing to record, or the statement won't execute.
+		return
+	}
+	var opts AuditOptions
+	if a.Tables != nil {
+		var ok bool
+		if opts, ok = a.Tables[table]; !ok {
+			return
+		}
+	}
+
+	fields = writableColumns(fields)
+	record := AuditRecord{Table: table, Action: action}
+	var err error
+	if record.RecordKey, err = auditValues(fields, values, nil, true); err != nil {
+		q.fail(err)
+		return
+	}
+	if action != AuditInsert {
+		elemType := reflect.TypeOf(i).Elem()
+		keys, _ := splitPrimaryKey(fields)
+		q.beforeExec = append(q.beforeExec, func(q *Q) error {
+			old := reflect.New(elemType).Interface()
+			err := q.New().
+				Write("SELECT").
+				WriteFields("{name}", FieldSep, fields...).
+				Writef("FROM %s WHERE", q.Qualify(table)).
+				WriteValueMap("{name} = {bindVar}", " AND ", values, keys...).
+				FirstContext(q.Context(), old)
+			if err == ErrNoRecord {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			oldValues, err := auditValues(fields, Values(old), opts.Omit, false)
+			if err != nil {
+				return err
+			}
+			record.OldValues = &oldValues
+			return nil
+		})
+	}
+	if action != AuditDelete {
+		newValues, err := auditValues(fields, values, opts.Omit, false)
+		if err != nil {
+			q.fail(err)
+			return
+		}
+		record.NewValues = &newValues
+	}
+
+	q.afterExec = append(q.afterExec, func(q *Q) error {
+		record.Actor = Actor(q.Context())
+		record.CreatedAt = time.Now().UTC()
+		return q.New().InsertStruct(AuditTable, &record).ExecContext(q.Context())
+	})
+}
+
+// auditValues returns the values of fields as a JSON object, without the fields in omit. If keys is true only the
+// primary key fields are included.
+func auditValues(fields []Field, values ValueMap, omit []string, keys bool) (string, error) {
+	m := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		if keys && !field.PrimaryKey || contains(omit, field.Name) {
+			continue
+		}
+		if field.Sensitive || field.Encrypted {
+			m[field.Name] = Redacted
+		} else if value, ok := values[field.Name]; ok {
+			m[field.Name] = value.Interface()
+		}
+	}
+	b, err := json.Marshal(m)
+	return string(b), err
+}
+
+func contains(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package querier
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+)
+
+type auditModel struct {
+	ID       int64  `db:"id,,pk"`
+	Name     string `db:"name"`
+	Password string `db:"password,,sensitive"`
+	Note     string `db:"note"`
+}
+
+func TestAudit(t *testing.T) {
+	SetAuditor(&Auditor{Tables: map[string]AuditOptions{"user": {Omit: []string{"note"}}}})
+	defer SetAuditor(nil)
+
+	var inserted []driver.Value
+	db := openTestDB(t, func(query string, args []driver.Value) (*testRows, error) {
+		switch {
+		case strings.HasPrefix(query, "SELECT"):
+			return &testRows{
+				columns: []string{"id", "name", "password", "note"},
+				values:  [][]driver.Value{{int64(1), "foo", "secret", "old"}},
+			}, nil
+		case strings.HasPrefix(query, "INSERT INTO audit_log"):
+			inserted = args
+		}
+		return nil, nil
+	})
+	defer db.Close()
+
+	ctx := WithActor(context.Background(), "alice")
+	m := auditModel{ID: 1, Name: "bar", Password: "secret", Note: "new"}
+	if err := New(db, Default{}).UpdateStruct("user", &m).ExecContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(inserted) != 7 {
+		t.Fatalf("audit record has %d values, want 7", len(inserted))
+	}
+	want := []driver.Value{
+		"user",
+		AuditUpdate,
+		`{"id":1}`,
+		`{"id":1,"name":"foo","password":"[REDACTED]"}`,
+		`{"id":1,"name":"bar","password":"[REDACTED]"}`,
+		"alice",
+	}
+	for i := range want {
+		if inserted[i] != want[i] {
+			t.Errorf("audit record value %d = %v, want %v", i, inserted[i], want[i])
+		}
+	}
+
+	// Tables that are not configured are not audited.
+	inserted = nil
+	if err := New(db, Default{}).InsertStruct("other", &m).Exec(); err != nil {
+		t.Fatal(err)
+	}
+	if inserted != nil {
+		t.Errorf("audit record %v written for unaudited table", inserted)
+	}
+}
+
+type auditReport struct {
+	ID         int64  `db:"id,,pk"`
+	Name       string `db:"name"`
+	TotalCount int64  `db:"total_count,,computed"`
+	Upper      string `db:"upper,,generated"`
+}
+
+func TestAuditExecution(t *testing.T) {
+	SetAuditor(&Auditor{})
+	defer SetAuditor(nil)
+
+	var queries []string
+	db := openTestDB(t, func(query string, args []driver.Value) (*testRows, error) {
+		queries = append(queries, query)
+		if strings.HasPrefix(query, "SELECT") {
+			return &testRows{columns: []string{"id", "name"}, values: [][]driver.Value{{int64(1), "foo"}}}, nil
+		}
+		return nil, nil
+	})
+	defer db.Close()
+
+	q := New(db, Default{}).SetSchema("s").DeleteStruct("report", &auditReport{ID: 1})
+	if queries != nil {
+		t.Fatalf("building the statement executed %v", queries)
+	}
+	if err := q.Exec(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"SELECT id, name FROM s.report WHERE id = ?",
+		"DELETE FROM s.report WHERE id = ?",
+		"INSERT INTO s.audit_log (table_name, action, record_key, old_values, new_values, actor, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+	}
+	if len(queries) != len(want) {
+		t.Fatalf("executed %q, want %q", queries, want)
+	}
+	for i := range want {
+		if queries[i] != want[i] {
+			t.Errorf("query %d = %q, want %q", i, queries[i], want[i])
+		}
+	}
+}
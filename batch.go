@@ -0,0 +1,87 @@
+//go:build go1.18
+// +build go1.18
+
+package querier
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+)
+
+// ForEachBatch executes the query and calls fn with the scanned rows in batches of batchSize, the last batch may be
+// smaller. T is a struct or a pointer to a struct, scanned like the elements of Find, or a single column type like
+// int64. Every batch is a new slice, so fn may keep it. This streams large results to bulk writes downstream, e.g.
+//
+//	err := querier.ForEachBatch(ctx, q.Write("SELECT * FROM event"), 500, func(events []Event) error {
+//		return sink.WriteEvents(events)
+//	})
+func ForEachBatch[T any](ctx context.Context, q *Q, batchSize int, fn func([]T) error) error {
+	if batchSize < 1 {
+		panic("batchSize must be positive")
+	}
+	var (
+		batch       []T
+		columns     []string
+		columnTypes []*sql.ColumnType
+		valueMap    ValueMap
+		fields      []interface{}
+	)
+	err := q.ForEachContext(ctx, func(_ *Q, rows *sql.Rows) error {
+		if batch == nil {
+			batch = make([]T, 0, batchSize)
+		}
+		batch = append(batch, *new(T))
+		element := reflect.ValueOf(&batch[len(batch)-1]).Elem()
+		if element.Kind() == reflect.Ptr && element.Type().Elem().Kind() == reflect.Struct {
+			element.Set(reflect.New(element.Type().Elem()))
+			element = element.Elem()
+		}
+		dest := element.Addr().Interface()
+		if s, ok := dest.(Scannable); ok {
+			if err := s.ScanRow(rows); err != nil {
+				return err
+			}
+		} else if element.Kind() != reflect.Struct {
+			if err := rows.Scan(dest); err != nil {
+				return err
+			}
+		} else {
+			valueMap = makeValueMap(element, valueMap, "")
+			if columns == nil {
+				var err error
+				if columns, err = rows.Columns(); err != nil {
+					return err
+				}
+				if columnTypes, err = rows.ColumnTypes(); err != nil {
+					return err
+				}
+				columns = matchColumns(valueMap, columns, q.columnMatching)
+				if err = checkColumnNames(valueMap, columns); err != nil {
+					return err
+				}
+			}
+			fields = valueMap.MapToColumns(columns, fields[:0])
+			decodeColumns(q.coercion, columnTypes, fields)
+			if err := rows.Scan(fields...); err != nil {
+				return err
+			}
+		}
+		if len(batch) < batchSize {
+			return nil
+		}
+		full := batch
+		batch = nil
+		return fn(full)
+	})
+	if err != nil {
+		return err
+	}
+	if len(batch) > 0 {
+		if err = fn(batch); !errors.Is(err, ErrStopIteration) {
+			return err
+		}
+	}
+	return nil
+}
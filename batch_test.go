@@ -0,0 +1,69 @@
+//go:build go1.18
+// +build go1.18
+
+package querier
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+)
+
+func TestForEachBatch(t *testing.T) {
+	type user struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+	db := openTestDB(t, func(query string, _ []driver.Value) (*testRows, error) {
+		rows := &testRows{columns: []string{"id", "name"}}
+		if query == "SELECT id FROM user" {
+			rows.columns = rows.columns[:1]
+		}
+		for i := 1; i <= 5; i++ {
+			rows.values = append(rows.values, []driver.Value{int64(i), "user"}[:len(rows.columns)])
+		}
+		return rows, nil
+	})
+	defer db.Close()
+
+	var batches [][]user
+	err := ForEachBatch(context.Background(), New(db, Default{}).Write("SELECT * FROM user"), 2, func(users []user) error {
+		batches = append(batches, users)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]user{
+		{{1, "user"}, {2, "user"}},
+		{{3, "user"}, {4, "user"}},
+		{{5, "user"}},
+	}
+	if !reflect.DeepEqual(batches, want) {
+		t.Errorf("got %v, want %v", batches, want)
+	}
+
+	var ids []int64
+	err = ForEachBatch(context.Background(), New(db, Default{}).Write("SELECT id FROM user"), 10, func(batch []int64) error {
+		ids = append(ids, batch...)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ids, []int64{1, 2, 3, 4, 5}) {
+		t.Errorf("got %v", ids)
+	}
+	var pointers []*user
+	err = ForEachBatch(context.Background(), New(db, Default{}).Write("SELECT * FROM user"), 3, func(users []*user) error {
+		pointers = append(pointers, users...)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pointers) != 5 || pointers[4] == nil || *pointers[4] != (user{5, "user"}) {
+		t.Errorf("got %v, want pointers to the 5 users", pointers)
+	}
+}
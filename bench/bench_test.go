@@ -0,0 +1,223 @@
+package bench
+
+import (
+	"database/sql"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/semrekkers/querier"
+	"github.com/semrekkers/querier/migrator"
+)
+
+var columnCounts = []int{1, 5, 20}
+
+// structOf returns a struct type with an int64 field for each column of newTable.
+func structOf(columns int) reflect.Type {
+	fields := make([]reflect.StructField, columns)
+	for i := range fields {
+		fields[i] = reflect.StructField{
+			Name: "C" + strconv.Itoa(i),
+			Type: reflect.TypeOf(int64(0)),
+			Tag:  reflect.StructTag(`db:"c` + strconv.Itoa(i) + `"`),
+		}
+	}
+	return reflect.StructOf(fields)
+}
+
+func BenchmarkBuild(b *testing.B) {
+	for _, columns := range columnCounts {
+		b.Run("columns="+strconv.Itoa(columns), func(b *testing.B) {
+			v := reflect.New(structOf(columns)).Interface()
+			q := querier.New(nil, querier.Default{})
+			fields := q.Fields(v).Select()
+			values := querier.Values(v)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				q.Reset().Write("INSERT INTO t (").
+					WriteFields("{name}", querier.FieldSep, fields...).
+					WriteRaw(") VALUES (").
+					WriteValueMap("{bindVar}", querier.FieldSep, values, fields...).
+					WriteRaw(")")
+			}
+		})
+	}
+}
+
+func BenchmarkInsertStruct(b *testing.B) {
+	for _, columns := range columnCounts {
+		b.Run("columns="+strconv.Itoa(columns), func(b *testing.B) {
+			v := reflect.New(structOf(columns)).Interface()
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				querier.New(nil, querier.Default{}).InsertStruct("t", v)
+			}
+		})
+	}
+}
+
+func BenchmarkFirst(b *testing.B) {
+	for _, columns := range columnCounts {
+		b.Run("columns="+strconv.Itoa(columns), func(b *testing.B) {
+			db := openDB(b, newTable(columns, 1))
+			defer db.Close()
+			v := reflect.New(structOf(columns)).Interface()
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := querier.New(db, querier.Default{}).Write("SELECT * FROM t").First(v); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkRawFirst is the database/sql baseline of BenchmarkFirst.
+func BenchmarkRawFirst(b *testing.B) {
+	for _, columns := range columnCounts {
+		b.Run("columns="+strconv.Itoa(columns), func(b *testing.B) {
+			db := openDB(b, newTable(columns, 1))
+			defer db.Close()
+			dest := make([]int64, columns)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := db.QueryRow("SELECT * FROM t").Scan(scanDest(dest)...); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkFind(b *testing.B) {
+	for _, columns := range columnCounts {
+		b.Run("columns="+strconv.Itoa(columns), func(b *testing.B) {
+			db := openDB(b, newTable(columns, 100))
+			defer db.Close()
+			slice := reflect.New(reflect.SliceOf(structOf(columns)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				slice.Elem().SetLen(0)
+				if err := querier.New(db, querier.Default{}).Write("SELECT * FROM t").Find(slice.Interface()); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkFindConcurrent is BenchmarkFind with 4 scan workers.
+func BenchmarkFindConcurrent(b *testing.B) {
+	for _, columns := range columnCounts {
+		b.Run("columns="+strconv.Itoa(columns), func(b *testing.B) {
+			db := openDB(b, newTable(columns, 100))
+			defer db.Close()
+			slice := reflect.New(reflect.SliceOf(structOf(columns)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				slice.Elem().SetLen(0)
+				q := querier.New(db, querier.Default{}).SetScanWorkers(4)
+				if err := q.Write("SELECT * FROM t").Find(slice.Interface()); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkRawFind is the database/sql baseline of BenchmarkFind.
+func BenchmarkRawFind(b *testing.B) {
+	for _, columns := range columnCounts {
+		b.Run("columns="+strconv.Itoa(columns), func(b *testing.B) {
+			db := openDB(b, newTable(columns, 100))
+			defer db.Close()
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := rawFind(db, columns); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func rawFind(db *sql.DB, columns int) error {
+	rows, err := db.Query("SELECT * FROM t")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	var result [][]int64
+	for rows.Next() {
+		dest := make([]int64, columns)
+		if err = rows.Scan(scanDest(dest)...); err != nil {
+			return err
+		}
+		result = append(result, dest)
+	}
+	return rows.Err()
+}
+
+func scanDest(dest []int64) []interface{} {
+	ptrs := make([]interface{}, len(dest))
+	for i := range dest {
+		ptrs[i] = &dest[i]
+	}
+	return ptrs
+}
+
+// model has the columns of newTable(10, 0).
+type model struct {
+	C0 int64 `db:"c0"`
+	C1 int64 `db:"c1"`
+	C2 int64 `db:"c2"`
+	C3 int64 `db:"c3"`
+	C4 int64 `db:"c4"`
+	C5 int64 `db:"c5"`
+	C6 int64 `db:"c6"`
+	C7 int64 `db:"c7"`
+	C8 int64 `db:"c8"`
+	C9 int64 `db:"c9"`
+}
+
+func (*model) TableName() string                { return "t" }
+func (*model) CreateTable(*querier.Q)           {}
+func (*model) Migrate(*querier.Q, string) error { return nil }
+
+// dbInfo reports that table t exists with the columns of newTable.
+type dbInfo struct {
+	querier.Default
+}
+
+func (dbInfo) HasTable(*querier.Q, string) (bool, error) {
+	return true, nil
+}
+
+func (dbInfo) TableColumns(q *querier.Q, table string) (columns []string, err error) {
+	err = q.Write("SELECT column_name FROM information_schema.columns WHERE table_name = ?", table).
+		ForEach(func(_ *querier.Q, rows *sql.Rows) error {
+			var column string
+			err := rows.Scan(&column)
+			columns = append(columns, column)
+			return err
+		})
+	return
+}
+
+// BenchmarkMigratorPlan benchmarks migrating a model whose table is up to date, which only compares the columns.
+func BenchmarkMigratorPlan(b *testing.B) {
+	db := openDB(b, newTable(10, 0))
+	defer db.Close()
+	m := migrator.New(db, dbInfo{})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		res, err := m.Migrate(&model{})
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(res.NewColumns) != 0 {
+			b.Fatalf("new columns %v, want none", res.NewColumns)
+		}
+	}
+}
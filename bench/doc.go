@@ -0,0 +1,11 @@
+// Package bench contains the benchmarks of Querier, to evaluate performance related changes. It benchmarks building
+// queries, scanning with First and Find for varying numbers of columns, and migration planning by the migrator.
+// Scanning is compared to plain database/sql baselines. The queries are served by an in-memory driver, so the results
+// measure the package's overhead rather than a database.
+//
+// Every benchmark reports allocations. For comparable results in CI, run a fixed number of iterations and compare
+// the output of two revisions with benchstat:
+//
+//	go test -run '^$' -bench . -benchtime 2000x -count 10 ./bench > new.txt
+//	benchstat old.txt new.txt
+package bench
@@ -0,0 +1,116 @@
+package bench
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// table is an in-memory result set that is returned for every query.
+type table struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+var (
+	registerOnce sync.Once
+	tablesMu     sync.Mutex
+	tables       = make(map[string]*table)
+)
+
+// openDB opens a database that returns t for every query.
+func openDB(b *testing.B, t *table) *sql.DB {
+	registerOnce.Do(func() {
+		sql.Register("querier-bench", benchDriver{})
+	})
+	tablesMu.Lock()
+	dsn := strconv.Itoa(len(tables))
+	tables[dsn] = t
+	tablesMu.Unlock()
+	db, err := sql.Open("querier-bench", dsn)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return db
+}
+
+// newTable returns a table with columns c0, c1, ... and rows of int64 values.
+func newTable(columns, rows int) *table {
+	t := &table{columns: make([]string, columns)}
+	for i := range t.columns {
+		t.columns[i] = "c" + strconv.Itoa(i)
+	}
+	for i := 0; i < rows; i++ {
+		row := make([]driver.Value, columns)
+		for j := range row {
+			row[j] = int64(i + j)
+		}
+		t.rows = append(t.rows, row)
+	}
+	return t
+}
+
+type benchDriver struct{}
+
+func (benchDriver) Open(dsn string) (driver.Conn, error) {
+	tablesMu.Lock()
+	defer tablesMu.Unlock()
+	return &conn{t: tables[dsn]}, nil
+}
+
+type conn struct {
+	t *table
+}
+
+func (c *conn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *conn) Close() error                        { return nil }
+func (c *conn) Begin() (driver.Tx, error)           { return tx{}, nil }
+
+func (c *conn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	if strings.HasPrefix(query, "SELECT column_name") {
+		// Columns of the migrated table.
+		r := &rows{columns: []string{"column_name"}}
+		for _, column := range c.t.columns {
+			r.values = append(r.values, []driver.Value{column})
+		}
+		return r, nil
+	}
+	return &rows{columns: c.t.columns, values: c.t.rows}, nil
+}
+
+func (c *conn) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	return result{}, nil
+}
+
+type result struct{}
+
+func (result) LastInsertId() (int64, error) { return 0, nil }
+func (result) RowsAffected() (int64, error) { return 1, nil }
+
+type tx struct{}
+
+func (tx) Commit() error   { return nil }
+func (tx) Rollback() error { return nil }
+
+type rows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *rows) Columns() []string { return r.columns }
+func (r *rows) Close() error      { return nil }
+
+func (r *rows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
@@ -0,0 +1,37 @@
+package querier
+
+import "testing"
+
+type benchModel struct {
+	ID        int64  `db:"id,,pk"`
+	Name      string `db:"name"`
+	Email     string `db:"email"`
+	Age       int    `db:"age"`
+	Active    bool   `db:"active"`
+	CreatedAt int64  `db:"created_at"`
+}
+
+func BenchmarkInsertStruct(b *testing.B) {
+	ex := new(testExecutor)
+	m := benchModel{ID: 1, Name: "foo", Email: "foo@example.com", Age: 42, Active: true}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ex.queries = ex.queries[:0]
+		if err := New(ex, Default{}).InsertStruct("user", &m).Exec(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkWriteValueMap(b *testing.B) {
+	m := benchModel{ID: 1, Name: "foo", Email: "foo@example.com", Age: 42, Active: true}
+	fields := Fields(&m).Select()
+	values := Values(&m)
+	q := New(nil, Default{})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		q.Reset().Write("INSERT INTO user").
+			WriteFields("({name})", FieldSep, fields...).
+			WriteValueMap("VALUES ({bindVar})", FieldSep, values, fields...)
+	}
+}
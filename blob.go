@@ -0,0 +1,84 @@
+package querier
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+)
+
+// WriteBlob returns a ScanFunc that writes the first column of every row to w. The column is scanned as sql.RawBytes,
+// so it isn't copied before it's written. Panics when w is invalid.
+func WriteBlob(w io.Writer) ScanFunc {
+	if w == nil {
+		panic("invalid writer")
+	}
+	return func(_ *Q, r *sql.Rows) error {
+		var b sql.RawBytes
+		if err := r.Scan(&b); err != nil {
+			return err
+		}
+		_, err := w.Write(b)
+		return err
+	}
+}
+
+// BlobReader reads a BLOB column in chunks using SUBSTRING, so large values don't have to be loaded into memory
+// wholly. It is created by Q.BlobReader.
+type BlobReader struct {
+	q             *Q
+	ctx           context.Context
+	table, column string
+	chunkSize     int
+	offset        int64
+	buf           []byte
+	eof           bool
+}
+
+// BlobReader returns a BlobReader which reads column of the row in table selected by the query, e.g.
+//
+//	r := q.Write("WHERE id = ?", id).BlobReader(ctx, "files", "data", 1<<20)
+//
+// Every chunk of chunkSize bytes is read with a separate query. The query must select exactly one row.
+func (q *Q) BlobReader(ctx context.Context, table, column string, chunkSize int) *BlobReader {
+	if chunkSize < 1 {
+		panic("invalid chunk size")
+	}
+	return &BlobReader{q: q, ctx: ctx, table: q.Qualify(table), column: column, chunkSize: chunkSize}
+}
+
+// Read implements io.Reader.
+func (r *BlobReader) Read(p []byte) (n int, err error) {
+	if len(r.buf) == 0 {
+		if r.eof {
+			return 0, io.EOF
+		}
+		if err = r.next(); err != nil {
+			return 0, err
+		}
+		if len(r.buf) == 0 {
+			return 0, io.EOF
+		}
+	}
+	n = copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func (r *BlobReader) next() error {
+	q := r.q.New()
+	q.Writef("SELECT SUBSTRING(%s, %d, %d) FROM %s", r.column, r.offset+1, r.chunkSize, r.table).
+		writeQ(r.q)
+
+	var chunk []byte
+	if err := q.ScanContext(r.ctx, &chunk); err != nil {
+		if err == ErrNoRecord {
+			return fmt.Errorf("blob %s.%s: %s", r.table, r.column, err)
+		}
+		return err
+	}
+	r.offset += int64(len(chunk))
+	r.buf = chunk
+	r.eof = len(chunk) < r.chunkSize
+	return nil
+}
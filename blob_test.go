@@ -0,0 +1,59 @@
+package querier
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+func TestBlobReader(t *testing.T) {
+	blob := bytes.Repeat([]byte("0123456789"), 10)
+	substring := regexp.MustCompile(`^SELECT SUBSTRING\(data, (\d+), (\d+)\) FROM files WHERE id = \?$`)
+	var queries int
+	db := openTestDB(t, func(query string, args []driver.Value) (*testRows, error) {
+		queries++
+		m := substring.FindStringSubmatch(query)
+		if m == nil || len(args) != 1 {
+			t.Fatalf("unexpected query %q %v", query, args)
+		}
+		start, _ := strconv.Atoi(m[1])
+		end, _ := strconv.Atoi(m[2])
+		end += start - 1
+		if end > len(blob) {
+			end = len(blob)
+		}
+		return &testRows{columns: []string{"chunk"}, values: [][]driver.Value{{blob[start-1 : end]}}}, nil
+	})
+	defer db.Close()
+
+	r := New(db, Default{}).Write("WHERE id = ?", 1).BlobReader(context.Background(), "files", "data", 30)
+	got, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, blob) {
+		t.Errorf("ReadAll() = %q, want %q", got, blob)
+	}
+	if queries != 4 {
+		t.Errorf("BlobReader executed %d queries, want 4", queries)
+	}
+}
+
+func TestWriteBlob(t *testing.T) {
+	db := openTestDB(t, func(string, []driver.Value) (*testRows, error) {
+		return &testRows{columns: []string{"data"}, values: [][]driver.Value{{[]byte("foo")}, {[]byte("bar")}}}, nil
+	})
+	defer db.Close()
+
+	var buf bytes.Buffer
+	if err := New(db, Default{}).Write("SELECT data FROM files").ForEach(WriteBlob(&buf)); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "foobar" {
+		t.Errorf("WriteBlob() wrote %q, want %q", buf.String(), "foobar")
+	}
+}
@@ -0,0 +1,232 @@
+package querier
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrBreakerOpen is returned by a Breaker that rejects executions.
+var ErrBreakerOpen = errors.New("circuit breaker is open")
+
+// BreakerState is the state of a Breaker.
+type BreakerState int
+
+const (
+	// BreakerClosed executes all statements.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen rejects all statements with ErrBreakerOpen.
+	BreakerOpen
+	// BreakerHalfOpen executes a limited number of probes, which decide whether the breaker closes or opens again.
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// BreakerConfig configures a Breaker. Zero fields use their defaults.
+type BreakerConfig struct {
+	// Window is the period in which the failures are counted. Defaults to 10 seconds.
+	Window time.Duration
+	// MinRequests is the number of executions in the window before the breaker can open. Defaults to 20.
+	MinRequests int
+	// FailureRate opens the breaker when the fraction of failed executions in the window reaches it. Defaults to 0.5.
+	FailureRate float64
+	// SlowThreshold counts executions that take longer as failed, so a database that is slow to respond sheds load
+	// too. Zero disables it.
+	SlowThreshold time.Duration
+	// OpenTimeout is how long the breaker stays open before it lets probes through. Defaults to 5 seconds.
+	OpenTimeout time.Duration
+	// Probes is the number of executions let through when half-open, which must all succeed to close the breaker.
+	// Defaults to 1.
+	Probes int
+	// Dialect translates the errors for the default IsFailure, see DialectErrors.
+	Dialect Dialect
+	// IsFailure returns true if err counts as a failure. Defaults to any error except context.Canceled and the errors
+	// the Dialect translates into a UniqueViolation, FKViolation, NotNullViolation or SyntaxError, which are caused by
+	// the caller and say nothing about the health of the database. Without a Dialect that implements DialectErrors,
+	// every driver error counts, so configure IsFailure to keep e.g. a burst of conflicts from opening the breaker.
+	IsFailure func(err error) bool
+	// OnStateChange is called when the state changes, e.g. to export it as a metric. It must not block.
+	OnStateChange func(from, to BreakerState)
+}
+
+// Breaker is an Executor that stops executing statements when the underlying Executor fails too often, so a
+// struggling database sheds load fast instead of timing out on every query:
+//
+//	b := querier.NewBreaker(db, querier.BreakerConfig{SlowThreshold: time.Second, Dialect: db.Dialect()})
+//	q := querier.New(b, db.Dialect())
+//
+// While closed, the breaker opens when the failure rate in the window reaches FailureRate. While open, executions
+// fail with ErrBreakerOpen. After OpenTimeout the breaker is half-open and lets Probes executions through, it closes if
+// they all succeed and opens again otherwise. A Breaker is safe for concurrent use.
+type Breaker struct {
+	ex  Executor
+	cfg BreakerConfig
+	now func() time.Time
+
+	mu                 sync.Mutex
+	state              BreakerState
+	windowStart        time.Time
+	requests, failures int
+	openedAt           time.Time
+	probes, successes  int
+}
+
+// NewBreaker returns a Breaker around ex.
+func NewBreaker(ex Executor, cfg BreakerConfig) *Breaker {
+	if cfg.Window == 0 {
+		cfg.Window = 10 * time.Second
+	}
+	if cfg.MinRequests == 0 {
+		cfg.MinRequests = 20
+	}
+	if cfg.FailureRate == 0 {
+		cfg.FailureRate = 0.5
+	}
+	if cfg.OpenTimeout == 0 {
+		cfg.OpenTimeout = 5 * time.Second
+	}
+	if cfg.Probes == 0 {
+		cfg.Probes = 1
+	}
+	if cfg.IsFailure == nil {
+		d := cfg.Dialect
+		cfg.IsFailure = func(err error) bool { return isFailure(d, err) }
+	}
+	return &Breaker{ex: ex, cfg: cfg, now: time.Now}
+}
+
+// isFailure is the default IsFailure of a BreakerConfig with Dialect d.
+func isFailure(d Dialect, err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var (
+		unique  *UniqueViolation
+		fk      *FKViolation
+		notNull *NotNullViolation
+		syntax  *SyntaxError
+	)
+	err = translateError(d, err)
+	return !errors.As(err, &unique) && !errors.As(err, &fk) && !errors.As(err, &notNull) && !errors.As(err, &syntax)
+}
+
+// State returns the current state of the breaker.
+func (b *Breaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.cfg.OpenTimeout {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
+
+// ExecContext implements Executor.
+func (b *Breaker) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if !b.allow() {
+		return nil, ErrBreakerOpen
+	}
+	start := b.now()
+	result, err := b.ex.ExecContext(ctx, query, args...)
+	b.done(b.now().Sub(start), err)
+	return result, err
+}
+
+// QueryContext implements Executor. The duration compared to SlowThreshold doesn't include reading the rows.
+func (b *Breaker) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if !b.allow() {
+		return nil, ErrBreakerOpen
+	}
+	start := b.now()
+	rows, err := b.ex.QueryContext(ctx, query, args...)
+	b.done(b.now().Sub(start), err)
+	return rows, err
+}
+
+// track tracks the execution if the underlying Executor is a tracker, e.g. a DB.
+func (b *Breaker) track() (func(), error) {
+	if t, ok := b.ex.(tracker); ok {
+		return t.track()
+	}
+	return func() {}, nil
+}
+
+// allow returns true if an execution may start.
+func (b *Breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case BreakerOpen:
+		if b.now().Sub(b.openedAt) < b.cfg.OpenTimeout {
+			return false
+		}
+		b.setState(BreakerHalfOpen)
+		b.probes, b.successes = 0, 0
+		fallthrough
+	case BreakerHalfOpen:
+		if b.probes >= b.cfg.Probes {
+			return false
+		}
+		b.probes++
+	}
+	return true
+}
+
+// done records the result of an execution that took d.
+func (b *Breaker) done(d time.Duration, err error) {
+	failed := b.cfg.IsFailure(err) || b.cfg.SlowThreshold > 0 && d > b.cfg.SlowThreshold
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	switch b.state {
+	case BreakerClosed:
+		if now.Sub(b.windowStart) > b.cfg.Window {
+			b.windowStart, b.requests, b.failures = now, 0, 0
+		}
+		b.requests++
+		if failed {
+			b.failures++
+		}
+		if b.requests >= b.cfg.MinRequests && float64(b.failures)/float64(b.requests) >= b.cfg.FailureRate {
+			b.open(now)
+		}
+	case BreakerHalfOpen:
+		if failed {
+			b.open(now)
+			return
+		}
+		b.successes++
+		if b.successes >= b.cfg.Probes {
+			b.setState(BreakerClosed)
+			b.windowStart, b.requests, b.failures = now, 0, 0
+		}
+	}
+}
+
+func (b *Breaker) open(now time.Time) {
+	b.setState(BreakerOpen)
+	b.openedAt = now
+}
+
+func (b *Breaker) setState(state BreakerState) {
+	if b.state == state {
+		return
+	}
+	from := b.state
+	b.state = state
+	if b.cfg.OnStateChange != nil {
+		b.cfg.OnStateChange(from, state)
+	}
+}
@@ -0,0 +1,115 @@
+package querier
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// flakyExecutor fails its executions with err and makes them take delay on clock.
+type flakyExecutor struct {
+	err   error
+	delay time.Duration
+	clock *time.Time
+}
+
+func (e *flakyExecutor) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	*e.clock = e.clock.Add(e.delay)
+	if e.err != nil {
+		return nil, e.err
+	}
+	return testResult{1}, nil
+}
+
+func (e *flakyExecutor) QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error) {
+	return nil, errors.New("not supported")
+}
+
+func TestBreaker(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ex := &flakyExecutor{clock: &clock}
+	var changes []string
+	b := NewBreaker(ex, BreakerConfig{
+		MinRequests:   4,
+		SlowThreshold: time.Second,
+		OnStateChange: func(from, to BreakerState) {
+			changes = append(changes, from.String()+" -> "+to.String())
+		},
+	})
+	b.now = func() time.Time { return clock }
+	exec := func() error {
+		return New(b, Default{}).Write("DELETE FROM session").Exec()
+	}
+
+	exec()
+	exec()
+	ex.err = errors.New("connection refused")
+	exec()
+	if b.State() != BreakerClosed {
+		t.Fatalf("State() = %v after 1 of 3 failed, want closed", b.State())
+	}
+	exec()
+	if b.State() != BreakerOpen {
+		t.Fatalf("State() = %v after 2 of 4 failed, want open", b.State())
+	}
+	ex.err = nil
+	if err := exec(); !errors.Is(err, ErrBreakerOpen) {
+		t.Fatalf("Exec() = %v, want ErrBreakerOpen", err)
+	}
+
+	// A slow probe fails and opens the breaker again.
+	clock = clock.Add(5 * time.Second)
+	if b.State() != BreakerHalfOpen {
+		t.Fatalf("State() = %v after OpenTimeout, want half-open", b.State())
+	}
+	ex.delay = 2 * time.Second
+	if err := exec(); err != nil {
+		t.Fatal(err)
+	}
+	if err := exec(); !errors.Is(err, ErrBreakerOpen) {
+		t.Fatalf("Exec() after failed probe = %v, want ErrBreakerOpen", err)
+	}
+
+	clock = clock.Add(5 * time.Second)
+	ex.delay = 0
+	if err := exec(); err != nil {
+		t.Fatal(err)
+	}
+	if b.State() != BreakerClosed {
+		t.Errorf("State() = %v after successful probe, want closed", b.State())
+	}
+	want := []string{"closed -> open", "open -> half-open", "half-open -> open", "open -> half-open", "half-open -> closed"}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("state changes = %v, want %v", changes, want)
+	}
+}
+
+func TestBreakerUniqueViolation(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ex := &flakyExecutor{err: errors.New("duplicate key"), clock: &clock}
+	b := NewBreaker(ex, BreakerConfig{MinRequests: 4, Dialect: uniqueDialect{}})
+	b.now = func() time.Time { return clock }
+	for i := 0; i < 8; i++ {
+		err := New(b, uniqueDialect{}).Write("INSERT INTO user (email) VALUES (?)", "a").Exec()
+		var unique *UniqueViolation
+		if !errors.As(err, &unique) {
+			t.Fatalf("Exec() = %v, want a *UniqueViolation", err)
+		}
+	}
+	if b.State() != BreakerClosed {
+		t.Errorf("State() = %v after unique violations, want closed", b.State())
+	}
+
+	// Without a Dialect that translates them, they're failures.
+	b = NewBreaker(ex, BreakerConfig{MinRequests: 4})
+	b.now = func() time.Time { return clock }
+	for i := 0; i < 4; i++ {
+		New(b, uniqueDialect{}).Write("INSERT INTO user (email) VALUES (?)", "a").Exec()
+	}
+	if b.State() != BreakerOpen {
+		t.Errorf("State() = %v without Dialect, want open", b.State())
+	}
+}
@@ -0,0 +1,51 @@
+package querier
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrDeadlineTooShort is returned, without executing the query, when the time its budget leaves before the deadline of
+// the context is shorter than MinBudget, see WithBudget.
+var ErrDeadlineTooShort = errors.New("querier: deadline too short")
+
+// MinBudget is the least time a query with a budget is executed with, see WithBudget.
+const MinBudget = 5 * time.Millisecond
+
+// WithBudget sets the fraction of the time left before the deadline of the context that the database gets to execute
+// the query, so the rest is reserved for processing its results, e.g. of a request:
+//
+//	err := q.WithBudget(0.8).FindContext(r.Context(), &users)
+//
+// With 800ms left the query is executed with a deadline in 640ms. When that is less than MinBudget the query isn't
+// executed and ErrDeadlineTooShort is returned. Contexts without a deadline aren't shaved. The budget is kept by Reset
+// and New. Panics when fraction isn't above 0 and at most 1.
+func (q *Q) WithBudget(fraction float64) *Q {
+	if fraction <= 0 || fraction > 1 {
+		panic("budget fraction must be above 0 and at most 1")
+	}
+	q.budget = fraction
+	return q
+}
+
+// budgetContext returns ctx with its deadline shaved to the budget of the query. Its cancel function is called by
+// runDeferred, after the rows have been closed.
+func (q *Q) budgetContext(ctx context.Context) (context.Context, error) {
+	if q.budget == 0 {
+		return ctx, nil
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return ctx, nil
+	}
+	budget := time.Duration(float64(time.Until(deadline)) * q.budget)
+	if budget < MinBudget {
+		return nil, ErrDeadlineTooShort
+	}
+	if q.cancelBudget != nil {
+		q.cancelBudget()
+	}
+	ctx, q.cancelBudget = context.WithTimeout(ctx, budget)
+	return ctx, nil
+}
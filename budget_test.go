@@ -0,0 +1,57 @@
+package querier
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+// deadlineExecutor records the deadline of the context of the last execution.
+type deadlineExecutor struct {
+	deadline time.Time
+	calls    int
+}
+
+func (e *deadlineExecutor) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	e.deadline, _ = ctx.Deadline()
+	e.calls++
+	return testResult{1}, nil
+}
+
+func (e *deadlineExecutor) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestWithBudget(t *testing.T) {
+	ex := &deadlineExecutor{}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	deadline, _ := ctx.Deadline()
+
+	q := New(ex, Default{}).WithBudget(0.5).Write("DELETE FROM user")
+	if err := q.ExecContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if left := time.Until(ex.deadline); ex.deadline.After(deadline) || left > 500*time.Millisecond || left < 400*time.Millisecond {
+		t.Errorf("query executed with %v left, want about 500ms", left)
+	}
+	if q.cancelBudget != nil {
+		t.Error("the context with the shaved deadline wasn't canceled")
+	}
+
+	// Without a deadline the context isn't shaved.
+	if err := q.ExecContext(context.Background()); err != nil || !ex.deadline.IsZero() {
+		t.Errorf("ExecContext() = %v with deadline %v, want no deadline", err, ex.deadline)
+	}
+
+	short, cancel := context.WithTimeout(context.Background(), MinBudget)
+	defer cancel()
+	if err := q.ExecContext(short); err != ErrDeadlineTooShort {
+		t.Errorf("ExecContext() = %v, want %v", err, ErrDeadlineTooShort)
+	}
+	if ex.calls != 2 {
+		t.Errorf("executed %d times, want 2", ex.calls)
+	}
+}
@@ -0,0 +1,45 @@
+package querier
+
+import "context"
+
+// Query is a built query, its SQL with the hints and comment of the Querier and its arguments, see Build. It doesn't
+// change when the Querier it was built by is written to or reset, so it can be cached, logged, passed across API
+// boundaries and executed repeatedly, also concurrently:
+//
+//	query, err := q.Write("SELECT * FROM user WHERE id = ?", id).Build()
+//	// ...
+//	err = db.New().WriteQuery(query).Find(&users)
+type Query struct {
+	SQL  string
+	Args []interface{}
+}
+
+// String returns the SQL of the query.
+func (query Query) String() string {
+	return query.SQL
+}
+
+// Build returns the query of q with a copy of its parameters. It returns the error that occurred while building the
+// query, like executing it does, e.g. a *ParamMismatchError. The trace comment of the context is added when the query
+// is executed, not built.
+func (q *Q) Build() (Query, error) {
+	if q.query.Len() == 0 {
+		return Query{}, errEmptyQuery
+	}
+	if err := q.checkBuild(); err != nil {
+		return Query{}, err
+	}
+	return Query{
+		SQL:  q.statement(context.Background()),
+		Args: append([]interface{}(nil), q.params...),
+	}, nil
+}
+
+// WriteQuery writes the SQL of query and adds its arguments as parameters, to execute it with q. The bind variables of
+// the SQL aren't renumbered, so a Query built for a Dialect with numbered bind variables, e.g. $1, must be written to
+// an empty Querier.
+func (q *Q) WriteQuery(query Query) *Q {
+	q.Write(query.SQL, query.Args...)
+	q.bindIndex += len(query.Args)
+	return q
+}
@@ -0,0 +1,55 @@
+package querier
+
+import (
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestBuild(t *testing.T) {
+	q := New(nil, Default{}).Write("SELECT * FROM user WHERE id = ?", 1).Comment("route=users")
+	query, err := q.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Query{SQL: "SELECT * FROM user WHERE id = ? /*route='users'*/", Args: []interface{}{1}}
+	if !reflect.DeepEqual(query, want) {
+		t.Errorf("Build() = %+v, want %+v", query, want)
+	}
+	// Changes to the Querier don't change the Query.
+	q.Reset().Write("DELETE FROM user WHERE id = ?", 2)
+	if !reflect.DeepEqual(query, want) {
+		t.Errorf("Query changed with its Querier to %+v", query)
+	}
+
+	if _, err = New(nil, Default{}).Write("SELECT * FROM user WHERE id = ?").Build(); !errors.Is(err, ErrParamMismatch) {
+		t.Errorf("Build() = %v, want %v", err, ErrParamMismatch)
+	}
+	if _, err = New(nil, Default{}).Build(); err == nil {
+		t.Error("Build() of an empty query succeeded")
+	}
+}
+
+func TestWriteQuery(t *testing.T) {
+	var args []driver.Value
+	sqlDB := openTestDB(t, func(query string, a []driver.Value) (*testRows, error) {
+		args = a
+		return &testRows{columns: []string{"id"}, values: [][]driver.Value{{int64(1)}}}, nil
+	})
+	query, err := New(nil, Default{}).Write("SELECT id FROM user WHERE name = ?", "foo").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		var users []struct {
+			ID int64 `db:"id"`
+		}
+		if err = New(sqlDB, Default{}).WriteQuery(query).Find(&users); err != nil || len(users) != 1 {
+			t.Fatalf("Find() = %v, %v", users, err)
+		}
+		if !reflect.DeepEqual(args, []driver.Value{"foo"}) {
+			t.Errorf("executed with %v, want [foo]", args)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package querier
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"sync"
+)
+
+// CapturedStatement is a statement captured by a CaptureExecutor.
+type CapturedStatement struct {
+	Query  string
+	Params []interface{}
+}
+
+// String returns the statement with its parameters interpolated, like Q.DebugString.
+func (s CapturedStatement) String() string {
+	return interpolate(nil, s.Query, s.Params)
+}
+
+// CaptureExecutor is an Executor that captures statements instead of executing them. Exec reports no rows affected
+// and no last insert id, Query returns an empty result set. It's meant for generating SQL scripts from existing code,
+// e.g. seeding or migrations, and for auditing which statements code runs:
+//
+//	ex := new(querier.CaptureExecutor)
+//	err := seed(querier.New(ex, d))
+//	ex.WriteScript(os.Stdout)
+//
+// Code that depends on the results of its queries sees empty results, so it may take other paths than it would on a
+// database. A CaptureExecutor is safe for concurrent use.
+type CaptureExecutor struct {
+	mu         sync.Mutex
+	statements []CapturedStatement
+}
+
+// ExecContext captures query and args.
+func (c *CaptureExecutor) ExecContext(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+	c.capture(query, args)
+	return captureResult{}, nil
+}
+
+// QueryContext captures query and args, it returns an empty result set.
+func (c *CaptureExecutor) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	c.capture(query, args)
+	return memQuery(ctx, nil)
+}
+
+func (c *CaptureExecutor) capture(query string, args []interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.statements = append(c.statements, CapturedStatement{
+		Query:  query,
+		Params: append([]interface{}(nil), args...),
+	})
+}
+
+// Statements returns the captured statements in the order they were executed.
+func (c *CaptureExecutor) Statements() []CapturedStatement {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]CapturedStatement(nil), c.statements...)
+}
+
+// Reset discards the captured statements.
+func (c *CaptureExecutor) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.statements = nil
+}
+
+// WriteScript writes the captured statements to w as a script, one statement per line terminated by a semicolon.
+// Parameters are interpolated like Q.DebugString, so sensitive parameters are redacted.
+func (c *CaptureExecutor) WriteScript(w io.Writer) error {
+	for _, s := range c.Statements() {
+		if _, err := io.WriteString(w, s.String()+";\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// captureResult is the result of a captured Exec.
+type captureResult struct{}
+
+func (captureResult) LastInsertId() (int64, error) { return 0, nil }
+func (captureResult) RowsAffected() (int64, error) { return 0, nil }
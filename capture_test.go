@@ -0,0 +1,40 @@
+package querier
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestCaptureExecutor(t *testing.T) {
+	ex := new(CaptureExecutor)
+	if err := New(ex, Default{}).Write("INSERT INTO user (name, password) VALUES (?, ?)", "o'neil", Sensitive("secret")).Exec(); err != nil {
+		t.Fatal(err)
+	}
+	var id int
+	if err := New(ex, Default{}).Write("SELECT id FROM user WHERE name = ?", "foo").Scan(&id); !errors.Is(err, ErrNoRecord) {
+		t.Errorf("Scan() = %v, want ErrNoRecord", err)
+	}
+	if err := New(ex, Default{}).Write("DELETE FROM session").Exec(); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := len(ex.Statements()); n != 3 {
+		t.Fatalf("captured %d statements, want 3", n)
+	}
+	var buf bytes.Buffer
+	if err := ex.WriteScript(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := "INSERT INTO user (name, password) VALUES ('o''neil', '[REDACTED]');\n" +
+		"SELECT id FROM user WHERE name = 'foo';\n" +
+		"DELETE FROM session;\n"
+	if buf.String() != want {
+		t.Errorf("WriteScript() = %q, want %q", buf.String(), want)
+	}
+
+	ex.Reset()
+	if n := len(ex.Statements()); n != 0 {
+		t.Errorf("captured %d statements after Reset, want 0", n)
+	}
+}
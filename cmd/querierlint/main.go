@@ -0,0 +1,14 @@
+// Command querierlint reports misuse of the querier query builder. Run it using go vet:
+//
+//	go vet -vettool=$(which querierlint) ./...
+package main
+
+import (
+	"github.com/semrekkers/querier/lint"
+
+	"golang.org/x/tools/go/analysis/unitchecker"
+)
+
+func main() {
+	unitchecker.Main(lint.Analyzer)
+}
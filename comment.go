@@ -0,0 +1,81 @@
+package querier
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+var traceParent func(context.Context) string
+
+// SetTraceParent sets fn, which returns the W3C traceparent of the trace in ctx, e.g. using OpenTelemetry. When set,
+// the traceparent is added to the comment of every executed query, so the query can be correlated with the trace.
+func SetTraceParent(fn func(ctx context.Context) string) {
+	traceParent = fn
+}
+
+// Comment adds tags, space separated key=value pairs, to the comment of the query, e.g.
+//
+//	q.Comment("service=checkout route=/pay")
+//
+// The comment is appended to the query when it's executed, in the sqlcommenter format:
+//
+//	SELECT ... /*route='%2Fpay',service='checkout'*/
+//
+// so the server's logs can be attributed to the application. A tag is replaced by a later tag with the same key.
+func (q *Q) Comment(tags string) *Q {
+	for _, tag := range strings.Fields(tags) {
+		kv := strings.SplitN(tag, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			q.fail(fmt.Errorf("invalid comment tag %q", tag))
+			continue
+		}
+		q.comment = append(q.comment, kv)
+	}
+	return q
+}
+
+// statement returns the query to execute in ctx, including its hints and comment.
+func (q *Q) statement(ctx context.Context) string {
+	var traceID string
+	if traceParent != nil {
+		traceID = traceParent(ctx)
+	}
+	query := q.placeHints(q.query.String())
+	if q.comment == nil && traceID == "" {
+		return query
+	}
+
+	tags := make(map[string]string, len(q.comment)+1)
+	for _, kv := range q.comment {
+		tags[kv[0]] = kv[1]
+	}
+	if traceID != "" {
+		tags["traceparent"] = traceID
+	}
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.WriteString(query)
+	buf.WriteString(" /*")
+	for i, key := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		fmt.Fprintf(&buf, "%s='%s'", commentEscape(key), commentEscape(tags[key]))
+	}
+	buf.WriteString("*/")
+	return buf.String()
+}
+
+// commentEscape URL encodes s, which also escapes the quotes and comment delimiters.
+func commentEscape(s string) string {
+	return strings.Replace(url.PathEscape(s), "*", "%2A", -1)
+}
@@ -0,0 +1,54 @@
+package querier
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestComment(t *testing.T) {
+	var ex testExecutor
+	err := New(&ex, Default{}).
+		Write("UPDATE cart SET paid = ?", true).
+		Comment("service=checkout route=/pay").
+		Comment("service=payments").
+		Exec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]interface{}{{"UPDATE cart SET paid = ? /*route='%2Fpay',service='payments'*/", true}}
+	if !reflect.DeepEqual(ex.queries, want) {
+		t.Errorf("executed %v, want %v", ex.queries, want)
+	}
+
+	if err = New(&ex, Default{}).Write("SELECT 1").Comment("invalid").Exec(); err == nil {
+		t.Error("Exec() with invalid comment tag returned no error")
+	}
+}
+
+func TestCommentTraceParent(t *testing.T) {
+	SetTraceParent(func(ctx context.Context) string {
+		id, _ := ctx.Value(ctxKey{}).(string)
+		return id
+	})
+	defer SetTraceParent(nil)
+
+	var ex testExecutor
+	ctx := context.WithValue(context.Background(), ctxKey{}, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	if err := New(&ex, Default{}).Write("DELETE FROM cart").Comment("it's=*/").ExecContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+	want := [][]interface{}{{"DELETE FROM cart /*it%27s='%2A%2F',traceparent='00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01'*/"}}
+	if !reflect.DeepEqual(ex.queries, want) {
+		t.Errorf("executed %v, want %v", ex.queries, want)
+	}
+
+	// Queries without a trace in their context are not commented.
+	ex.queries = nil
+	if err := New(&ex, Default{}).Write("DELETE FROM cart").Exec(); err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]interface{}{{"DELETE FROM cart"}}; !reflect.DeepEqual(ex.queries, want) {
+		t.Errorf("executed %v, want %v", ex.queries, want)
+	}
+}
@@ -0,0 +1,111 @@
+package querier
+
+import "strings"
+
+// InsertFromSelect writes an INSERT INTO table (columns) statement followed by the query of sub, whose parameters are
+// added to the Querier's parameters and whose numbered bind variables, e.g. $1, are renumbered to follow those of the
+// Querier. The column list is omitted if columns is empty.
+func (q *Q) InsertFromSelect(table string, columns []string, sub *Q) *Q {
+	if len(columns) > 0 {
+		q.Writef("INSERT INTO %s (%s)", q.Qualify(table), strings.Join(columns, FieldSep))
+	} else {
+		q.Writef("INSERT INTO %s", q.Qualify(table))
+	}
+	return q.writeQ(sub)
+}
+
+// writeQ writes the query of sub and adds its parameters. An error that occurred while building sub is kept.
+func (q *Q) writeQ(sub *Q) *Q {
+	q.writeSep()
+	q.writeSub("", sub, "", true)
+	return q
+}
+
+// writeSub writes the query of sub between prefix and suffix, and adds its parameters, tracked bind variables and
+// hints. If renumber is true, the numbered bind variables of sub, e.g. $1, are renumbered to follow those of q,
+// otherwise sub must continue the numbering of q, like the Querier of Group. An error that occurred while building sub
+// is kept.
+func (q *Q) writeSub(prefix string, sub *Q, suffix string, renumber bool) {
+	if sub.buildErr != nil {
+		q.fail(sub.buildErr)
+	}
+	text, bindVars, bindIndex := sub.String(), sub.bindVars, q.bindIndex+sub.bindIndex
+	if renumber {
+		var next int
+		text, bindVars, next = q.renumber(text, bindVars, q.bindIndex)
+		if next > bindIndex {
+			bindIndex = next
+		}
+	}
+	q.query.WriteString(prefix)
+	start, offset := len(q.params), q.query.Len()
+	q.query.WriteString(text)
+	q.query.WriteString(suffix)
+	for _, pos := range bindVars {
+		q.bindVars = append(q.bindVars, offset+pos)
+	}
+	q.untracked = q.untracked || sub.untracked
+	q.bindIndex = bindIndex
+	q.hints = append(q.hints, sub.hints...)
+	q.params = append(q.params, sub.params...)
+	q.record(SegmentQuery, text, nil, start)
+}
+
+// Group writes the writes of fn to g enclosed in parentheses, e.g. to nest conditions:
+//
+//	q.Write("WHERE a = ? AND", a).Group(func(g *querier.Q) {
+//		g.Write("b = ?", b).Write("OR c = ?", c)
+//	})
+//
+// writes WHERE a = ? AND (b = ? OR c = ?). g continues the numbering of the bind variables.
+func (q *Q) Group(fn func(g *Q)) *Q {
+	g := q.New()
+	g.sep, g.strict, g.bindIndex = q.sep, q.strict, q.bindIndex
+	fn(g)
+	q.writeSep()
+	bindIndex := g.bindIndex
+	q.writeSub("(", g, ")", false)
+	q.bindIndex = bindIndex
+	return q
+}
+
+// Union combines queries with UNION, removing duplicate rows. Each query is enclosed in parentheses, so clauses written
+// to the returned Querier, like ORDER BY and LIMIT, apply to the combined result, e.g.
+//
+//	querier.Union(q1, q2).Write("ORDER BY name LIMIT ?", 10)
+//
+// The numbered bind variables of the queries, e.g. $1, are renumbered to follow each other. The returned Querier is
+// created by New of the first query. Panics when no queries are given.
+func Union(queries ...*Q) *Q {
+	return combine("UNION", queries)
+}
+
+// UnionAll combines queries with UNION ALL, keeping duplicate rows. See Union.
+func UnionAll(queries ...*Q) *Q {
+	return combine("UNION ALL", queries)
+}
+
+// Intersect combines queries with INTERSECT. See Union.
+func Intersect(queries ...*Q) *Q {
+	return combine("INTERSECT", queries)
+}
+
+// Except combines queries with EXCEPT. See Union.
+func Except(queries ...*Q) *Q {
+	return combine("EXCEPT", queries)
+}
+
+func combine(op string, queries []*Q) *Q {
+	if len(queries) == 0 {
+		panic("no queries to combine")
+	}
+	q := queries[0].New()
+	for i, sub := range queries {
+		if i > 0 {
+			q.Write(op)
+		}
+		q.writeSep()
+		q.writeSub("(", sub, ")", true)
+	}
+	return q
+}
@@ -0,0 +1,113 @@
+package querier
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInsertFromSelect(t *testing.T) {
+	sub := New(nil, Default{}).Write("SELECT id, name FROM user WHERE active = ?", true)
+	q := New(nil, Default{}).InsertFromSelect("archive", []string{"id", "name"}, sub)
+
+	if want := "INSERT INTO archive (id, name) SELECT id, name FROM user WHERE active = ?"; q.String() != want {
+		t.Errorf("String() = %q, want %q", q.String(), want)
+	}
+	if want := []interface{}{true}; !reflect.DeepEqual(q.Params(), want) {
+		t.Errorf("Params() = %v, want %v", q.Params(), want)
+	}
+
+	q = New(nil, Default{}).InsertFromSelect("archive", nil, sub)
+	if want := "INSERT INTO archive SELECT id, name FROM user WHERE active = ?"; q.String() != want {
+		t.Errorf("String() = %q, want %q", q.String(), want)
+	}
+
+	sub = New(nil, numberedDialect{}).WriteValues("SELECT id FROM user WHERE z = {bindVar}", "", 5)
+	q = New(nil, numberedDialect{}).WriteValues("WITH x AS (SELECT {bindVar})", "", 9).
+		InsertFromSelect("archive", []string{"id"}, sub).
+		WriteValues("RETURNING {bindVar}", "", 1)
+	if want := "WITH x AS (SELECT $1) INSERT INTO archive (id) SELECT id FROM user WHERE z = $2 RETURNING $3"; q.String() != want {
+		t.Errorf("String() = %q, want %q", q.String(), want)
+	}
+	if want := []interface{}{9, 5, 1}; !reflect.DeepEqual(q.Params(), want) {
+		t.Errorf("Params() = %v, want %v", q.Params(), want)
+	}
+	if err := q.checkBuild(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCombine(t *testing.T) {
+	q1 := New(nil, Default{}).Write("SELECT name FROM user WHERE id = ?", 1)
+	q2 := New(nil, Default{}).Write("SELECT name FROM admin WHERE id = ?", 2)
+
+	tests := []struct {
+		q    *Q
+		want string
+	}{
+		{Union(q1, q2), "(SELECT name FROM user WHERE id = ?) UNION (SELECT name FROM admin WHERE id = ?)"},
+		{UnionAll(q1, q2), "(SELECT name FROM user WHERE id = ?) UNION ALL (SELECT name FROM admin WHERE id = ?)"},
+		{Intersect(q1, q2), "(SELECT name FROM user WHERE id = ?) INTERSECT (SELECT name FROM admin WHERE id = ?)"},
+		{Except(q1, q2), "(SELECT name FROM user WHERE id = ?) EXCEPT (SELECT name FROM admin WHERE id = ?)"},
+	}
+	for _, tt := range tests {
+		tt.q.Write("ORDER BY name LIMIT ?", 10)
+		if want := tt.want + " ORDER BY name LIMIT ?"; tt.q.String() != want {
+			t.Errorf("String() = %q, want %q", tt.q.String(), want)
+		}
+		if want := []interface{}{1, 2, 10}; !reflect.DeepEqual(tt.q.Params(), want) {
+			t.Errorf("Params() = %v, want %v", tt.q.Params(), want)
+		}
+	}
+}
+
+func TestCombineNumbered(t *testing.T) {
+	q1 := New(nil, numberedDialect{}).WriteValues("SELECT name FROM user WHERE x = {bindVar}", "", 1)
+	q2 := New(nil, numberedDialect{}).Write("SELECT name FROM admin WHERE y = $1 AND z = $2 AND note <> '$1'", 2, 3)
+	q := Union(q1, q2).WriteValues("LIMIT {bindVar}", "", 10)
+
+	want := "(SELECT name FROM user WHERE x = $1) UNION (SELECT name FROM admin WHERE y = $2 AND z = $3 AND note <> '$1') LIMIT $4"
+	if q.String() != want {
+		t.Errorf("String() = %q, want %q", q.String(), want)
+	}
+	if want := []interface{}{1, 2, 3, 10}; !reflect.DeepEqual(q.Params(), want) {
+		t.Errorf("Params() = %v, want %v", q.Params(), want)
+	}
+
+	// The tracked bind variables move with the renumbered text.
+	q1 = New(nil, numberedDialect{}).WriteValues("SELECT {bindVar}", "", 0)
+	q2 = New(nil, numberedDialect{}).Write("SELECT").WriteValues("{bindVar}", ", ", 1, 2, 3, 4, 5, 6, 7, 8, 9).
+		WriteValues("WHERE x = {bindVar}", "", 10)
+	q = UnionAll(q1, q2)
+	if want := "(SELECT $1) UNION ALL (SELECT $2, $3, $4, $5, $6, $7, $8, $9, $10 WHERE x = $11)"; q.String() != want {
+		t.Errorf("String() = %q, want %q", q.String(), want)
+	}
+	if err := q.checkBuild(); err != nil {
+		t.Error(err)
+	}
+	for _, pos := range q.bindVars {
+		if q.String()[pos] != '$' {
+			t.Errorf("bind variable at %d is %q", pos, q.String()[pos:])
+		}
+	}
+}
+
+func TestGroup(t *testing.T) {
+	q := New(nil, Default{}).Write("SELECT * FROM user WHERE a = ? AND", 1).Group(func(g *Q) {
+		g.Write("b = ?", 2).Write("OR").Group(func(g *Q) {
+			g.Write("c = ? AND d = ?", 3, 4)
+		})
+	}).Write("LIMIT ?", 10)
+	if want := "SELECT * FROM user WHERE a = ? AND (b = ? OR (c = ? AND d = ?)) LIMIT ?"; q.String() != want {
+		t.Errorf("String() = %q, want %q", q.String(), want)
+	}
+	if want := []interface{}{1, 2, 3, 4, 10}; !reflect.DeepEqual(q.Params(), want) {
+		t.Errorf("Params() = %v, want %v", q.Params(), want)
+	}
+
+	q = New(nil, numberedDialect{}).WriteValues("WHERE a = {bindVar} AND", "", 1).Group(func(g *Q) {
+		g.WriteValues("b = {bindVar} OR", "", 2).WriteValues("c = {bindVar}", "", 3)
+	}).WriteValues("LIMIT {bindVar}", "", 10)
+	if want := "WHERE a = $1 AND (b = $2 OR c = $3) LIMIT $4"; q.String() != want {
+		t.Errorf("String() = %q, want %q", q.String(), want)
+	}
+}
@@ -0,0 +1,141 @@
+package querier
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"sync"
+)
+
+// scanBatchSize is the number of rows findConcurrent decodes at once.
+const scanBatchSize = 64
+
+// SetScanWorkers sets the number of goroutines that decode the rows of Find. With n > 1, the rows are read from the
+// driver in order and decoded into their structs by n goroutines, in batches: the mapping of the struct to the result
+// columns, the conversions of database/sql and those of the Coercion, which dominate the scanning of wide rows with
+// e.g. times in text. The conversions remain those of database/sql. Defaults to 0, which decodes the rows while
+// reading them.
+func (q *Q) SetScanWorkers(n int) *Q {
+	q.scanWorkers = n
+	return q
+}
+
+// scanBatch is a batch of rows read from the driver, decoded into elements by a worker.
+type scanBatch struct {
+	values   [][]driver.Value
+	elements []reflect.Value
+	err      error
+	done     chan struct{}
+}
+
+// findConcurrent appends the rows to slice v, they're decoded by workers goroutines.
+func findConcurrent(ctx context.Context, rows *sql.Rows, v reflect.Value, elemType reflect.Type, elemIsPtr bool,
+	columns []string, columnTypes []*sql.ColumnType, c *Coercion, workers int) error {
+	var (
+		batches  []*scanBatch
+		pending  = make(chan *scanBatch, workers)
+		failed   = make(chan struct{})
+		failOnce sync.Once
+		wg       sync.WaitGroup
+	)
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for batch := range pending {
+				batch.err = decodeBatch(ctx, batch, elemType, columns, columnTypes, c)
+				if batch.err != nil {
+					failOnce.Do(func() { close(failed) })
+				}
+				close(batch.done)
+			}
+		}()
+	}
+	defer wg.Wait()
+
+	err := readBatches(rows, len(columns), pending, failed, &batches)
+	close(pending)
+	for _, batch := range batches {
+		<-batch.done
+		if batch.err != nil {
+			return batch.err
+		}
+		for _, element := range batch.elements {
+			if elemIsPtr {
+				element = element.Addr()
+			}
+			v.Set(reflect.Append(v, element))
+		}
+	}
+	return err
+}
+
+// readBatches reads the rows as driver values and sends them to the workers in batches, until the rows are done or a
+// batch failed. The batches are appended to batches in order.
+func readBatches(rows *sql.Rows, n int, pending chan<- *scanBatch, failed <-chan struct{}, batches *[]*scanBatch) error {
+	batch := &scanBatch{done: make(chan struct{})}
+	dest := make([]interface{}, n)
+	for rows.Next() {
+		values := make([]interface{}, n)
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		// Scanning into interfaces copies the bytes of the driver.
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+		row := make([]driver.Value, n)
+		for i, value := range values {
+			row[i] = value
+		}
+		batch.values = append(batch.values, row)
+		if len(batch.values) < scanBatchSize {
+			continue
+		}
+		select {
+		case pending <- batch:
+		case <-failed:
+			return nil
+		}
+		*batches = append(*batches, batch)
+		batch = &scanBatch{done: make(chan struct{})}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(batch.values) > 0 {
+		select {
+		case pending <- batch:
+			*batches = append(*batches, batch)
+		case <-failed:
+		}
+	}
+	return nil
+}
+
+// decodeBatch decodes the values of batch into new elements of elemType, by scanning them from a result set in memory.
+func decodeBatch(ctx context.Context, batch *scanBatch, elemType reflect.Type, columns []string,
+	columnTypes []*sql.ColumnType, c *Coercion) error {
+	rows, err := memQuery(ctx, &memResultSet{columns: columns, values: batch.values})
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	var (
+		valueMap ValueMap
+		fields   []interface{}
+	)
+	batch.elements = make([]reflect.Value, 0, len(batch.values))
+	for rows.Next() {
+		element := reflect.New(elemType).Elem()
+		valueMap = makeValueMap(element, valueMap, "")
+		fields = valueMap.MapToColumns(columns, fields[:0])
+		decodeColumns(c, columnTypes, fields)
+		if err = rows.Scan(fields...); err != nil {
+			return err
+		}
+		batch.elements = append(batch.elements, element)
+	}
+	return rows.Err()
+}
@@ -0,0 +1,122 @@
+package querier
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFindConcurrent(t *testing.T) {
+	type user struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+	rows := make([][]driver.Value, 500)
+	for i := range rows {
+		rows[i] = []driver.Value{int64(i), []byte("user")}
+	}
+	db := openTestDB(t, func(string, []driver.Value) (*testRows, error) {
+		return &testRows{columns: []string{"id", "name"}, values: rows}, nil
+	})
+	defer db.Close()
+
+	var want []user
+	if err := New(db, Default{}).Write("SELECT * FROM user").Find(&want); err != nil {
+		t.Fatal(err)
+	}
+	var got []*user
+	if err := New(db, Default{}).SetScanWorkers(4).Write("SELECT * FROM user").Find(&got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(got), len(want))
+	}
+	for i := range got {
+		if !reflect.DeepEqual(*got[i], want[i]) {
+			t.Fatalf("row %d: got %+v, want %+v", i, *got[i], want[i])
+		}
+	}
+}
+
+func TestFindConcurrentErrors(t *testing.T) {
+	type user struct {
+		ID int64 `db:"id"`
+	}
+	errBroken := errors.New("connection broken")
+	tests := []struct {
+		name    string
+		rows    *testRows
+		wantErr string
+	}{
+		{"truncated", &testRows{columns: []string{"id"}, values: testIDs(200), err: errBroken}, errBroken.Error()},
+		{"decode", &testRows{columns: []string{"id"}, values: append(testIDs(150), []driver.Value{[]byte("x")})},
+			"converting driver.Value type []uint8"},
+	}
+	for _, tt := range tests {
+		for _, workers := range []int{0, 4} {
+			db := openTestDB(t, func(string, []driver.Value) (*testRows, error) {
+				rows := *tt.rows
+				return &rows, nil
+			})
+			var got []user
+			err := New(db, Default{}).SetScanWorkers(workers).Write("SELECT * FROM user").Find(&got)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s with %d workers: Find() = %v, want %q", tt.name, workers, err, tt.wantErr)
+			}
+			db.Close()
+		}
+	}
+}
+
+func testIDs(n int) [][]driver.Value {
+	rows := make([][]driver.Value, n)
+	for i := range rows {
+		rows[i] = []driver.Value{int64(i)}
+	}
+	return rows
+}
+
+type benchReading struct {
+	ID        int64     `db:"id"`
+	Sensor    string    `db:"sensor"`
+	Value     float64   `db:"value"`
+	Count     int64     `db:"count"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+	ReadAt    time.Time `db:"read_at"`
+}
+
+// BenchmarkFind compares Find with and without scan workers, for rows with times and numbers in text that are
+// coerced.
+func BenchmarkFind(b *testing.B) {
+	rows := make([][]driver.Value, 1000)
+	for i := range rows {
+		rows[i] = []driver.Value{[]byte(strconv.Itoa(i)), []byte("sensor"), []byte("21.5"), []byte("1e+03"),
+			[]byte("2024-01-02 15:04:05"), []byte("2024-01-02T15:04:05.123456Z"), []byte("2024-01-02 15:04:05+02:00")}
+	}
+	db := openTestDB(b, func(string, []driver.Value) (*testRows, error) {
+		return &testRows{
+			columns: []string{"id", "sensor", "value", "count", "created_at", "updated_at", "read_at"},
+			values:  rows,
+		}, nil
+	})
+	defer db.Close()
+	for _, workers := range []int{0, 4} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				var readings []benchReading
+				err := New(db, Default{}).SetCoercion(&Coercion{Numbers: true}).SetScanWorkers(workers).
+					Write("SELECT * FROM reading").Find(&readings)
+				if err != nil || len(readings) != len(rows) {
+					b.Fatal(len(readings), err)
+				}
+			}
+		})
+	}
+}
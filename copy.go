@@ -0,0 +1,284 @@
+package querier
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// CopyExporter can be implemented by a Dialect to stream the result of a query in the text format of COPY, e.g. with
+// COPY (query) TO STDOUT (HEADER) of PostgreSQL, for FindCopy. No Dialect of this module implements it. COPY TO STDOUT
+// can't be run through the methods of an Executor, so an implementation needs the connection of its driver, e.g.
+// pgconn's CopyTo on the connection of (*sql.Conn).Raw. Only the text format is decoded, not the binary format.
+type CopyExporter interface {
+	// CopyTo executes query, which has its parameters interpolated, using ex and writes its result to w: a header line
+	// with the names of the columns, then a line per row. Columns are separated by tabs, NULL is \N and tabs, newlines
+	// and backslashes in values are escaped with a backslash.
+	CopyTo(ctx context.Context, ex Executor, query string, w io.Writer) error
+}
+
+// copyTimeLayouts are the layouts of times in the text format of COPY, tried after the layouts of the Coercion.
+var copyTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999999Z07",
+	"2006-01-02 15:04:05.999999999Z07:00:00",
+	"15:04:05.999999999",
+}
+
+// FindCopy is like Find, but streams the result with CopyTo of the Dialect, which skips the row-at-a-time protocol of
+// the driver, e.g. for very large exports. Values are decoded from text: into sql.Scanner, strings, byte slices, bools,
+// numbers, times, pointers to them and the types of the Coercion. It's Find if the Dialect isn't a CopyExporter.
+// Parameters are interpolated into the query, see FormatLiteral.
+func (q *Q) FindCopy(i interface{}) error {
+	return q.FindCopyContext(q.baseContext(), i)
+}
+
+func (q *Q) FindCopyContext(ctx context.Context, i interface{}) (err error) {
+	exporter, ok := q.d.(CopyExporter)
+	if !ok {
+		return q.FindContext(ctx, i)
+	}
+	if q.query.Len() == 0 {
+		panic(errEmptyQuery)
+	}
+	v, elemType, elemIsPtr := extractStructSliceInfo(i)
+	q.ctx = ctx
+	defer q.runDeferred(&err)
+	if err := q.checkBuild(); err != nil {
+		return q.returnErr(err)
+	}
+	done, err := q.track()
+	if err != nil {
+		return q.returnErr(err)
+	}
+	defer done()
+
+	ctx, err = q.budgetContext(ctx)
+	if err != nil {
+		return q.returnErr(err)
+	}
+	query := q.statement(ctx)
+	start := time.Now()
+	r, w := io.Pipe()
+	copyErr := make(chan error, 1)
+	go func() {
+		err := exporter.CopyTo(ctx, q.ex, interpolate(q.d, query, q.params), w)
+		w.CloseWithError(err)
+		copyErr <- err
+	}()
+	err = q.decodeCopy(r, v, elemType, elemIsPtr)
+	// Stop the export if decoding failed.
+	r.CloseWithError(err)
+	// The exporter fails with the error of the pipe if decoding failed, keep the decoding error then.
+	if cerr := <-copyErr; err == nil {
+		err = cerr
+	}
+	err = q.translateError(err)
+	q.logQuery(ctx, query, start, err)
+	return q.returnErr(err)
+}
+
+// decodeCopy decodes the text format of COPY from r into the slice v.
+func (q *Q) decodeCopy(r io.Reader, v reflect.Value, elemType reflect.Type, elemIsPtr bool) error {
+	c := Coercion{Numbers: true}
+	if q.coercion != nil {
+		c = *q.coercion
+		c.Numbers = true
+	}
+	if c.TimeLayouts == nil {
+		c.TimeLayouts = DefaultTimeLayouts
+	}
+	c.TimeLayouts = append(c.TimeLayouts[:len(c.TimeLayouts):len(c.TimeLayouts)], copyTimeLayouts...)
+
+	br := bufio.NewReader(r)
+	header, err := readCopyLine(br)
+	if err == io.EOF {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	columns := make([]string, len(header))
+	for i, column := range header {
+		columns[i] = string(column)
+	}
+	valueMap := makeValueMap(reflect.New(elemType).Elem(), nil, "")
+	columns = matchColumns(valueMap, columns, q.columnMatching)
+	if err = checkColumnNames(valueMap, columns); err != nil {
+		return err
+	}
+
+	fields := getScanSlice()
+	defer putScanSlice(fields)
+	for {
+		values, err := readCopyLine(br)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if len(values) != len(columns) {
+			return fmt.Errorf("copy row %d has %d columns, want %d", v.Len(), len(values), len(columns))
+		}
+		element := reflect.New(elemType).Elem()
+		valueMap = makeValueMap(element, valueMap, "")
+		*fields = valueMap.MapToColumns(columns, (*fields)[:0])
+		for i, dest := range *fields {
+			if err = scanText(&c, dest, values[i]); err != nil {
+				return fmt.Errorf("copy column %s: %v", columns[i], err)
+			}
+		}
+		if elemIsPtr {
+			element = element.Addr()
+		}
+		v.Set(reflect.Append(v, element))
+	}
+}
+
+// readCopyLine reads a line of the text format of COPY and returns its unescaped values, nil for NULL.
+func readCopyLine(r *bufio.Reader) ([][]byte, error) {
+	line, err := r.ReadBytes('\n')
+	if err == io.EOF && len(line) > 0 {
+		err = nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	line = bytes.TrimSuffix(line, []byte{'\n'})
+	if bytes.Equal(line, []byte(`\.`)) {
+		// The end-of-data marker.
+		return nil, io.EOF
+	}
+	var values [][]byte
+	for _, field := range bytes.Split(line, []byte{'\t'}) {
+		if bytes.Equal(field, []byte(`\N`)) {
+			values = append(values, nil)
+			continue
+		}
+		values = append(values, unescapeCopy(field))
+	}
+	return values, nil
+}
+
+// unescapeCopy returns field without the backslash escapes of COPY. The result is never nil.
+func unescapeCopy(field []byte) []byte {
+	if bytes.IndexByte(field, '\\') < 0 {
+		return append([]byte{}, field...)
+	}
+	out := make([]byte, 0, len(field))
+	for i := 0; i < len(field); i++ {
+		c := field[i]
+		if c != '\\' || i+1 == len(field) {
+			out = append(out, c)
+			continue
+		}
+		i++
+		switch c = field[i]; c {
+		case 'b':
+			out = append(out, '\b')
+		case 'f':
+			out = append(out, '\f')
+		case 'n':
+			out = append(out, '\n')
+		case 'r':
+			out = append(out, '\r')
+		case 't':
+			out = append(out, '\t')
+		case 'v':
+			out = append(out, '\v')
+		case 'x':
+			// \x followed by one or two hex digits.
+			n := 0
+			for n < 2 && i+1+n < len(field) && isHexDigit(field[i+1+n]) {
+				n++
+			}
+			if n == 0 {
+				out = append(out, c)
+				break
+			}
+			b, _ := strconv.ParseUint(string(field[i+1:i+1+n]), 16, 8)
+			out = append(out, byte(b))
+			i += n
+		case '0', '1', '2', '3', '4', '5', '6', '7':
+			// Up to three octal digits.
+			n := 1
+			for n < 3 && i+n < len(field) && field[i+n] >= '0' && field[i+n] <= '7' {
+				n++
+			}
+			b, _ := strconv.ParseUint(string(field[i:i+n]), 8, 8)
+			out = append(out, byte(b))
+			i += n - 1
+		default:
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func isHexDigit(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+// scanText scans the text value src, nil for NULL, into dest, a pointer.
+func scanText(c *Coercion, dest interface{}, src []byte) error {
+	var value interface{}
+	if src != nil {
+		value = src
+	}
+	if s, ok := dest.(sql.Scanner); ok {
+		return s.Scan(value)
+	}
+	v := reflect.ValueOf(dest).Elem()
+	if c.coerces(v.Type()) {
+		return (&coercingScanner{c: c, dst: v}).Scan(value)
+	}
+	if v.Kind() == reflect.Ptr {
+		if src == nil {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		elem := reflect.New(v.Type().Elem())
+		if err := scanText(c, elem.Interface(), src); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+	if src == nil {
+		if v.Kind() == reflect.Interface || v.Kind() == reflect.Slice {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		return fmt.Errorf("converting NULL to %s is unsupported", v.Type())
+	}
+	switch {
+	case v.Kind() == reflect.String:
+		v.SetString(string(src))
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(string(src))
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+		// bytea is hex encoded.
+		if bytes.HasPrefix(src, []byte(`\x`)) {
+			b := make([]byte, hex.DecodedLen(len(src)-2))
+			if _, err := hex.Decode(b, src[2:]); err != nil {
+				return err
+			}
+			src = b
+		}
+		v.SetBytes(src)
+	case v.Kind() == reflect.Interface && v.NumMethod() == 0:
+		v.Set(reflect.ValueOf(string(src)))
+	default:
+		return fmt.Errorf("unsupported Scan, storing text into type %s", v.Type())
+	}
+	return nil
+}
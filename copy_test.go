@@ -0,0 +1,115 @@
+package querier
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// copyDialect exports the text in out, and records the query.
+type copyDialect struct {
+	Default
+	out   string
+	query *string
+}
+
+func (d copyDialect) CopyTo(_ context.Context, _ Executor, query string, w io.Writer) error {
+	*d.query = query
+	if _, err := io.WriteString(w, d.out); err != nil {
+		return fmt.Errorf("export: %w", err)
+	}
+	return nil
+}
+
+func TestFindCopy(t *testing.T) {
+	type row struct {
+		ID      int64      `db:"id"`
+		Name    string     `db:"name"`
+		Nick    *string    `db:"nick"`
+		Active  bool       `db:"active"`
+		Data    []byte     `db:"data"`
+		Created time.Time  `db:"created"`
+		Deleted *time.Time `db:"deleted"`
+	}
+	var query string
+	d := copyDialect{
+		out: "id\tname\tnick\tactive\tdata\tcreated\tdeleted\n" +
+			"1\tJohn\\tDoe\t\\N\tt\t\\\\x0102\t2024-01-02 03:04:05+01\t\\N\n" +
+			"2\tmulti\\nline\tjd\tf\t\\N\t2024-01-02 03:04:05\t2024-01-03 00:00:00\n",
+		query: &query,
+	}
+	var rows []row
+	if err := New(nil, d).Write("SELECT * FROM user WHERE name <> ?", "it's").FindCopy(&rows); err != nil {
+		t.Fatal(err)
+	}
+	if want := "SELECT * FROM user WHERE name <> 'it''s'"; query != want {
+		t.Errorf("query = %q, want %q", query, want)
+	}
+	nick := "jd"
+	deleted := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+	want := []row{
+		{ID: 1, Name: "John\tDoe", Active: true, Data: []byte{1, 2}, Created: time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("", 3600))},
+		{ID: 2, Name: "multi\nline", Nick: &nick, Created: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), Deleted: &deleted},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("FindCopy() = %+v, want %+v", rows, want)
+	}
+	for i := range want {
+		if !rows[i].Created.Equal(want[i].Created) {
+			t.Errorf("row %d created = %v, want %v", i, rows[i].Created, want[i].Created)
+		}
+		rows[i].Created = want[i].Created
+		if !reflect.DeepEqual(rows[i], want[i]) {
+			t.Errorf("row %d = %+v, want %+v", i, rows[i], want[i])
+		}
+	}
+
+	d.out = "id\tname\nx\ty\n"
+	if err := New(nil, d).Write("SELECT id, name FROM user").FindCopy(&rows); err == nil {
+		t.Error("FindCopy() of an invalid integer succeeded")
+	}
+	d.out = "id\tname\n1\n"
+	if err := New(nil, d).Write("SELECT id, name FROM user").FindCopy(&rows); err == nil {
+		t.Error("FindCopy() of a row with missing columns succeeded")
+	}
+	// The export fails when decoding fails, the decoding error is returned.
+	d.out = "id\tname\nx\ty\n" + strings.Repeat("1\ty\n", 10000)
+	err := New(nil, d).Write("SELECT id, name FROM user").FindCopy(&rows)
+	if err == nil || !strings.HasPrefix(err.Error(), "copy column id") {
+		t.Errorf("FindCopy() = %v, want the decoding error", err)
+	}
+}
+
+func TestFindCopyFallback(t *testing.T) {
+	sqlDB := openTestDB(t, func(string, []driver.Value) (*testRows, error) {
+		return &testRows{columns: []string{"id"}, values: [][]driver.Value{{int64(1)}}}, nil
+	})
+	var rows []struct {
+		ID int64 `db:"id"`
+	}
+	if err := New(sqlDB, Default{}).Write("SELECT id FROM user").FindCopy(&rows); err != nil || len(rows) != 1 || rows[0].ID != 1 {
+		t.Errorf("FindCopy() = %v, %v, want the rows of Find", rows, err)
+	}
+}
+
+func TestUnescapeCopy(t *testing.T) {
+	for in, want := range map[string]string{
+		`plain`:       "plain",
+		`a\tb\\c`:     "a\tb\\c",
+		`\101\x42\x4`: "AB\x04",
+		`trailing\`:   `trailing\`,
+		`\q`:          "q",
+	} {
+		if got := string(unescapeCopy([]byte(in))); got != want {
+			t.Errorf("unescapeCopy(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if err := scanText(&Coercion{}, new(string), nil); err == nil {
+		t.Error("scanText() of NULL into a string succeeded")
+	}
+}
@@ -0,0 +1,66 @@
+package querier
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ScanAll executes the query in batches of batchSize rows ordered by keyColumn and calls fn for every row, like
+// ForEach. Every batch is a new query which continues after the last seen key:
+//
+//	SELECT * FROM (<query>) scan_all WHERE <keyColumn> > ? ORDER BY <keyColumn> LIMIT <batchSize>
+//
+// This processes a huge table without keeping one cursor or transaction open for the whole scan. keyColumn must be a
+// unique column of the result, it's validated and quoted like WriteIdent. The Querier itself is not executed, so its deferred functions don't run.
+func (q *Q) ScanAll(ctx context.Context, keyColumn string, batchSize int, fn ScanFunc) error {
+	if batchSize < 1 {
+		panic("batchSize must be positive")
+	}
+	var (
+		last     interface{}
+		keyIndex = -1
+		dest     []interface{}
+		ignored  interface{}
+	)
+	for {
+		page := q.New()
+		page.writeSub("SELECT * FROM (", q, ") scan_all", true)
+		if keyIndex >= 0 {
+			page.Write("WHERE").WriteIdent(keyColumn).WriteValues("> {bindVar}", "", last)
+		}
+		page.Write("ORDER BY").WriteIdent(keyColumn).Writef("LIMIT %d", batchSize)
+
+		n, stopped := 0, false
+		err := page.ForEachContext(ctx, func(p *Q, rows *sql.Rows) error {
+			n++
+			if err := fn(p, rows); err != nil {
+				stopped = errors.Is(err, ErrStopIteration)
+				return err
+			}
+			if dest == nil {
+				columns, err := rows.Columns()
+				if err != nil {
+					return err
+				}
+				dest = make([]interface{}, len(columns))
+				for i, column := range columns {
+					dest[i] = &ignored
+					if column == keyColumn {
+						keyIndex = i
+					}
+				}
+				if keyIndex < 0 {
+					return fmt.Errorf("querier: key column %s is not in the result", keyColumn)
+				}
+				dest[keyIndex] = &last
+			}
+			// Rows may be scanned again, this reads the key of the row.
+			return rows.Scan(dest...)
+		})
+		if err != nil || stopped || n < batchSize {
+			return err
+		}
+	}
+}
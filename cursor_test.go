@@ -0,0 +1,75 @@
+package querier
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestScanAll(t *testing.T) {
+	var queries []string
+	db := openTestDB(t, func(query string, args []driver.Value) (*testRows, error) {
+		queries = append(queries, query)
+		var after int64
+		if len(args) > 1 {
+			after = args[1].(int64)
+		}
+		rows := &testRows{columns: []string{"id", "name"}}
+		for id := after + 1; id <= 5 && len(rows.values) < 2; id++ {
+			rows.values = append(rows.values, []driver.Value{id, "user"})
+		}
+		return rows, nil
+	})
+	defer db.Close()
+
+	var ids []int64
+	err := New(db, Default{}).Write("SELECT id, name FROM user WHERE active = ?", true).
+		ScanAll(context.Background(), "id", 2, func(_ *Q, rows *sql.Rows) error {
+			var (
+				id   int64
+				name string
+			)
+			err := rows.Scan(&id, &name)
+			ids = append(ids, id)
+			return err
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ids, []int64{1, 2, 3, 4, 5}) {
+		t.Errorf("got ids %v", ids)
+	}
+	wantQueries := []string{
+		"SELECT * FROM (SELECT id, name FROM user WHERE active = ?) scan_all ORDER BY \"id\" LIMIT 2",
+		"SELECT * FROM (SELECT id, name FROM user WHERE active = ?) scan_all WHERE \"id\" > ? ORDER BY \"id\" LIMIT 2",
+		"SELECT * FROM (SELECT id, name FROM user WHERE active = ?) scan_all WHERE \"id\" > ? ORDER BY \"id\" LIMIT 2",
+	}
+	if !reflect.DeepEqual(queries, wantQueries) {
+		t.Errorf("got queries %q", queries)
+	}
+
+	// Stopping at the last row of a batch doesn't query the next batch.
+	queries, ids = nil, nil
+	err = New(db, Default{}).Write("SELECT id, name FROM user").
+		ScanAll(context.Background(), "id", 2, func(p *Q, rows *sql.Rows) error {
+			ids = append(ids, int64(p.RowIndex()))
+			if len(ids) == 2 {
+				return ErrStopIteration
+			}
+			return nil
+		})
+	if err != nil || len(queries) != 1 || !reflect.DeepEqual(ids, []int64{0, 1}) {
+		t.Errorf("ScanAll() stopped = %v, after %d queries, visited %v", err, len(queries), ids)
+	}
+	// The key column is an identifier.
+	queries = nil
+	err = New(db, Default{}).Write("SELECT id, name FROM user").
+		ScanAll(context.Background(), "id; DROP TABLE user", 2, func(*Q, *sql.Rows) error { return nil })
+	var identErr *IdentError
+	if !errors.As(err, &identErr) || len(queries) != 0 {
+		t.Errorf("ScanAll() with an invalid key column = %v, after %d queries, want an *IdentError", err, len(queries))
+	}
+}
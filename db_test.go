@@ -0,0 +1,77 @@
+package querier
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var registerTestSchemeOnce sync.Once
+
+// testDSN returns a DSN with scheme querier-test of a database whose queries are served by fn.
+func testDSN(fn testHandler) string {
+	registerTestSchemeOnce.Do(func() {
+		Register("querier-test", func(dsn string) (string, string, Dialect, error) {
+			return "querier-test", strings.TrimPrefix(dsn, "querier-test://"), numberedDialect{}, nil
+		})
+	})
+	return "querier-test://" + registerTestHandler(fn)
+}
+
+func TestOpenDSN(t *testing.T) {
+	dsn := testDSN(func(string, []driver.Value) (*testRows, error) {
+		return nil, nil
+	})
+	db, err := OpenDSN(context.Background(), dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, ok := db.Dialect().(numberedDialect); !ok {
+		t.Errorf("Dialect() = %T, want numberedDialect", db.Dialect())
+	}
+	if err = db.New().Write("DELETE FROM user").Exec(); err != nil {
+		t.Error(err)
+	}
+
+	for _, dsn := range []string{"unknown://localhost", "localhost"} {
+		if _, err = OpenDSN(context.Background(), dsn); err == nil {
+			t.Errorf("OpenDSN(%q) returned no error", dsn)
+		}
+	}
+}
+
+func TestOpenConfig(t *testing.T) {
+	dsn := testDSN(func(string, []driver.Value) (*testRows, error) {
+		return nil, nil
+	})
+	var logged []string
+	db, err := OpenConfig(Config{
+		DSN:          dsn,
+		MaxOpenConns: 3,
+		PingTimeout:  time.Second,
+		Logger: LoggerFunc(func(_ context.Context, query string, _ []interface{}, _ time.Duration, _ error) {
+			logged = append(logged, query)
+		}),
+		Dialect: Default{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, ok := db.Dialect().(Default); !ok {
+		t.Errorf("Dialect() = %T, want Default", db.Dialect())
+	}
+	if n := db.Stats().MaxOpenConnections; n != 3 {
+		t.Errorf("MaxOpenConnections = %d, want 3", n)
+	}
+	if err = db.New().Write("DELETE FROM user").Exec(); err != nil {
+		t.Fatal(err)
+	}
+	if len(logged) != 1 || logged[0] != "DELETE FROM user" {
+		t.Errorf("logged %v, want [DELETE FROM user]", logged)
+	}
+}
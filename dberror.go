@@ -0,0 +1,123 @@
+package querier
+
+import "fmt"
+
+// DialectErrors can be implemented by a Dialect to translate the errors of its driver into the portable errors below,
+// so applications can handle them without matching on error messages or driver error codes:
+//
+//	var unique *querier.UniqueViolation
+//	if errors.As(err, &unique) && unique.Constraint == "user_email" {
+//		// Respond with 409 Conflict.
+//	}
+//
+// The errors wrap the driver's error, so it can still be inspected.
+type DialectErrors interface {
+	// TranslateError returns err as a portable error, or err itself if it has no portable equivalent.
+	TranslateError(err error) error
+}
+
+// translateError translates err with the DialectErrors of d, if it implements it.
+func translateError(d Dialect, err error) error {
+	if de, ok := d.(DialectErrors); ok && err != nil {
+		return de.TranslateError(err)
+	}
+	return err
+}
+
+// UniqueViolation means that a statement violated a unique constraint or primary key.
+type UniqueViolation struct {
+	// Constraint is the name of the constraint or index, empty if the database doesn't report it.
+	Constraint string
+	// Fields are the names of the fields of the constraint, when it's violated by InsertStruct or UpdateStruct and
+	// the constraint is one of the struct's, see the unique tag option.
+	Fields []string
+	Err    error
+}
+
+func (e *UniqueViolation) Error() string {
+	return violationError("unique", e.Constraint, e.Err)
+}
+
+func (e *UniqueViolation) Unwrap() error {
+	return e.Err
+}
+
+// FieldErrors returns the violation as FieldErrors of its fields with the message "is already taken", e.g. for an
+// API response like validation errors. It returns nil if the fields are unknown.
+func (e *UniqueViolation) FieldErrors() FieldErrors {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	errs := make(FieldErrors, len(e.Fields))
+	for i, field := range e.Fields {
+		errs[i] = FieldError{Field: field, Message: "is already taken"}
+	}
+	return errs
+}
+
+// FKViolation means that a statement violated a foreign key constraint, by referring to a row that doesn't exist or by
+// deleting a row that is referred to.
+type FKViolation struct {
+	// Constraint is the name of the constraint, empty if the database doesn't report it.
+	Constraint string
+	Err        error
+}
+
+func (e *FKViolation) Error() string {
+	return violationError("foreign key", e.Constraint, e.Err)
+}
+
+func (e *FKViolation) Unwrap() error {
+	return e.Err
+}
+
+// NotNullViolation means that a statement set a NOT NULL column to NULL, or didn't set one without a default.
+type NotNullViolation struct {
+	// Column is the name of the column, empty if the database doesn't report it.
+	Column string
+	Err    error
+}
+
+func (e *NotNullViolation) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("not null violation: %v", e.Err)
+	}
+	return fmt.Sprintf("not null violation of column %s: %v", e.Column, e.Err)
+}
+
+func (e *NotNullViolation) Unwrap() error {
+	return e.Err
+}
+
+// SyntaxError means that the database couldn't parse a statement.
+type SyntaxError struct {
+	Err error
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error: %v", e.Err)
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return e.Err
+}
+
+// ConnectionError means that the connection to the database failed or was lost. The statement may be retried.
+type ConnectionError struct {
+	Err error
+}
+
+func (e *ConnectionError) Error() string {
+	return fmt.Sprintf("connection error: %v", e.Err)
+}
+
+func (e *ConnectionError) Unwrap() error {
+	return e.Err
+}
+
+func violationError(kind, constraint string, err error) string {
+	if constraint == "" {
+		return fmt.Sprintf("%s violation: %v", kind, err)
+	}
+	return fmt.Sprintf("%s violation of constraint %s: %v", kind, constraint, err)
+}
@@ -0,0 +1,43 @@
+package querier
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// uniqueDialect translates errors containing "duplicate" into a *UniqueViolation.
+type uniqueDialect struct {
+	Default
+}
+
+func (uniqueDialect) TranslateError(err error) error {
+	if strings.Contains(err.Error(), "duplicate") {
+		return &UniqueViolation{Constraint: "user_email", Err: err}
+	}
+	return err
+}
+
+func TestTranslateError(t *testing.T) {
+	errDuplicate, errOther := errors.New("duplicate key"), errors.New("other")
+	sqlDB := openTestDB(t, func(query string, _ []driver.Value) (*testRows, error) {
+		if strings.Contains(query, "email") {
+			return nil, errDuplicate
+		}
+		return nil, errOther
+	})
+
+	err := New(sqlDB, uniqueDialect{}).Write("INSERT INTO user (email) VALUES (?)", "a").Exec()
+	var unique *UniqueViolation
+	if !errors.As(err, &unique) || unique.Constraint != "user_email" || !errors.Is(err, errDuplicate) {
+		t.Errorf("Exec() = %v, want a *UniqueViolation wrapping the driver's error", err)
+	}
+	if err = New(sqlDB, uniqueDialect{}).Write("SELECT * FROM user").Find(&[]struct{}{}); !errors.Is(err, errOther) ||
+		errors.As(err, &unique) {
+		t.Errorf("Find() = %v, want the driver's error", err)
+	}
+	if err = New(sqlDB, Default{}).Write("INSERT INTO user (email) VALUES (?)", "a").Exec(); errors.As(err, &unique) {
+		t.Errorf("Exec() with a dialect without DialectErrors = %v, want the driver's error", err)
+	}
+}
@@ -0,0 +1,164 @@
+package querier
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DebugString returns the query with its parameters interpolated, so it can be copied and run while debugging.
+// Parameters are escaped, but the result must not be executed by the application: it's for debugging only. Bind
+// variables are recognized as ? or $N outside of quoted strings and identifiers. Sensitive parameters are redacted.
+func (q *Q) DebugString() string {
+	return interpolate(q.d, q.query.String(), q.params)
+}
+
+// LiteralFormatter can be implemented by a Dialect to format values as SQL literals of its database, e.g. booleans as
+// 1 and 0 or strings with escaped backslashes. Dialects that don't implement it use the default.
+type LiteralFormatter interface {
+	// FormatLiteral formats v, a driver.Value, as a SQL literal.
+	FormatLiteral(v interface{}) string
+}
+
+// FormatLiteral formats v as an ANSI SQL literal: NULL, TRUE and FALSE, numbers, strings quoted with quotes doubled, byte
+// slices as X'hex' and times as 'YYYY-MM-DD hh:mm:ss.ffffff'.
+func (Default) FormatLiteral(v interface{}) string {
+	return formatLiteral(v)
+}
+
+// FormatLiteral formats v as a SQL literal of Dialect d, after converting it to a driver.Value. It's used by
+// DebugString, and can be used for DDL, e.g. DEFAULT values. The result must not be used to build queries from user
+// input, use bind variables for that.
+func FormatLiteral(d Dialect, v interface{}) string {
+	dv, err := driver.DefaultParameterConverter.ConvertValue(v)
+	if err != nil {
+		return fmt.Sprintf("<%s>", err)
+	}
+	f, ok := d.(LiteralFormatter)
+	if !ok {
+		f = Default{}
+	}
+	return f.FormatLiteral(dv)
+}
+
+// interpolate replaces the bind variables in query with params formatted as literals of d, see DebugString.
+func interpolate(d Dialect, query string, params []interface{}) string {
+	var (
+		buf   bytes.Buffer
+		quote byte
+		next  int
+	)
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '?':
+			buf.WriteString(debugParam(d, params, next))
+			next++
+			continue
+		case c == '$' && i+1 < len(query) && isDigit(query[i+1]):
+			j := i + 1
+			for j < len(query) && isDigit(query[j]) {
+				j++
+			}
+			n, _ := strconv.Atoi(query[i+1 : j])
+			buf.WriteString(debugParam(d, params, n-1))
+			i = j - 1
+			continue
+		}
+		buf.WriteByte(c)
+	}
+	return buf.String()
+}
+
+// Explain returns the database's query plan for the query. Every row of the plan is returned as a line, with its
+// columns separated by tabs. The query itself is not executed and the Q is left unchanged.
+func (q *Q) Explain(ctx context.Context) (string, error) {
+	if q.query.Len() == 0 {
+		panic(errEmptyQuery)
+	}
+	rows, err := q.ex.QueryContext(ctx, "EXPLAIN "+q.query.String(), q.params...)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		plan   []string
+		values = make([]sql.NullString, len(columns))
+		dest   = make([]interface{}, len(columns))
+	)
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	for rows.Next() {
+		if err = rows.Scan(dest...); err != nil {
+			return "", err
+		}
+		line := make([]string, len(values))
+		for i, value := range values {
+			if value.Valid {
+				line[i] = value.String
+			} else {
+				line[i] = "NULL"
+			}
+		}
+		plan = append(plan, strings.Join(line, "\t"))
+	}
+	return strings.Join(plan, "\n"), rows.Err()
+}
+
+func debugParam(d Dialect, params []interface{}, i int) string {
+	if i < 0 || i >= len(params) {
+		// Leave a bind variable without a parameter recognizable.
+		return "<missing>"
+	}
+	param := params[i]
+	if IsSensitive(param) {
+		param = Redacted
+	}
+	return FormatLiteral(d, param)
+}
+
+// formatLiteral formats driver value v as an ANSI SQL literal.
+func formatLiteral(v driver.Value) string {
+	switch v := v.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case []byte:
+		return "X'" + hex.EncodeToString(v) + "'"
+	case string:
+		return "'" + strings.Replace(v, "'", "''", -1) + "'"
+	case time.Time:
+		return "'" + v.Format("2006-01-02 15:04:05.999999") + "'"
+	}
+	return fmt.Sprintf("'%v'", v)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
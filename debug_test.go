@@ -0,0 +1,91 @@
+package querier
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+)
+
+func TestDebugString(t *testing.T) {
+	name := "O'Brien"
+	tests := []struct {
+		query  string
+		params []interface{}
+		want   string
+	}{
+		{"SELECT * FROM user WHERE name = ? AND age > ?", []interface{}{&name, 18}, "SELECT * FROM user WHERE name = 'O''Brien' AND age > 18"},
+		{"SELECT '?', \"a?\" FROM t WHERE id = ?", []interface{}{int64(1)}, "SELECT '?', \"a?\" FROM t WHERE id = 1"},
+		{"UPDATE t SET a = $2, b = $1", []interface{}{true, nil}, "UPDATE t SET a = NULL, b = TRUE"},
+		{"INSERT INTO t VALUES (?, ?, ?)", []interface{}{[]byte{0xca, 0xfe}, 1.5, time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC)}, "INSERT INTO t VALUES (X'cafe', 1.5, '2018-01-02 03:04:05')"},
+		{"SELECT * FROM t WHERE password = ? AND a = ?", []interface{}{Sensitive("secret")}, "SELECT * FROM t WHERE password = '[REDACTED]' AND a = <missing>"},
+	}
+
+	for _, tt := range tests {
+		got := New(nil, Default{}).Write(tt.query, tt.params...).DebugString()
+		if got != tt.want {
+			t.Errorf("DebugString() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestExplain(t *testing.T) {
+	db := openTestDB(t, func(query string, args []driver.Value) (*testRows, error) {
+		if query != "EXPLAIN SELECT * FROM user WHERE id = ?" || len(args) != 1 {
+			t.Errorf("unexpected query %q %v", query, args)
+		}
+		return &testRows{
+			columns: []string{"id", "table", "key"},
+			values:  [][]driver.Value{{int64(1), "user", nil}},
+		}, nil
+	})
+	defer db.Close()
+
+	plan, err := New(db, Default{}).Write("SELECT * FROM user WHERE id = ?", 1).Explain(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "1\tuser\tNULL"; plan != want {
+		t.Errorf("Explain() = %q, want %q", plan, want)
+	}
+}
+
+// bitDialect formats booleans as 1 and 0.
+type bitDialect struct {
+	Default
+}
+
+func (bitDialect) FormatLiteral(v interface{}) string {
+	if b, ok := v.(bool); ok {
+		if b {
+			return "1"
+		}
+		return "0"
+	}
+	return formatLiteral(v)
+}
+
+func TestFormatLiteral(t *testing.T) {
+	name := "it's"
+	tests := []struct {
+		d    Dialect
+		v    interface{}
+		want string
+	}{
+		{Default{}, true, "TRUE"},
+		{Default{}, &name, "'it''s'"},
+		{Default{}, time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC), "'2018-01-02 03:04:05'"},
+		{bitDialect{}, false, "0"},
+		{bitDialect{}, int32(7), "7"},
+	}
+	for _, tt := range tests {
+		if got := FormatLiteral(tt.d, tt.v); got != tt.want {
+			t.Errorf("FormatLiteral(%T, %v) = %q, want %q", tt.d, tt.v, got, tt.want)
+		}
+	}
+
+	got := New(nil, bitDialect{}).Write("UPDATE t SET active = ?", true).DebugString()
+	if want := "UPDATE t SET active = 1"; got != want {
+		t.Errorf("DebugString() = %q, want %q", got, want)
+	}
+}
@@ -1,8 +1,10 @@
-package  querier
+package querier
 
 import (
 	"database/sql"
+	"database/sql/driver"
 	"reflect"
+	"strings"
 	"time"
 )
 
@@ -11,7 +13,9 @@ type Dialect interface {
 	// TypeMapper maps a Go type to a SQL data type. It returns true and a dataType whether the mapping succeeded.
 	TypeMapper(t reflect.Type) (dataType string, ok bool)
 
-	// BindVar returns a formatted bind variable. i represents the current iteration.
+	// BindVar returns a formatted bind variable. i is the index of the bind variable in the query, starting at 0 and
+	// continuing over the write calls of the query, e.g. for numbered placeholders like $1. q.Context() returns the
+	// context of the query being built.
 	BindVar(q *Q, i int) string
 }
 
@@ -20,12 +24,14 @@ type Default struct{}
 
 var (
 	reflectTypeScanner     = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	reflectTypeValuer      = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
 	reflectTypeByteSlice   = reflect.TypeOf([]byte{})
 	reflectTypeTime        = reflect.TypeOf(time.Time{})
 	reflectTypeNullString  = reflect.TypeOf(sql.NullString{})
 	reflectTypeNullInt64   = reflect.TypeOf(sql.NullInt64{})
 	reflectTypeNullFloat64 = reflect.TypeOf(sql.NullFloat64{})
 	reflectTypeNullBool    = reflect.TypeOf(sql.NullBool{})
+	reflectTypePoint       = reflect.TypeOf(Point{})
 )
 
 var typeMap = map[reflect.Kind]string{
@@ -46,9 +52,13 @@ var typeMap = map[reflect.Kind]string{
 }
 
 // TypeMapper is the default type mapper.
-func (Default) TypeMapper(t reflect.Type) (dataType string, ok bool) {
+func (d Default) TypeMapper(t reflect.Type) (dataType string, ok bool) {
 	if t.Kind() == reflect.Ptr {
-		t = t.Elem()
+		// A nil pointer represents NULL, so pointers map to nullable columns.
+		if dataType, ok = d.TypeMapper(t.Elem()); ok {
+			dataType = Nullable(dataType)
+		}
+		return
 	}
 
 	if dataType, ok = typeMap[t.Kind()]; ok {
@@ -69,7 +79,16 @@ func (Default) TypeMapper(t reflect.Type) (dataType string, ok bool) {
 		dataType = "DOUBLE NULL"
 	case reflectTypeNullBool:
 		dataType = "BOOLEAN NULL"
+	case reflectTypePoint:
+		dataType = "POINT NOT NULL"
 	default:
+		if v, isNull := nullValueType(t); isNull {
+			// Map sql.Null[T] to the nullable data type of T.
+			if dataType, ok = d.TypeMapper(v); ok {
+				dataType = Nullable(dataType)
+			}
+			return
+		}
 		ok = false
 	}
 
@@ -80,3 +99,23 @@ func (Default) TypeMapper(t reflect.Type) (dataType string, ok bool) {
 func (Default) BindVar(*Q, int) string {
 	return "?"
 }
+
+// Nullable makes dataType nullable by replacing a trailing NOT NULL constraint, e.g. for the pointers of a TypeMapper.
+func Nullable(dataType string) string {
+	if strings.HasSuffix(dataType, " NOT NULL") {
+		return strings.TrimSuffix(dataType, " NOT NULL") + " NULL"
+	}
+	return dataType
+}
+
+// nullValueType returns the type of V and true if t is an instance of the generic sql.Null[T] type (Go 1.22+).
+func nullValueType(t reflect.Type) (reflect.Type, bool) {
+	if t.Kind() != reflect.Struct || t.PkgPath() != reflectTypeNullString.PkgPath() || !strings.HasPrefix(t.Name(), "Null[") {
+		return nil, false
+	}
+	v, ok := t.FieldByName("V")
+	if !ok {
+		return nil, false
+	}
+	return v.Type, true
+}
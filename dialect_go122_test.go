@@ -0,0 +1,40 @@
+//go:build go1.22
+// +build go1.22
+
+package querier
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDefaultTypeMapperGenericNull(t *testing.T) {
+	tests := []struct {
+		in      interface{}
+		wantOut string
+		wantOk  bool
+	}{
+		{sql.Null[string]{}, "VARCHAR(255) NULL", true},
+		{sql.Null[int32]{}, "INT NULL", true},
+		{sql.Null[uint64]{}, "BIGINT UNSIGNED NULL", true},
+		{sql.Null[time.Time]{}, "DATETIME NULL", true},
+		{&sql.Null[float64]{}, "DOUBLE NULL", true},
+		{sql.Null[reflect.Value]{}, "", false},
+	}
+
+	for _, tt := range tests {
+		gotOut, gotOk := Default{}.TypeMapper(reflect.TypeOf(tt.in))
+		if gotOut != tt.wantOut {
+			t.Errorf("TypeMapper(%T) out = %q, want %q", tt.in, gotOut, tt.wantOut)
+		}
+		if gotOk != tt.wantOk {
+			t.Errorf("TypeMapper(%T) ok = %t, want %t", tt.in, gotOk, tt.wantOk)
+		}
+	}
+
+	if !isColumnType(reflect.TypeOf(sql.Null[int64]{})) {
+		t.Error("isColumnType(sql.Null[int64]) = false, want true")
+	}
+}
@@ -9,7 +9,11 @@ import (
 
 func TestDefaultTypeMapper(t *testing.T) {
 	var (
-		str string
+		str   string
+		i64   int64
+		tm    time.Time
+		nstr  sql.NullString
+		bytes []byte
 	)
 
 	tests := []struct {
@@ -18,7 +22,11 @@ func TestDefaultTypeMapper(t *testing.T) {
 		wantOk  bool
 	}{
 		{"", "VARCHAR(255) NOT NULL", true},
-		{&str, "VARCHAR(255) NOT NULL", true},
+		{&str, "VARCHAR(255) NULL", true},
+		{&i64, "BIGINT NULL", true},
+		{&tm, "DATETIME NULL", true},
+		{&nstr, "VARCHAR(255) NULL", true},
+		{&bytes, "VARBINARY(255) NULL", true},
 
 		{int(0), "BIGINT NOT NULL", true},
 		{int64(0), "BIGINT NOT NULL", true},
@@ -43,6 +51,8 @@ func TestDefaultTypeMapper(t *testing.T) {
 		{sql.NullInt64{}, "BIGINT NULL", true},
 		{sql.NullFloat64{}, "DOUBLE NULL", true},
 		{sql.NullBool{}, "BOOLEAN NULL", true},
+		{Point{}, "POINT NOT NULL", true},
+		{&Point{}, "POINT NULL", true},
 
 		{reflect.Value{}, "", false},
 	}
@@ -0,0 +1,148 @@
+package querier
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// testHandler serves a query for the test driver.
+type testHandler func(query string, args []driver.Value) (*testRows, error)
+
+var (
+	testDriverOnce     sync.Once
+	testDriverMu       sync.Mutex
+	testDriverHandlers = make(map[string]testHandler)
+)
+
+// openTestDB opens a database whose queries are served by fn.
+func openTestDB(t testing.TB, fn testHandler) *sql.DB {
+	db, err := sql.Open("querier-test", registerTestHandler(fn))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// registerTestHandler registers fn with the test driver, it returns the data source name of a database whose queries
+// are served by fn.
+func registerTestHandler(fn testHandler) string {
+	testDriverOnce.Do(func() {
+		sql.Register("querier-test", testDriver{})
+	})
+	testDriverMu.Lock()
+	defer testDriverMu.Unlock()
+	dsn := strconv.Itoa(len(testDriverHandlers))
+	testDriverHandlers[dsn] = fn
+	return dsn
+}
+
+type testDriver struct{}
+
+func (testDriver) Open(dsn string) (driver.Conn, error) {
+	testDriverMu.Lock()
+	defer testDriverMu.Unlock()
+	return &testConn{fn: testDriverHandlers[dsn]}, nil
+}
+
+type testConn struct {
+	fn testHandler
+}
+
+func (c *testConn) Close() error              { return nil }
+func (c *testConn) Begin() (driver.Tx, error) { return testTx{}, nil }
+
+// Prepare passes "PREPARE " and the query to the handler, so tests can count the statements prepared.
+func (c *testConn) Prepare(query string) (driver.Stmt, error) {
+	if _, err := c.fn("PREPARE "+query, nil); err != nil {
+		return nil, err
+	}
+	return &testStmt{c: c, query: query}, nil
+}
+
+func (c *testConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	rows, err := c.fn(query, namedValues(args))
+	if err != nil {
+		return nil, err
+	}
+	if rows == nil {
+		rows = &testRows{}
+	}
+	return rows, nil
+}
+
+func (c *testConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	rows, err := c.fn(query, namedValues(args))
+	if err != nil {
+		return nil, err
+	}
+	var n int64
+	if rows != nil {
+		n = int64(len(rows.values))
+	}
+	return testResult{n}, nil
+}
+
+type testStmt struct {
+	c     *testConn
+	query string
+}
+
+func (s *testStmt) Close() error  { return nil }
+func (s *testStmt) NumInput() int { return -1 }
+
+func (s *testStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.c.ExecContext(context.Background(), s.query, driverNamedValues(args))
+}
+
+func (s *testStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.c.QueryContext(context.Background(), s.query, driverNamedValues(args))
+}
+
+type testTx struct{}
+
+func (testTx) Commit() error   { return nil }
+func (testTx) Rollback() error { return nil }
+
+// testRows are the rows returned by a testHandler.
+type testRows struct {
+	columns []string
+	values  [][]driver.Value
+	// err is returned after the values instead of io.EOF, e.g. for a broken connection.
+	err error
+}
+
+func (r *testRows) Columns() []string { return r.columns }
+func (r *testRows) Close() error      { return nil }
+
+func (r *testRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		if r.err != nil {
+			return r.err
+		}
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func namedValues(args []driver.NamedValue) []driver.Value {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	return values
+}
+
+func driverNamedValues(values []driver.Value) []driver.NamedValue {
+	args := make([]driver.NamedValue, len(values))
+	for i, v := range values {
+		args[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
+	}
+	return args
+}
@@ -0,0 +1,160 @@
+package querier
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// tagOptionEncrypted encrypts a field at rest, e.g. `db:"ssn,,encrypted"`.
+const tagOptionEncrypted = "encrypted"
+
+var (
+	errNoKeyProvider     = errors.New("no key provider set for encrypted field")
+	errInvalidCiphertext = errors.New("invalid ciphertext")
+
+	keyProvider KeyProvider
+)
+
+// KeyProvider provides the keys for encrypted fields. Values are encrypted with the current encryption key, the
+// key's id is stored along with the ciphertext so values can be decrypted after the encryption key has changed.
+type KeyProvider interface {
+	// EncryptionKey returns the id and AEAD of the key that is used to encrypt values.
+	EncryptionKey() (id byte, aead cipher.AEAD, err error)
+
+	// DecryptionKey returns the AEAD of key id.
+	DecryptionKey(id byte) (cipher.AEAD, error)
+}
+
+// SetKeyProvider sets the KeyProvider for encrypted fields. It must be called before encrypted fields are used.
+func SetKeyProvider(kp KeyProvider) {
+	keyProvider = kp
+}
+
+// Rekey re-encrypts ciphertext of field name with the current encryption key. It returns false if ciphertext was
+// already encrypted with the current encryption key.
+func Rekey(name string, ciphertext []byte) (rekeyed []byte, changed bool, err error) {
+	if keyProvider == nil {
+		return nil, false, errNoKeyProvider
+	}
+	if len(ciphertext) == 0 {
+		return nil, false, errInvalidCiphertext
+	}
+	id, _, err := keyProvider.EncryptionKey()
+	if err != nil || ciphertext[0] == id {
+		return ciphertext, false, err
+	}
+	plaintext, err := decrypt(name, ciphertext)
+	if err != nil {
+		return nil, false, err
+	}
+	rekeyed, err = encrypt(name, plaintext)
+	return rekeyed, err == nil, err
+}
+
+// encrypt encrypts plaintext of field name. The field name is used as additional data, so ciphertexts can't be
+// swapped between fields.
+func encrypt(name string, plaintext []byte) ([]byte, error) {
+	if keyProvider == nil {
+		return nil, errNoKeyProvider
+	}
+	id, aead, err := keyProvider.EncryptionKey()
+	if err != nil {
+		return nil, err
+	}
+	nonceSize := aead.NonceSize()
+	ciphertext := make([]byte, 1+nonceSize, 1+nonceSize+len(plaintext)+aead.Overhead())
+	ciphertext[0] = id
+	if _, err = io.ReadFull(rand.Reader, ciphertext[1:]); err != nil {
+		return nil, err
+	}
+	return aead.Seal(ciphertext, ciphertext[1:], plaintext, []byte(name)), nil
+}
+
+// decrypt decrypts ciphertext of field name.
+func decrypt(name string, ciphertext []byte) ([]byte, error) {
+	if keyProvider == nil {
+		return nil, errNoKeyProvider
+	}
+	if len(ciphertext) == 0 {
+		return nil, errInvalidCiphertext
+	}
+	aead, err := keyProvider.DecryptionKey(ciphertext[0])
+	if err != nil {
+		return nil, err
+	}
+	nonceSize := aead.NonceSize()
+	if len(ciphertext) < 1+nonceSize {
+		return nil, errInvalidCiphertext
+	}
+	return aead.Open(nil, ciphertext[1:1+nonceSize], ciphertext[1+nonceSize:], []byte(name))
+}
+
+// encryptedField wraps the value of an encrypted field in a ValueMap. It encrypts the value when it is bound and
+// decrypts when it is scanned.
+type encryptedField struct {
+	name string
+	v    reflect.Value
+}
+
+// Value implements driver.Valuer.
+func (f *encryptedField) Value() (driver.Value, error) {
+	v := f.v
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+	var plaintext []byte
+	switch {
+	case v.Kind() == reflect.String:
+		plaintext = []byte(v.String())
+	case v.Type() == reflectTypeByteSlice:
+		if v.IsNil() {
+			return nil, nil
+		}
+		plaintext = v.Bytes()
+	default:
+		return nil, fmt.Errorf("unsupported type %s of encrypted field %s", v.Type(), f.name)
+	}
+	return encrypt(f.name, plaintext)
+}
+
+// Scan implements sql.Scanner.
+func (f *encryptedField) Scan(src interface{}) error {
+	v := f.v
+	var ciphertext []byte
+	switch src := src.(type) {
+	case nil:
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	case []byte:
+		ciphertext = src
+	case string:
+		ciphertext = []byte(src)
+	default:
+		return fmt.Errorf("cannot scan %T into encrypted field %s", src, f.name)
+	}
+	plaintext, err := decrypt(f.name, ciphertext)
+	if err != nil {
+		return err
+	}
+	if v.Kind() == reflect.Ptr {
+		v.Set(reflect.New(v.Type().Elem()))
+		v = v.Elem()
+	}
+	switch {
+	case v.Kind() == reflect.String:
+		v.SetString(string(plaintext))
+	case v.Type() == reflectTypeByteSlice:
+		v.SetBytes(plaintext)
+	default:
+		return fmt.Errorf("unsupported type %s of encrypted field %s", v.Type(), f.name)
+	}
+	return nil
+}
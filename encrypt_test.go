@@ -0,0 +1,105 @@
+package querier
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+type testKeyProvider struct {
+	current byte
+	keys    map[byte]cipher.AEAD
+}
+
+func newTestKeyProvider(ids ...byte) *testKeyProvider {
+	kp := &testKeyProvider{keys: make(map[byte]cipher.AEAD)}
+	for _, id := range ids {
+		block, err := aes.NewCipher(bytes.Repeat([]byte{id}, 32))
+		if err != nil {
+			panic(err)
+		}
+		if kp.keys[id], err = cipher.NewGCM(block); err != nil {
+			panic(err)
+		}
+		kp.current = id
+	}
+	return kp
+}
+
+func (kp *testKeyProvider) EncryptionKey() (byte, cipher.AEAD, error) {
+	return kp.current, kp.keys[kp.current], nil
+}
+
+func (kp *testKeyProvider) DecryptionKey(id byte) (cipher.AEAD, error) {
+	if aead, ok := kp.keys[id]; ok {
+		return aead, nil
+	}
+	return nil, errors.New("unknown key")
+}
+
+type encryptedModel struct {
+	ID  int
+	SSN string  `db:"ssn,,encrypted"`
+	PIN *string `db:"pin,,encrypted"`
+}
+
+func TestEncryptedField(t *testing.T) {
+	kp := newTestKeyProvider(1, 2)
+	SetKeyProvider(kp)
+	defer SetKeyProvider(nil)
+
+	in := encryptedModel{SSN: "123-45-6789"}
+	params := Values(&in).MapToFields(Fields(&in).Select(), nil)
+	ssn, err := params[1].(driver.Valuer).Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(ssn.([]byte), []byte(in.SSN)) {
+		t.Error("Value() returned plaintext")
+	}
+	if pin, err := params[2].(driver.Valuer).Value(); pin != nil || err != nil {
+		t.Errorf("Value() of nil pointer = %v, %v, want nil, nil", pin, err)
+	}
+
+	pin := "0000"
+	out := encryptedModel{PIN: &pin}
+	dest := Values(&out).MapToColumns([]string{"ssn", "pin"}, nil)
+	if err = dest[0].(interface{ Scan(interface{}) error }).Scan(ssn); err != nil {
+		t.Fatal(err)
+	}
+	if err = dest[1].(interface{ Scan(interface{}) error }).Scan(nil); err != nil {
+		t.Fatal(err)
+	}
+	if out.SSN != in.SSN || out.PIN != nil {
+		t.Errorf("Scan() = %q, %v, want %q, nil", out.SSN, out.PIN, in.SSN)
+	}
+
+	// Ciphertexts are bound to their field.
+	if _, err = decrypt("pin", ssn.([]byte)); err == nil {
+		t.Error("decrypt() of another field's ciphertext succeeded")
+	}
+
+	// Rekey with a new key.
+	kp.keys[3], kp.current = kp.keys[1], 3
+	rekeyed, changed, err := Rekey("ssn", ssn.([]byte))
+	if err != nil || !changed || rekeyed[0] != 3 {
+		t.Fatalf("Rekey() = %v, %v, %v", rekeyed, changed, err)
+	}
+	if _, changed, _ = Rekey("ssn", rekeyed); changed {
+		t.Error("Rekey() of current key changed ciphertext")
+	}
+	if plaintext, err := decrypt("ssn", rekeyed); err != nil || string(plaintext) != in.SSN {
+		t.Errorf("decrypt() of rekeyed = %q, %v", plaintext, err)
+	}
+}
+
+func TestEncryptedDataType(t *testing.T) {
+	for _, field := range Fields(&encryptedModel{}).Except("ID").Select() {
+		if !field.Encrypted || field.DataType != "VARBINARY(255) NULL" {
+			t.Errorf("field %s = %+v", field.Name, field)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package querier
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	// tagOptionEnum restricts a field to a set of values, e.g. `db:"status,,enum=pending;active;done"`.
+	tagOptionEnum = "enum"
+
+	enumSep = ";"
+)
+
+// EnumMapper can be implemented by a Dialect to map enum fields to a data type, e.g. a native ENUM type. Dialects
+// that don't implement it use the default mapping, which adds a CHECK constraint to the field's data type.
+type EnumMapper interface {
+	// EnumType returns the data type for field name with values. dataType is the field's mapped data type.
+	EnumType(name, dataType string, values []string) string
+}
+
+// EnumError is returned when a value that is not one of its field's enum values is bound.
+type EnumError struct {
+	Field, Value string
+	Values       []string
+}
+
+func (e *EnumError) Error() string {
+	return fmt.Sprintf("invalid value %q for enum field %s, must be one of: %s", e.Value, e.Field, strings.Join(e.Values, ", "))
+}
+
+// EnumType returns dataType with a CHECK constraint on values.
+func (d Default) EnumType(name, dataType string, values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = d.FormatLiteral(value)
+	}
+	return fmt.Sprintf("%s CHECK (%s IN (%s))", dataType, name, strings.Join(quoted, FieldSep))
+}
+
+// enumValues returns the enum values of the tag, or nil if it has none.
+func (ft *FieldInfo) enumValues() []string {
+	values, ok := ft.Options[tagOptionEnum]
+	if !ok || values == "" {
+		return nil
+	}
+	return strings.Split(values, enumSep)
+}
+
+// enumDataType returns the data type for an enum field using d.
+func enumDataType(d Dialect, name, dataType string, values []string) string {
+	mapper, ok := d.(EnumMapper)
+	if !ok {
+		mapper = Default{}
+	}
+	return mapper.EnumType(name, dataType, values)
+}
+
+// checkEnum returns an *EnumError if v is not one of field's enum values. NULL is always allowed.
+func checkEnum(field *Field, v reflect.Value) error {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	value := fmt.Sprint(v.Interface())
+	for _, member := range field.Enum {
+		if value == member {
+			return nil
+		}
+	}
+	return &EnumError{Field: field.Name, Value: value, Values: field.Enum}
+}
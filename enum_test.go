@@ -0,0 +1,51 @@
+package querier
+
+import (
+	"reflect"
+	"testing"
+)
+
+type enumModel struct {
+	ID     int
+	Status string  `db:"status,,enum=pending;active;done"`
+	Kind   *string `db:"kind,,enum=a;b"`
+}
+
+func TestEnumDataType(t *testing.T) {
+	fields := Fields(&enumModel{}).Only("status", "kind").Select()
+
+	want := []Field{
+		{Name: "status", DataType: "VARCHAR(255) NOT NULL CHECK (status IN ('pending', 'active', 'done'))", Enum: []string{"pending", "active", "done"}},
+		{Name: "kind", DataType: "VARCHAR(255) NULL CHECK (kind IN ('a', 'b'))", Enum: []string{"a", "b"}},
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("Select() = %v, want %v", fields, want)
+	}
+}
+
+func TestEnumCheck(t *testing.T) {
+	kind := "c"
+	tests := []struct {
+		in      enumModel
+		wantErr bool
+	}{
+		{enumModel{Status: "active"}, false},
+		{enumModel{Status: "unknown"}, true},
+		{enumModel{Status: "done", Kind: &kind}, true},
+	}
+
+	for _, tt := range tests {
+		q := New(&testExecutor{}, Default{})
+		fields := q.Fields(&tt.in).Select()
+		q.Write("INSERT INTO enum_model").
+			WriteValueMap("({bindVar})", FieldSep, Values(&tt.in), fields...)
+
+		err := q.Exec()
+		if _, isEnumErr := err.(*EnumError); isEnumErr != tt.wantErr {
+			t.Errorf("Exec() with %+v error = %v, want enum error %v", tt.in, err, tt.wantErr)
+		}
+		if tt.wantErr && q.Error() != err {
+			t.Errorf("Error() = %v, want %v", q.Error(), err)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package querier
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// BatchOptions configures ExecBatch.
+type BatchOptions struct {
+	// Tx are the options of the transaction, nil uses the defaults.
+	Tx *sql.TxOptions
+	// Savepoints executes every statement in a savepoint. A failed statement is rolled back to its savepoint and
+	// reported, and the batch continues. Without savepoints the first failure aborts the batch.
+	Savepoints bool
+}
+
+// BatchError is the error of a statement of a batch.
+type BatchError struct {
+	// Index is the index of the statement.
+	Index int
+	// Err is the error of the statement.
+	Err error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("statement %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+type txBeginner interface {
+	BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error)
+}
+
+// ExecBatchContext executes stmts in one transaction and commits it. The transaction is begun by the Querier's
+// Executor, which must be a *sql.DB or *DB, or the Executor is used as is if it's a *Tx or *sql.Tx. The statements
+// are executed by the transaction, replacing their Executor.
+//
+// With opts.Savepoints the statements that failed are returned and the others are committed. Otherwise the first
+// failure rolls back the transaction and is returned as a *BatchError. opts may be nil.
+func (q *Q) ExecBatchContext(ctx context.Context, stmts []*Q, opts *BatchOptions) (failed []BatchError, err error) {
+	if opts == nil {
+		opts = &BatchOptions{}
+	}
+	if !Supports(q.d, FeatureTransactions) {
+		return nil, errors.New("querier: batch requires transactions, which the database doesn't support")
+	}
+	if opts.Savepoints && !Supports(q.d, FeatureSavepoints) {
+		return nil, errors.New("querier: batch requires savepoints, which the database doesn't support")
+	}
+
+	var tx *Tx
+	switch ex := q.ex.(type) {
+	case *Tx, *sql.Tx:
+		return q.execBatch(ctx, ex, stmts, opts.Savepoints)
+	case txBeginner:
+		sqlTx, err := ex.BeginTx(ctx, opts.Tx)
+		if err != nil {
+			return nil, err
+		}
+		tx = &Tx{Tx: sqlTx}
+	default:
+		return nil, fmt.Errorf("querier: executor %T can't begin a transaction", q.ex)
+	}
+
+	if failed, err = q.execBatch(ctx, tx, stmts, opts.Savepoints); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return failed, tx.Commit()
+}
+
+// ExecBatch executes stmts in one transaction, see ExecBatchContext.
+func (q *Q) ExecBatch(stmts []*Q, opts *BatchOptions) ([]BatchError, error) {
+	return q.ExecBatchContext(q.baseContext(), stmts, opts)
+}
+
+// execBatch executes stmts by ex, which is a transaction.
+func (q *Q) execBatch(ctx context.Context, ex Executor, stmts []*Q, savepoints bool) (failed []BatchError, err error) {
+	run := func(query string) error {
+		n := q.New()
+		n.ex = ex
+		return n.Write(query).ExecContext(ctx)
+	}
+	for i, stmt := range stmts {
+		stmt.ex = ex
+		if !savepoints {
+			if err = stmt.ExecContext(ctx); err != nil {
+				return nil, &BatchError{Index: i, Err: err}
+			}
+			continue
+		}
+
+		if err = run("SAVEPOINT querier_batch"); err != nil {
+			return nil, err
+		}
+		if err = stmt.ExecContext(ctx); err != nil {
+			failed = append(failed, BatchError{Index: i, Err: err})
+			err = run("ROLLBACK TO SAVEPOINT querier_batch")
+		} else {
+			err = run("RELEASE SAVEPOINT querier_batch")
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return failed, nil
+}
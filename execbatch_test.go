@@ -0,0 +1,71 @@
+package querier
+
+import (
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+var errTestFailed = errors.New("failed")
+
+func openBatchTestDB(t *testing.T, queries *[]string) *DB {
+	return NewDB(openTestDB(t, func(query string, _ []driver.Value) (*testRows, error) {
+		*queries = append(*queries, query)
+		if query == "DELETE FROM fail" {
+			return nil, errTestFailed
+		}
+		return nil, nil
+	}), Default{})
+}
+
+func TestExecBatchSavepoints(t *testing.T) {
+	var queries []string
+	db := openBatchTestDB(t, &queries)
+	defer db.Close()
+
+	stmts := []*Q{
+		db.New().Write("DELETE FROM a"),
+		db.New().Write("DELETE FROM fail"),
+		db.New().Write("DELETE FROM b"),
+	}
+	failed, err := db.New().ExecBatch(stmts, &BatchOptions{Savepoints: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(failed) != 1 || failed[0].Index != 1 || !errors.Is(&failed[0], errTestFailed) {
+		t.Errorf("got failed %v", failed)
+	}
+	want := []string{
+		"SAVEPOINT querier_batch", "DELETE FROM a", "RELEASE SAVEPOINT querier_batch",
+		"SAVEPOINT querier_batch", "DELETE FROM fail", "ROLLBACK TO SAVEPOINT querier_batch",
+		"SAVEPOINT querier_batch", "DELETE FROM b", "RELEASE SAVEPOINT querier_batch",
+	}
+	if !reflect.DeepEqual(queries, want) {
+		t.Errorf("executed %q, want %q", queries, want)
+	}
+}
+
+func TestExecBatchAbort(t *testing.T) {
+	var queries []string
+	db := openBatchTestDB(t, &queries)
+	defer db.Close()
+
+	stmts := []*Q{
+		db.New().Write("DELETE FROM a"),
+		db.New().Write("DELETE FROM fail"),
+		db.New().Write("DELETE FROM b"),
+	}
+	_, err := db.New().ExecBatch(stmts, nil)
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || batchErr.Index != 1 {
+		t.Fatalf("got error %v, want statement 1 to fail", err)
+	}
+	if want := []string{"DELETE FROM a", "DELETE FROM fail"}; !reflect.DeepEqual(queries, want) {
+		t.Errorf("executed %q, want %q", queries, want)
+	}
+
+	if _, err = New(&testExecutor{}, Default{}).ExecBatch(stmts, nil); err == nil {
+		t.Error("expected an error for an executor without transactions")
+	}
+}
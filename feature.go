@@ -0,0 +1,42 @@
+package querier
+
+// Feature is an optional feature of a database.
+type Feature int
+
+const (
+	// FeatureTransactions means the database supports transactions.
+	FeatureTransactions Feature = iota
+	// FeatureRowsAffected means the driver reports the number of rows affected by a statement.
+	FeatureRowsAffected
+	// FeatureLastInsertID means the driver reports the last inserted id.
+	FeatureLastInsertID
+	// FeatureSavepoints means the database supports savepoints within transactions.
+	FeatureSavepoints
+	// FeatureRowValues means the database supports row value comparisons, e.g. (a, b) IN ((1, 2)).
+	FeatureRowValues
+	// FeatureAlterTable means existing tables can be altered beyond adding columns, e.g. by modifying columns or adding
+	// constraints. Without it, e.g. in SQLite, the migrator adds columns in place and rebuilds tables for the other
+	// changes, including dropped columns, see migrator.TableRebuilder.
+	FeatureAlterTable
+	// FeatureBackslashEscapes means a backslash escapes the next character in string literals, e.g. 'it\'s' in MySQL.
+	// Without it, e.g. in SQLite, a backslash is an ordinary character. It's used to find the bind variables in text.
+	// Unlike the other features, it's only supported if a FeatureDialect reports it.
+	FeatureBackslashEscapes
+)
+
+// FeatureDialect can be implemented by a Dialect of a database that lacks features, e.g. an analytics database without
+// transactions. Dialects that don't implement it support all features, except FeatureBackslashEscapes.
+type FeatureDialect interface {
+	// Supports returns true if the database supports feature f.
+	Supports(f Feature) bool
+}
+
+// Supports returns true if Dialect d supports feature f. When RowsAffected or LastInsertID isn't supported, Exec
+// doesn't request it from the driver and the Querier reports zero. Helpers that use transactions, like the outbox and
+// queue packages, work without them when transactions aren't supported.
+func Supports(d Dialect, f Feature) bool {
+	if fd, ok := d.(FeatureDialect); ok {
+		return fd.Supports(f)
+	}
+	return f != FeatureBackslashEscapes
+}
@@ -0,0 +1,55 @@
+package querier
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+// analyticsDialect doesn't support transactions, RowsAffected and LastInsertID.
+type analyticsDialect struct {
+	Default
+}
+
+func (analyticsDialect) Supports(Feature) bool {
+	return false
+}
+
+type noResultExecutor struct {
+	testExecutor
+}
+
+type noResult struct{}
+
+var errNoResult = errors.New("not supported")
+
+func (noResult) LastInsertId() (int64, error) { return 0, errNoResult }
+func (noResult) RowsAffected() (int64, error) { return 0, errNoResult }
+
+func (e *noResultExecutor) ExecContext(ctx context.Context, query string, params ...interface{}) (sql.Result, error) {
+	e.testExecutor.ExecContext(ctx, query, params...)
+	return noResult{}, nil
+}
+
+func TestSupports(t *testing.T) {
+	if !Supports(Default{}, FeatureTransactions) {
+		t.Error("Supports(Default{}, FeatureTransactions) = false, want true")
+	}
+	if Supports(analyticsDialect{}, FeatureTransactions) {
+		t.Error("Supports(analyticsDialect{}, FeatureTransactions) = true, want false")
+	}
+
+	q := New(&noResultExecutor{}, analyticsDialect{})
+	if err := q.Write("INSERT INTO event VALUES (?)", 1).Exec(); err != nil {
+		t.Errorf("Exec() = %v, want nil", err)
+	}
+	if q.RowsAffected() != 0 || q.LastInsertID() != 0 {
+		t.Errorf("RowsAffected() = %d, LastInsertID() = %d, want 0, 0", q.RowsAffected(), q.LastInsertID())
+	}
+
+	q = New(&noResultExecutor{}, Default{})
+	if err := q.Write("INSERT INTO event VALUES (?)", 1).Exec(); err != errNoResult {
+		t.Errorf("Exec() = %v, want %v", err, errNoResult)
+	}
+}
@@ -0,0 +1,120 @@
+package querier
+
+import (
+	"reflect"
+	"sync"
+)
+
+// FieldSet is an immutable set of fields, see FieldSelector.Freeze. It's safe for concurrent use, so it can be built
+// once and reused by every query.
+type FieldSet struct {
+	fields []Field
+}
+
+type fieldSetKey struct {
+	t reflect.Type
+	d Dialect
+}
+
+// fieldSets caches the FieldSets of all fields of a struct type by type and Dialect.
+var fieldSets sync.Map
+
+// Freeze returns the selected fields as a FieldSet. The fields of a struct type are mapped once per Dialect and cached,
+// so Freeze doesn't use reflection after the first call for a type.
+func (s *FieldSelector) Freeze() *FieldSet {
+	all := s.all()
+	if s.filterSet == nil {
+		return all
+	}
+	return all.filter(s.filterSet, s.filterExclude)
+}
+
+// Project returns the selected fields that are requested, e.g. by the selection set of a GraphQL query, so a wide
+// table is only partially selected and scanned:
+//
+//	fields := querier.Fields(User{}).Except("password_hash").Project(requested, "id")
+//	err := q.Write("SELECT").WriteFields("{quotedName}", querier.FieldSep, fields.Fields()...).
+//		Write("FROM user WHERE id = ?", id).First(&user)
+//
+// First and Find only scan the selected columns, the other fields keep their zero value. A requested name matches a
+// field by name, or ignoring case and underscores, so createdAt matches created_at. Names that don't match a selected
+// field are ignored. The always fields are included whether they're requested or not, e.g. the key that the resolvers
+// of relations need.
+func (s *FieldSelector) Project(requested []string, always ...string) *FieldSet {
+	names := make(map[string]struct{}, len(requested)+len(always))
+	for _, name := range requested {
+		names[foldName(name)] = struct{}{}
+	}
+	for _, name := range always {
+		names[foldName(name)] = struct{}{}
+	}
+	selected := s.Freeze()
+	result := &FieldSet{fields: make([]Field, 0, len(names))}
+	for _, field := range selected.fields {
+		if _, ok := names[foldName(field.Name)]; ok {
+			result.fields = append(result.fields, field)
+		}
+	}
+	return result
+}
+
+// all returns the FieldSet of all fields of the selector's struct type.
+func (s *FieldSelector) all() *FieldSet {
+	if s.d != nil && !reflect.TypeOf(s.d).Comparable() {
+		// The Dialect can't be used as a key.
+		return &FieldSet{fields: makeFieldSlice(s.t, nil, s.d, "", nil, false)}
+	}
+	key := fieldSetKey{s.t, s.d}
+	if set, ok := fieldSets.Load(key); ok {
+		return set.(*FieldSet)
+	}
+	set, _ := fieldSets.LoadOrStore(key, &FieldSet{fields: makeFieldSlice(s.t, nil, s.d, "", nil, false)})
+	return set.(*FieldSet)
+}
+
+// Fields returns the fields of the set. The slice is shared and must not be modified.
+func (fs *FieldSet) Fields() []Field {
+	return fs.fields[:len(fs.fields):len(fs.fields)]
+}
+
+// Names returns the names of the fields.
+func (fs *FieldSet) Names() []string {
+	names := make([]string, len(fs.fields))
+	for i := range fs.fields {
+		names[i] = fs.fields[i].Name
+	}
+	return names
+}
+
+// Len returns the number of fields.
+func (fs *FieldSet) Len() int {
+	return len(fs.fields)
+}
+
+// Only returns a FieldSet of the given fields of the set.
+func (fs *FieldSet) Only(fields ...string) *FieldSet {
+	return fs.filter(nameSet(fields), false)
+}
+
+// Except returns a FieldSet of the fields of the set, except the given fields.
+func (fs *FieldSet) Except(fields ...string) *FieldSet {
+	return fs.filter(nameSet(fields), true)
+}
+
+func (fs *FieldSet) filter(set map[string]struct{}, exclude bool) *FieldSet {
+	result := &FieldSet{fields: make([]Field, 0, len(fs.fields))}
+	for _, field := range fs.fields {
+		if _, inSet := set[field.Name]; inSet != exclude {
+			result.fields = append(result.fields, field)
+		}
+	}
+	return result
+}
+
+func nameSet(names []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		set[name] = struct{}{}
+	}
+	return set
+}
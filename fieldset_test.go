@@ -0,0 +1,45 @@
+package querier
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFreeze(t *testing.T) {
+	all := Fields(&fieldsModel{}).Freeze()
+	if want := Fields(&fieldsModel{}).Select(); !reflect.DeepEqual(all.Fields(), want) {
+		t.Errorf("Freeze().Fields() = %v, want %v", all.Fields(), want)
+	}
+	if Fields(&fieldsModel{}).Freeze() != all {
+		t.Error("Freeze() of the same type isn't cached")
+	}
+	if Fields(&fieldsModel{}).SetDialect(backtickDialect{}).Freeze() == all {
+		t.Error("Freeze() with another Dialect returned the same FieldSet")
+	}
+
+	only := Fields(&fieldsModel{}).Only("ID", "Age").Freeze()
+	if want := []string{"ID", "Age"}; !reflect.DeepEqual(only.Names(), want) {
+		t.Errorf("Names() = %v, want %v", only.Names(), want)
+	}
+	if want := []string{"ID", "Username", "FirstName", "LastName"}; !reflect.DeepEqual(all.Except("Age", "OtherID").Names(), want) {
+		t.Errorf("Except().Names() = %v, want %v", all.Except("Age", "OtherID").Names(), want)
+	}
+	if got := all.Only("Username").Len(); got != 1 {
+		t.Errorf("Only().Len() = %d, want 1", got)
+	}
+
+	// Appending to the fields doesn't modify the set.
+	if fields := all.Fields(); cap(fields) != len(fields) {
+		t.Errorf("cap(Fields()) = %d, want %d", cap(fields), len(fields))
+	}
+}
+
+func TestProject(t *testing.T) {
+	got := Fields(&fieldsModel{}).Except("Age").Project([]string{"firstName", "age", "unknown", "USERNAME"}, "id")
+	if want := []string{"ID", "Username", "FirstName"}; !reflect.DeepEqual(got.Names(), want) {
+		t.Errorf("Project().Names() = %v, want %v", got.Names(), want)
+	}
+	if got := Fields(&fieldsModel{}).Project(nil); got.Len() != 0 {
+		t.Errorf("Project(nil).Len() = %d, want 0", got.Len())
+	}
+}
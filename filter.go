@@ -0,0 +1,98 @@
+package querier
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidFilter means that a filter of BindFilters is invalid, e.g. because of an unknown operator. The error
+// returned is a *FilterError.
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// FilterError is returned by BindFilters for an invalid filter.
+type FilterError struct {
+	// Param is the name of the query parameter, e.g. age[gt].
+	Param string
+	// Reason describes why the filter is invalid.
+	Reason string
+}
+
+func (e *FilterError) Error() string {
+	return fmt.Sprintf("invalid filter %s: %s", e.Param, e.Reason)
+}
+
+// Is returns true if target is ErrInvalidFilter.
+func (e *FilterError) Is(target error) bool {
+	return target == ErrInvalidFilter
+}
+
+// filterOps maps the operators of BindFilters to SQL operators.
+var filterOps = map[string]string{
+	"eq": "=",
+	"ne": "<>",
+	"gt": ">",
+	"lt": "<",
+}
+
+// BindFilters writes the filters in values, e.g. the query parameters of a REST list endpoint, as conditions ANDed
+// together:
+//
+//	q.Write("SELECT * FROM user WHERE")
+//	if err := querier.BindFilters(q, r.URL.Query(), []string{"name", "age", "status"}); err != nil {
+//		// Respond with 400 Bad Request.
+//	}
+//
+// A filter is a parameter of the form field[op]=value, or field=value for eq. The operators are eq, ne, gt, lt, in,
+// whose value is a comma-separated list, and like, which matches values containing the value. For example
+// ?age[gt]=18&status[in]=active,invited writes age > ? AND status IN (?, ?). The values are bound as strings.
+//
+// Only the allowed fields are filtered on, they're written as column names. Parameters of other fields, like page,
+// are ignored, so they can be used by the caller. 1 = 1 is written when there are no filters. An unknown operator
+// results in a *FilterError. The filters are written in the order of their parameter names.
+func BindFilters(q *Q, values url.Values, allowedFields []string) error {
+	allowed := nameSet(allowedFields)
+	var filters []filter
+	for param, vs := range values {
+		field, op := param, "eq"
+		if i := strings.IndexByte(param, '['); i >= 0 && strings.HasSuffix(param, "]") {
+			field, op = param[:i], param[i+1:len(param)-1]
+		}
+		if _, ok := allowed[field]; !ok {
+			continue
+		}
+		if _, ok := filterOps[op]; !ok && op != "in" && op != "like" {
+			return &FilterError{Param: param, Reason: fmt.Sprintf("unknown operator %q", op)}
+		}
+		for _, value := range vs {
+			filters = append(filters, filter{param, field, op, value})
+		}
+	}
+	if len(filters) == 0 {
+		q.Write("1 = 1")
+		return nil
+	}
+	sort.SliceStable(filters, func(i, j int) bool { return filters[i].param < filters[j].param })
+
+	for i, f := range filters {
+		if i > 0 {
+			q.Write("AND")
+		}
+		switch f.op {
+		case "in":
+			q.Write(f.field).In(strings.Split(f.value, ","))
+		case "like":
+			q.WhereLike(f.field, f.value, LikeContains)
+		default:
+			q.WriteValues(f.field+" "+filterOps[f.op]+" {bindVar}", "", f.value)
+		}
+	}
+	return nil
+}
+
+// filter is a filter of BindFilters.
+type filter struct {
+	param, field, op, value string
+}
@@ -0,0 +1,52 @@
+package querier
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestBindFilters(t *testing.T) {
+	allowed := []string{"name", "age", "status"}
+	tests := []struct {
+		query      string
+		want       string
+		wantParams []interface{}
+	}{
+		{"", "WHERE 1 = 1", nil},
+		{"page=2&password=x", "WHERE 1 = 1", nil},
+		{"name=bob", "WHERE name = ?", []interface{}{"bob"}},
+		{"age[gt]=18&age[lt]=65&status[ne]=banned", "WHERE age > ? AND age < ? AND status <> ?",
+			[]interface{}{"18", "65", "banned"}},
+		{"status[in]=active,invited", "WHERE status IN (?, ?)", []interface{}{"active", "invited"}},
+		{"name[like]=50%25", `WHERE name LIKE ? ESCAPE '\'`, []interface{}{`%50\%%`}},
+	}
+	for _, tt := range tests {
+		values, err := url.ParseQuery(tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		q := New(nil, Default{}).Write("WHERE")
+		if err := BindFilters(q, values, allowed); err != nil {
+			t.Errorf("BindFilters(%q) = %v", tt.query, err)
+			continue
+		}
+		if q.String() != tt.want {
+			t.Errorf("BindFilters(%q) wrote %q, want %q", tt.query, q.String(), tt.want)
+		}
+		if !reflect.DeepEqual(q.Params(), tt.wantParams) {
+			t.Errorf("BindFilters(%q) params = %v, want %v", tt.query, q.Params(), tt.wantParams)
+		}
+	}
+
+	q := New(nil, Default{}).Write("WHERE")
+	err := BindFilters(q, url.Values{"name": {"bob"}, "age[between]": {"1,2"}}, allowed)
+	var fe *FilterError
+	if !errors.Is(err, ErrInvalidFilter) || !errors.As(err, &fe) || fe.Param != "age[between]" {
+		t.Errorf("BindFilters() with an unknown operator = %v, want a *FilterError", err)
+	}
+	if q.String() != "WHERE" {
+		t.Errorf("BindFilters() with an invalid filter wrote %q", q.String())
+	}
+}
@@ -0,0 +1,221 @@
+// Package fixtures loads fixtures, rows of tables in files, into a database using Querier, e.g. for tests and demo
+// environments.
+//
+// The fixtures of a table are in a file named after the table, e.g. testdata/fixtures/user.json, containing an array
+// of objects whose keys are column names:
+//
+//	[
+//		{"id": 1, "name": "Alice", "created_at": "2018-01-02T03:04:05Z"},
+//		{"id": 2, "name": "Bob"}
+//	]
+//
+// Every table has a model, a struct whose db tags map the columns to its fields, and the tables it refers to with
+// foreign keys. The tables are loaded in an order in which referenced tables go first:
+//
+//	l := fixtures.New(db, dialect).
+//		Register("user", &User{}).
+//		Register("order", &Order{}, "user")
+//	err := l.Load(ctx, "testdata/fixtures")
+//
+// JSON is built in, other formats are registered with RegisterFormat, e.g. YAML:
+//
+//	fixtures.RegisterFormat(".yaml", yaml.Unmarshal)
+package fixtures
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/semrekkers/querier"
+)
+
+// UnmarshalFunc decodes data into v, like json.Unmarshal.
+type UnmarshalFunc func(data []byte, v interface{}) error
+
+var (
+	formatsMu sync.RWMutex
+	formats   = map[string]UnmarshalFunc{".json": json.Unmarshal}
+)
+
+// RegisterFormat registers the format of fixture files with extension ext, e.g. ".yaml". unmarshal must decode an
+// array of objects into a []map[string]interface{}. The format of a registered extension is replaced.
+func RegisterFormat(ext string, unmarshal UnmarshalFunc) {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+	formats[ext] = unmarshal
+}
+
+// table is a registered table.
+type table struct {
+	name       string
+	model      reflect.Type
+	references []string
+}
+
+// Loader loads fixture files. It is safe for multiple goroutines to call Load once the tables are registered.
+type Loader struct {
+	db     *sql.DB
+	d      querier.Dialect
+	tables map[string]*table
+}
+
+// New returns a new Loader of fixtures into db.
+func New(db *sql.DB, d querier.Dialect) *Loader {
+	return &Loader{db: db, d: d, tables: make(map[string]*table)}
+}
+
+// Register registers table with its model, a pointer to a struct whose db tags map the columns of the fixtures to its
+// fields, and the tables it refers to with foreign keys, which are loaded before it. Panics when model isn't a pointer
+// to a struct.
+func (l *Loader) Register(name string, model interface{}, references ...string) *Loader {
+	t := reflect.TypeOf(model)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		panic("fixtures: model must be a pointer to a struct")
+	}
+	l.tables[name] = &table{name: name, model: t.Elem(), references: references}
+	return l
+}
+
+// Load loads the fixture files of the registered tables in dir, in one transaction. Tables without a fixture file are
+// skipped. The rows are inserted with InsertStruct, so IDs and defaults of the models are set like they are by the
+// application. It returns an error if the foreign keys form a cycle, or if a fixture has a column its model doesn't
+// have.
+func (l *Loader) Load(ctx context.Context, dir string) (err error) {
+	order, err := l.order()
+	if err != nil {
+		return err
+	}
+	rows := make(map[string][]map[string]interface{}, len(order))
+	for _, t := range order {
+		if rows[t.name], err = readFixtures(dir, t.name); err != nil {
+			return err
+		}
+	}
+
+	tx, err := querier.Begin(ctx, l.db, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+	for _, t := range order {
+		for i, row := range rows[t.name] {
+			model := reflect.New(t.model).Interface()
+			if err = decodeRow(model, row); err != nil {
+				return fmt.Errorf("fixtures: %s row %d: %v", t.name, i, err)
+			}
+			if err = querier.New(tx, l.d).InsertStruct(t.name, model).ExecContext(ctx); err != nil {
+				return fmt.Errorf("fixtures: %s row %d: %v", t.name, i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// order returns the registered tables in an order in which referenced tables go first. Tables that don't refer to
+// each other are ordered by name.
+func (l *Loader) order() ([]*table, error) {
+	names := make([]string, 0, len(l.tables))
+	for name := range l.tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int, len(names))
+	order := make([]*table, 0, len(names))
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visiting:
+			return fmt.Errorf("fixtures: foreign keys of table %s form a cycle", name)
+		case visited:
+			return nil
+		}
+		t, ok := l.tables[name]
+		if !ok {
+			// A table without fixtures, e.g. filled by a migration.
+			return nil
+		}
+		state[name] = visiting
+		for _, ref := range t.references {
+			if ref == name {
+				// Rows referring to the same table must be in order.
+				continue
+			}
+			if err := visit(ref); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+		order = append(order, t)
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// readFixtures reads the fixture file of table in dir. It returns no rows if there is none.
+func readFixtures(dir, table string) ([]map[string]interface{}, error) {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	exts := make([]string, 0, len(formats))
+	for ext := range formats {
+		exts = append(exts, ext)
+	}
+	sort.Strings(exts)
+	for _, ext := range exts {
+		data, err := ioutil.ReadFile(filepath.Join(dir, table+ext))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		var rows []map[string]interface{}
+		if err = formats[ext](data, &rows); err != nil {
+			return nil, fmt.Errorf("fixtures: %s%s: %v", table, ext, err)
+		}
+		return rows, nil
+	}
+	return nil, nil
+}
+
+// decodeRow sets the fields of model to the values of the columns of row.
+func decodeRow(model interface{}, row map[string]interface{}) error {
+	values := querier.ScanValues(model)
+	for column, value := range row {
+		field, ok := values[column]
+		if !ok {
+			return fmt.Errorf("model %T has no column %s", model, column)
+		}
+		// The values are decoded by the format, convert them to the type of the field through JSON.
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		if err = json.Unmarshal(data, field.Addr().Interface()); err != nil {
+			return fmt.Errorf("column %s: %v", column, err)
+		}
+	}
+	return nil
+}
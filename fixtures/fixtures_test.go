@@ -0,0 +1,194 @@
+package fixtures
+
+import (
+	"context"
+	"database/sql/driver"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/semrekkers/querier"
+	"github.com/semrekkers/querier/internal/testdriver"
+)
+
+// Base is embedded by pointer, like the base models of applications.
+type Base struct {
+	ID int64 `db:"id,,pk"`
+}
+
+type user struct {
+	*Base
+	Name      string    `db:"name"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type order struct {
+	ID     int64   `db:"id,,pk"`
+	UserID int64   `db:"user_id"`
+	Total  float64 `db:"total"`
+}
+
+// writeFixtures writes the fixture files to a temporary directory, which is removed when the test is finished.
+func writeFixtures(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "fixtures")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	for name, data := range files {
+		if err = ioutil.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// statement is a statement executed by the test driver.
+type statement struct {
+	query string
+	args  []driver.Value
+}
+
+func TestLoad(t *testing.T) {
+	dir := writeFixtures(t, map[string]string{
+		"order.json": `[{"id": 10, "user_id": 1, "total": 9.95}]`,
+		"user.json":  `[{"id": 1, "name": "Alice", "created_at": "2018-01-02T03:04:05Z"}, {"id": 2, "name": "Bob"}]`,
+	})
+	var executed []statement
+	db := testdriver.Open(t, func(query string, args []driver.Value) (*testdriver.Result, error) {
+		executed = append(executed, statement{query, args})
+		return &testdriver.Result{RowsAffected: 1}, nil
+	})
+
+	// The referenced table is loaded first, in a transaction.
+	l := New(db, querier.Default{}).Register("order", &order{}, "user").Register("user", &user{})
+	if err := l.Load(context.Background(), dir); err != nil {
+		t.Fatal(err)
+	}
+	want := []statement{
+		{"BEGIN", nil},
+		{"INSERT INTO user (id, name, created_at) VALUES (?, ?, ?)", []driver.Value{int64(1), "Alice", time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC)}},
+		{"INSERT INTO user (id, name, created_at) VALUES (?, ?, ?)", []driver.Value{int64(2), "Bob", time.Time{}}},
+		{"INSERT INTO order (id, user_id, total) VALUES (?, ?, ?)", []driver.Value{int64(10), int64(1), 9.95}},
+		{"COMMIT", nil},
+	}
+	if len(executed) != len(want) {
+		t.Fatalf("executed %v, want %v", executed, want)
+	}
+	for i := range want {
+		if executed[i].query != want[i].query || len(want[i].args) > 0 && !reflect.DeepEqual(executed[i].args, want[i].args) {
+			t.Errorf("statement %d = %v, want %v", i, executed[i], want[i])
+		}
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		files   map[string]string
+		tables  func(l *Loader)
+		wantErr string
+	}{
+		{
+			map[string]string{"user.json": `[{"id": 1, "email": "alice@example.com"}]`},
+			func(l *Loader) { l.Register("user", &user{}) },
+			"fixtures: user row 0: model *fixtures.user has no column email",
+		},
+		{
+			map[string]string{"user.json": `[{"id": "one"}]`},
+			func(l *Loader) { l.Register("user", &user{}) },
+			"fixtures: user row 0: column id: json: cannot unmarshal string",
+		},
+		{
+			map[string]string{"user.json": `{`},
+			func(l *Loader) { l.Register("user", &user{}) },
+			"fixtures: user.json: unexpected end of JSON input",
+		},
+		{
+			nil,
+			func(l *Loader) { l.Register("user", &user{}, "order").Register("order", &order{}, "user") },
+			"fixtures: foreign keys of table ",
+		},
+	}
+	for _, tt := range tests {
+		dir := writeFixtures(t, tt.files)
+		var queries []string
+		db := testdriver.Open(t, func(query string, args []driver.Value) (*testdriver.Result, error) {
+			queries = append(queries, query)
+			return &testdriver.Result{RowsAffected: 1}, nil
+		})
+		l := New(db, querier.Default{})
+		tt.tables(l)
+		err := l.Load(context.Background(), dir)
+		if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+			t.Errorf("Load() = %v, want %s", err, tt.wantErr)
+		}
+		if len(queries) > 0 && queries[len(queries)-1] != "ROLLBACK" {
+			t.Errorf("executed %q, want it rolled back", queries)
+		}
+	}
+}
+
+func TestLoadWithoutFixtures(t *testing.T) {
+	// Tables without a fixture file are skipped.
+	var queries []string
+	db := testdriver.Open(t, func(query string, args []driver.Value) (*testdriver.Result, error) {
+		queries = append(queries, query)
+		return nil, nil
+	})
+	if err := New(db, querier.Default{}).Register("user", &user{}).Load(context.Background(), writeFixtures(t, nil)); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"BEGIN", "COMMIT"}; !reflect.DeepEqual(queries, want) {
+		t.Errorf("executed %q, want %q", queries, want)
+	}
+}
+
+func TestRegisterFormat(t *testing.T) {
+	// A format of lines of name=value pairs.
+	RegisterFormat(".kv", func(data []byte, v interface{}) error {
+		var rows []map[string]interface{}
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			row := make(map[string]interface{})
+			for _, pair := range strings.Fields(line) {
+				kv := strings.SplitN(pair, "=", 2)
+				row[kv[0]] = kv[1]
+			}
+			rows = append(rows, row)
+		}
+		*v.(*[]map[string]interface{}) = rows
+		return nil
+	})
+	defer func() {
+		formatsMu.Lock()
+		delete(formats, ".kv")
+		formatsMu.Unlock()
+	}()
+
+	var args []driver.Value
+	db := testdriver.Open(t, func(query string, a []driver.Value) (*testdriver.Result, error) {
+		if strings.HasPrefix(query, "INSERT") {
+			args = a
+		}
+		return &testdriver.Result{RowsAffected: 1}, nil
+	})
+	dir := writeFixtures(t, map[string]string{"user.kv": "name=Alice"})
+	if err := New(db, querier.Default{}).Register("user", &user{}).Load(context.Background(), dir); err != nil {
+		t.Fatal(err)
+	}
+	if len(args) != 3 || args[1] != "Alice" {
+		t.Errorf("inserted %v, want Alice", args)
+	}
+}
+
+func TestRegisterPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Register() of a struct value didn't panic")
+		}
+	}()
+	New(nil, querier.Default{}).Register("user", user{})
+}
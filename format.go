@@ -0,0 +1,160 @@
+package querier
+
+import (
+	"bytes"
+	"strings"
+)
+
+// clauseKeywords start a new line when formatting a query. Multi-word keywords are matched as a whole.
+var clauseKeywords = [][]string{
+	{"SELECT"}, {"FROM"}, {"WHERE"}, {"GROUP", "BY"}, {"HAVING"}, {"ORDER", "BY"}, {"LIMIT"}, {"OFFSET"},
+	{"INSERT", "INTO"}, {"VALUES"}, {"UPDATE"}, {"SET"}, {"DELETE", "FROM"},
+	{"LEFT", "OUTER", "JOIN"}, {"RIGHT", "OUTER", "JOIN"}, {"LEFT", "JOIN"}, {"RIGHT", "JOIN"},
+	{"INNER", "JOIN"}, {"CROSS", "JOIN"}, {"JOIN"},
+	{"UNION", "ALL"}, {"UNION"}, {"INTERSECT"}, {"EXCEPT"},
+	{"ON", "DUPLICATE", "KEY", "UPDATE"}, {"RETURNING"}, {"FOR", "UPDATE"}, {"FOR", "SHARE"},
+}
+
+// conditionKeywords start a new, indented line when formatting a query.
+var conditionKeywords = map[string]bool{"AND": true, "OR": true}
+
+const formatIndent = "  "
+
+// FormatSQL re-indents query for readability, e.g. for logging or golden tests. Clauses start on a new line, AND and
+// OR conditions are indented and subqueries are indented by their depth. Quoted strings and identifiers are left
+// untouched.
+func FormatSQL(query string) string {
+	var (
+		tokens, spaced = tokenizeSQL(query)
+		buf            bytes.Buffer
+		depth          int
+		// newline is true if the next token starts a new line.
+		newline bool
+		// between is true inside a BETWEEN expression, its AND is not a condition.
+		between bool
+	)
+	writeNewline := func(extra int) {
+		if buf.Len() > 0 {
+			buf.WriteByte('\n')
+			buf.WriteString(strings.Repeat(formatIndent, depth+extra))
+		}
+		newline = true
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		upper := strings.ToUpper(token)
+
+		if n := matchClause(tokens[i:]); n > 0 {
+			writeNewline(0)
+			buf.WriteString(strings.ToUpper(strings.Join(tokens[i:i+n], Space)))
+			i += n - 1
+			newline = false
+			continue
+		}
+		if conditionKeywords[upper] && !between {
+			writeNewline(1)
+		}
+		if upper == "AND" {
+			between = false
+		} else if upper == "BETWEEN" {
+			between = true
+		}
+
+		switch token {
+		case "(":
+			if !newline && spaced[i] {
+				buf.WriteByte(' ')
+			}
+			buf.WriteByte('(')
+			depth++
+			newline = true
+			continue
+		case ")":
+			if depth > 0 {
+				depth--
+			}
+			buf.WriteByte(')')
+			newline = false
+			continue
+		case ",":
+			buf.WriteByte(',')
+			newline = false
+			continue
+		}
+
+		if !newline && buf.Len() > 0 {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(token)
+		newline = false
+	}
+	return buf.String()
+}
+
+// PrettyString returns the query formatted by FormatSQL.
+func (q *Q) PrettyString() string {
+	return FormatSQL(q.query.String())
+}
+
+// matchClause returns the number of tokens of the clause keyword at the start of tokens, or 0.
+func matchClause(tokens []string) int {
+	for _, keyword := range clauseKeywords {
+		if len(tokens) < len(keyword) {
+			continue
+		}
+		match := true
+		for i, word := range keyword {
+			if !strings.EqualFold(tokens[i], word) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return len(keyword)
+		}
+	}
+	return 0
+}
+
+// tokenizeSQL splits query into words, quoted strings and identifiers, parentheses and commas. spaced reports for every
+// token whether it was preceded by whitespace.
+func tokenizeSQL(query string) (tokens []string, spaced []bool) {
+	space := false
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			space = true
+			i++
+			continue
+		case c == '(' || c == ')' || c == ',':
+			tokens = append(tokens, query[i:i+1])
+			i++
+		default:
+			j := i
+			for j < len(query) {
+				c = query[j]
+				if c == '\'' || c == '"' || c == '`' {
+					// Skip to the end of the quoted string or identifier.
+					end := strings.IndexByte(query[j+1:], c)
+					if end < 0 {
+						j = len(query)
+						break
+					}
+					j += end + 2
+					continue
+				}
+				if c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '(' || c == ')' || c == ',' {
+					break
+				}
+				j++
+			}
+			tokens = append(tokens, query[i:j])
+			i = j
+		}
+		spaced = append(spaced, space)
+		space = false
+	}
+	return
+}
@@ -0,0 +1,28 @@
+package querier
+
+import "testing"
+
+func TestFormatSQL(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{
+			"SELECT id, name FROM user WHERE age > ? AND name = 'a and b' ORDER BY name LIMIT 10",
+			"SELECT id, name\nFROM user\nWHERE age > ?\n  AND name = 'a and b'\nORDER BY name\nLIMIT 10",
+		},
+		{
+			"select count(*) from t left join u on t.id = u.t_id where t.x between 1 and 2 or t.y in (select y from v where z = ?)",
+			"SELECT count(*)\nFROM t\nLEFT JOIN u on t.id = u.t_id\nWHERE t.x between 1 and 2\n  or t.y in (\n  SELECT y\n  FROM v\n  WHERE z = ?)",
+		},
+		{
+			"INSERT INTO t (a, b) VALUES (?, ?)",
+			"INSERT INTO t (a, b)\nVALUES (?, ?)",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := FormatSQL(tt.in); got != tt.want {
+			t.Errorf("FormatSQL(%q) =\n%s\nwant\n%s", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+//go:build go1.18
+// +build go1.18
+
+package querier
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func FuzzWriteFormat(f *testing.F) {
+	for _, format := range []string{"{name} = {bindVar}", "{quotedName} {dataType}", "{", "{}", "{bindVar", "{name:x}", "'{bindVar}'"} {
+		f.Add(format)
+	}
+	f.Fuzz(func(t *testing.T, format string) {
+		q := New(nil, Default{}).WriteFields(format, ", ", Field{Name: "a", DataType: "INT"}, Field{Name: "b"})
+		if !strings.Contains(format, "{") && q.String() != format+", "+format {
+			t.Errorf("WriteFields(%q) = %q, want the format repeated", format, q.String())
+		}
+
+		var bindVars int
+		for _, seg := range compileFormat(nil, format) {
+			switch seg.kind {
+			case segmentName, segmentQuotedName, segmentDataType:
+				// Values only, see writeFormat.
+				return
+			case segmentBindVar:
+				bindVars++
+			}
+		}
+		q = New(nil, Default{}).WriteValues(format, ", ", 1, 2)
+		if len(q.Params()) != 2 {
+			t.Errorf("WriteValues(%q) has %d params, want 2", format, len(q.Params()))
+		}
+		if bindVars > 0 && !strings.Contains(format, "?") {
+			if err := q.checkBuild(); err != nil {
+				t.Errorf("WriteValues(%q) = %v", format, err)
+			}
+		}
+	})
+}
+
+func FuzzParseFieldTag(f *testing.F) {
+	for _, tag := range []string{"", "name", "price,DECIMAL(10,2),sensitive", "id,pk", "a,,enum=x|y", "a,(,)", "a,),(,b"} {
+		f.Add(tag)
+	}
+	f.Fuzz(func(t *testing.T, tag string) {
+		ft := ParseFieldTag(tag)
+		if !strings.HasPrefix(tag, ft.Name) {
+			t.Errorf("ParseFieldTag(%q).Name = %q, not a prefix", tag, ft.Name)
+		}
+		if got := strings.Join(splitTopLevel(tag, ','), ","); got != tag {
+			t.Errorf("splitTopLevel(%q) joined = %q", tag, got)
+		}
+	})
+}
+
+func FuzzWriteNamed(f *testing.F) {
+	for _, query := range []string{
+		"SELECT * FROM user WHERE name = :name",
+		"SELECT ':x', \":y\", `:z` -- :c\n/* :d */ :e::int",
+		"SELECT 'unterminated :x",
+		"/* unterminated :x",
+		":",
+		"::a:b",
+	} {
+		f.Add(query)
+	}
+	f.Fuzz(func(t *testing.T, query string) {
+		New(nil, Default{}).WriteNamed(query, nil)
+		var names int
+		q := New(nil, Default{}).writeNamed(query, func(string) (interface{}, bool) {
+			names++
+			return names, true
+		})
+		if len(q.Params()) != names {
+			t.Errorf("WriteNamed(%q) has %d params, want %d", query, len(q.Params()), names)
+		}
+		if !strings.Contains(query, "?") {
+			if err := q.checkBuild(); err != nil {
+				t.Errorf("WriteNamed(%q) = %v", query, err)
+			}
+		}
+	})
+}
+
+func FuzzWhereTuple(f *testing.F) {
+	f.Add(uint8(2), uint8(2), "IN", true)
+	f.Add(uint8(2), uint8(0), "NOT IN", false)
+	f.Add(uint8(3), uint8(1), "<=", false)
+	f.Add(uint8(0), uint8(1), "=", true)
+	f.Fuzz(func(t *testing.T, nCols, nTuples uint8, op string, rowValues bool) {
+		cols := make([]string, nCols%8)
+		for i := range cols {
+			cols[i] = "c" + string(rune('0'+i))
+		}
+		tuple := make([]interface{}, len(cols))
+		for i := range tuple {
+			tuple[i] = i
+		}
+		values := tuple
+		if strings.Contains(strings.ToUpper(op), "IN") {
+			values = make([]interface{}, nTuples%8)
+			for i := range values {
+				values[i] = tuple
+			}
+		}
+		var d Dialect = Default{}
+		if !rowValues {
+			d = analyticsDialect{}
+		}
+		q := New(nil, d).Write("SELECT * FROM t WHERE").WhereTuple(cols, op, values)
+		err := q.checkBuild()
+		if errors.Is(err, ErrParamMismatch) {
+			t.Errorf("WhereTuple(%v, %q, %v) = %v", cols, op, values, err)
+		}
+		if len(cols) == 0 && err == nil {
+			t.Errorf("WhereTuple without columns = %q, want an error", q.String())
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package querier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// HealthTimeout limits the time of HealthCheck when its context has no deadline.
+const HealthTimeout = 5 * time.Second
+
+// HealthChecker can be implemented by a Dialect to check the connection with a query other than SELECT 1.
+type HealthChecker interface {
+	// HealthQuery returns a cheap query that returns a single row.
+	HealthQuery() string
+}
+
+// HealthQuery returns SELECT 1.
+func (Default) HealthQuery() string {
+	return "SELECT 1"
+}
+
+// HealthCheck checks the connection to the database with a cheap query. If ctx has no deadline the check is limited
+// to HealthTimeout. A failed check is kept as the database's last error, see HealthHandler.
+func (db *DB) HealthCheck(ctx context.Context) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, HealthTimeout)
+		defer cancel()
+	}
+	checker, ok := db.d.(HealthChecker)
+	if !ok {
+		checker = Default{}
+	}
+	var ignore interface{}
+	err := db.New().Write(checker.HealthQuery()).ScanContext(ctx, &ignore)
+	if err != nil {
+		root := db.rootDB()
+		root.mu.Lock()
+		root.lastErr, root.lastErrAt = err, time.Now()
+		root.mu.Unlock()
+	}
+	return err
+}
+
+// healthStatus is the response of HealthHandler.
+type healthStatus struct {
+	Status      string      `json:"status"`
+	Error       string      `json:"error,omitempty"`
+	LastError   string      `json:"last_error,omitempty"`
+	LastErrorAt *time.Time  `json:"last_error_at,omitempty"`
+	Stats       healthStats `json:"stats"`
+}
+
+type healthStats struct {
+	MaxOpenConnections int           `json:"max_open_connections"`
+	OpenConnections    int           `json:"open_connections"`
+	InUse              int           `json:"in_use"`
+	Idle               int           `json:"idle"`
+	WaitCount          int64         `json:"wait_count"`
+	WaitDuration       time.Duration `json:"wait_duration"`
+}
+
+// HealthHandler returns a handler for readiness probes. It runs HealthCheck and responds with the status, the
+// connection pool statistics and the last error as JSON. The status code is 503 if the check failed.
+func (db *DB) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := healthStatus{Status: "ok"}
+		code := http.StatusOK
+		if err := db.HealthCheck(r.Context()); err != nil {
+			status.Status, status.Error = "error", err.Error()
+			code = http.StatusServiceUnavailable
+		}
+		root := db.rootDB()
+		root.mu.Lock()
+		if root.lastErr != nil {
+			lastErrAt := root.lastErrAt
+			status.LastError, status.LastErrorAt = root.lastErr.Error(), &lastErrAt
+		}
+		root.mu.Unlock()
+		stats := db.Stats()
+		status.Stats = healthStats{
+			MaxOpenConnections: stats.MaxOpenConnections,
+			OpenConnections:    stats.OpenConnections,
+			InUse:              stats.InUse,
+			Idle:               stats.Idle,
+			WaitCount:          stats.WaitCount,
+			WaitDuration:       stats.WaitDuration,
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(&status)
+	})
+}
@@ -0,0 +1,54 @@
+package querier
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHealthCheck(t *testing.T) {
+	var down bool
+	errDown := errors.New("database is down")
+	db := NewDB(openTestDB(t, func(query string, _ []driver.Value) (*testRows, error) {
+		if down {
+			return nil, errDown
+		}
+		if query != "SELECT 1" {
+			t.Errorf("health query %q, want SELECT 1", query)
+		}
+		return &testRows{columns: []string{"1"}, values: [][]driver.Value{{int64(1)}}}, nil
+	}), Default{})
+	defer db.Close()
+
+	if err := db.HealthCheck(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	db.HealthHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/ready", nil))
+	var status healthStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusOK || status.Status != "ok" || status.LastError != "" {
+		t.Errorf("healthy response %d %+v", rec.Code, status)
+	}
+
+	down = true
+	rec = httptest.NewRecorder()
+	db.HealthHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/ready", nil))
+	status = healthStatus{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusServiceUnavailable || status.Status != "error" || status.LastError != errDown.Error() {
+		t.Errorf("unhealthy response %d %+v", rec.Code, status)
+	}
+	if status.LastErrorAt == nil {
+		t.Error("unhealthy response has no last error time")
+	}
+}
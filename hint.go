@@ -0,0 +1,67 @@
+package querier
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Hinter can be implemented by a Dialect to place optimizer hints in a query. Dialects that don't implement it use the
+// default, which prepends the hints as a comment for pg_hint_plan.
+type Hinter interface {
+	// PlaceHints returns query with hints, e.g. by writing /*+ hints */ at the right position.
+	PlaceHints(query string, hints []string) string
+}
+
+// Hint adds an optimizer hint to the query, e.g.
+//
+//	q.Write("SELECT * FROM user WHERE email = ?", email).Hint("MAX_EXECUTION_TIME(1000)")
+//
+// The hints are placed by the Dialect when the query is executed, see Hinter. Index hints of the optimizer hint
+// comment can be used as well, like INDEX(user idx_email) for MySQL or IndexScan(user idx_email) for pg_hint_plan.
+// The hints of composed queries are merged into the hints of the statement.
+func (q *Q) Hint(hint string) *Q {
+	if strings.Contains(hint, "*/") {
+		q.fail(fmt.Errorf("invalid hint %q", hint))
+		return q
+	}
+	q.hints = append(q.hints, hint)
+	return q
+}
+
+// PlaceHints prepends the hints to query as an optimizer hint comment.
+func (Default) PlaceHints(query string, hints []string) string {
+	return hintComment(hints) + " " + query
+}
+
+// placeHints returns query with the hints of the Querier.
+func (q *Q) placeHints(query string) string {
+	if len(q.hints) == 0 {
+		return query
+	}
+	hinter, ok := q.d.(Hinter)
+	if !ok {
+		hinter = Default{}
+	}
+	return hinter.PlaceHints(query, q.hints)
+}
+
+// hintComment returns the optimizer hint comment of hints.
+func hintComment(hints []string) string {
+	return "/*+ " + strings.Join(hints, " ") + " */"
+}
+
+// HintAfterKeyword returns query with the hints written after its leading keyword, e.g. SELECT /*+ hints */ ..., as
+// required by MySQL and Oracle. Queries starting with another word or a parenthesis get the hints prepended.
+func HintAfterKeyword(query string, hints []string) string {
+	trimmed := strings.TrimLeft(query, " \t\r\n")
+	end := strings.IndexAny(trimmed, " \t\r\n")
+	if end < 0 {
+		end = len(trimmed)
+	}
+	switch strings.ToUpper(trimmed[:end]) {
+	case "SELECT", "INSERT", "REPLACE", "UPDATE", "DELETE":
+		offset := len(query) - len(trimmed) + end
+		return query[:offset] + " " + hintComment(hints) + query[offset:]
+	}
+	return hintComment(hints) + " " + query
+}
@@ -0,0 +1,75 @@
+package querier
+
+import (
+	"reflect"
+	"testing"
+)
+
+type keywordHintDialect struct {
+	Default
+}
+
+func (keywordHintDialect) PlaceHints(query string, hints []string) string {
+	return HintAfterKeyword(query, hints)
+}
+
+func TestHint(t *testing.T) {
+	var ex testExecutor
+	err := New(&ex, Default{}).
+		Write("UPDATE user SET active = ?", false).
+		Hint("Set(statement_timeout 1000)").
+		Comment("service=admin").
+		Exec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = New(&ex, keywordHintDialect{}).
+		Write("DELETE FROM user WHERE active = ?", false).
+		Hint("MAX_EXECUTION_TIME(1000)").
+		Hint("INDEX(user idx_active)").
+		Exec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]interface{}{
+		{"/*+ Set(statement_timeout 1000) */ UPDATE user SET active = ? /*service='admin'*/", false},
+		{"DELETE /*+ MAX_EXECUTION_TIME(1000) INDEX(user idx_active) */ FROM user WHERE active = ?", false},
+	}
+	if !reflect.DeepEqual(ex.queries, want) {
+		t.Errorf("executed %v, want %v", ex.queries, want)
+	}
+
+	if err = New(&ex, Default{}).Write("SELECT 1").Hint("*/ DROP TABLE user").Exec(); err == nil {
+		t.Error("Exec() with invalid hint returned no error")
+	}
+}
+
+func TestHintComposed(t *testing.T) {
+	var ex testExecutor
+	sub := New(&ex, keywordHintDialect{}).Write("SELECT id FROM user").Hint("MAX_EXECUTION_TIME(1000)")
+	err := New(&ex, keywordHintDialect{}).InsertFromSelect("archive", nil, sub).Exec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]interface{}{{"INSERT /*+ MAX_EXECUTION_TIME(1000) */ INTO archive SELECT id FROM user"}}
+	if !reflect.DeepEqual(ex.queries, want) {
+		t.Errorf("executed %v, want %v", ex.queries, want)
+	}
+}
+
+func TestHintAfterKeyword(t *testing.T) {
+	tests := []struct {
+		query, want string
+	}{
+		{"SELECT * FROM user", "SELECT /*+ H */ * FROM user"},
+		{"\n  select *\nFROM user", "\n  select /*+ H */ *\nFROM user"},
+		{"SELECT", "SELECT /*+ H */"},
+		{"(SELECT 1) UNION (SELECT 2)", "/*+ H */ (SELECT 1) UNION (SELECT 2)"},
+		{"WITH x AS (SELECT 1) SELECT * FROM x", "/*+ H */ WITH x AS (SELECT 1) SELECT * FROM x"},
+	}
+	for _, test := range tests {
+		if got := HintAfterKeyword(test.query, []string{"H"}); got != test.want {
+			t.Errorf("HintAfterKeyword(%q) = %q, want %q", test.query, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package querier
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IdentQuoter can be implemented by a Dialect to quote identifiers. Dialects that don't implement it use the default,
+// ANSI SQL quoting with double quotes.
+type IdentQuoter interface {
+	// QuoteIdent quotes identifier name. name is a valid identifier.
+	QuoteIdent(name string) string
+}
+
+// IdentError is returned when an identifier or a value interpolated by Writef in strict mode is invalid.
+type IdentError struct {
+	Ident string
+}
+
+func (e *IdentError) Error() string {
+	return fmt.Sprintf("invalid identifier %q", e.Ident)
+}
+
+// QuoteIdent quotes name with double quotes.
+func (Default) QuoteIdent(name string) string {
+	return `"` + name + `"`
+}
+
+// SetStrict sets strict mode. In strict mode Writef only accepts arguments consisting of letters, digits and the
+// characters '_', '$' and '.', which covers identifiers and numbers. Invalid arguments result in an *IdentError when
+// the query is executed.
+func (q *Q) SetStrict(strict bool) *Q {
+	q.strict = strict
+	return q
+}
+
+// WriteIdent validates and quotes identifier name and writes it to the Querier.
+func (q *Q) WriteIdent(name string) *Q {
+	q.writeSep()
+	q.writeIdent(name)
+	q.record(SegmentIdent, name, nil, len(q.params))
+	return q
+}
+
+// WriteTable validates and quotes table name, which may be qualified by a schema name, e.g. "schema.table", and writes
+// it to the Querier. An unqualified name is qualified with the schema of the Querier, see SetSchema.
+func (q *Q) WriteTable(name string) *Q {
+	q.writeSep()
+	for i, part := range strings.SplitN(q.Qualify(name), ".", 2) {
+		if i > 0 {
+			q.query.WriteByte('.')
+		}
+		q.writeIdent(part)
+	}
+	q.record(SegmentIdent, name, nil, len(q.params))
+	return q
+}
+
+func (q *Q) writeIdent(name string) {
+	if !isIdent(name) {
+		q.fail(&IdentError{Ident: name})
+		return
+	}
+	q.query.WriteString(q.quoteIdent(name))
+}
+
+// quoteIdent quotes name with the Dialect's IdentQuoter.
+func (q *Q) quoteIdent(name string) string {
+	quoter, ok := q.d.(IdentQuoter)
+	if !ok {
+		quoter = Default{}
+	}
+	return quoter.QuoteIdent(name)
+}
+
+// checkStrict checks the arguments of Writef in strict mode.
+func (q *Q) checkStrict(args []interface{}) {
+	for _, arg := range args {
+		s := fmt.Sprint(arg)
+		if s == "" || strings.TrimFunc(s, isSafeRune) != "" {
+			q.fail(&IdentError{Ident: s})
+		}
+	}
+}
+
+// isIdent returns true if s is a valid unquoted identifier.
+func isIdent(s string) bool {
+	if s == "" || (s[0] >= '0' && s[0] <= '9') {
+		return false
+	}
+	for _, r := range s {
+		if !isIdentRune(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || r == '$' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
+
+func isSafeRune(r rune) bool {
+	return isIdentRune(r) || r == '.'
+}
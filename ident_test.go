@@ -0,0 +1,77 @@
+package querier
+
+import "testing"
+
+type backtickDialect struct {
+	Default
+}
+
+func (backtickDialect) QuoteIdent(name string) string {
+	return "`" + name + "`"
+}
+
+func TestWriteIdent(t *testing.T) {
+	tests := []struct {
+		d       Dialect
+		build   func(q *Q)
+		want    string
+		wantErr bool
+	}{
+		{Default{}, func(q *Q) { q.Write("SELECT").WriteIdent("order").Write("FROM").WriteTable("shop.orders") }, `SELECT "order" FROM "shop"."orders"`, false},
+		{backtickDialect{}, func(q *Q) { q.Write("SELECT").WriteIdent("group").Write("FROM").WriteTable("t") }, "SELECT `group` FROM `t`", false},
+		{Default{}, func(q *Q) { q.Write("SELECT").WriteIdent(`a"; DROP TABLE users; --`) }, "", true},
+		{Default{}, func(q *Q) { q.Write("SELECT * FROM").WriteTable("a.b.c") }, "", true},
+		{Default{}, func(q *Q) { q.Write("SELECT").WriteIdent("1abc") }, "", true},
+	}
+
+	for _, tt := range tests {
+		q := New(&testExecutor{}, tt.d)
+		tt.build(q)
+		err := q.Exec()
+		if _, isIdentErr := err.(*IdentError); isIdentErr != tt.wantErr {
+			t.Errorf("Exec() of %q error = %v, want ident error %v", q.String(), err, tt.wantErr)
+		}
+		if !tt.wantErr && q.String() != tt.want {
+			t.Errorf("String() = %q, want %q", q.String(), tt.want)
+		}
+	}
+}
+
+func TestWritefStrict(t *testing.T) {
+	tests := []struct {
+		args    []interface{}
+		wantErr bool
+	}{
+		{[]interface{}{"users", 10}, false},
+		{[]interface{}{"shop.users", 10}, false},
+		{[]interface{}{"users; DROP TABLE users", 10}, true},
+		{[]interface{}{"users", "10 OR 1=1"}, true},
+		{[]interface{}{"", 10}, true},
+	}
+
+	for _, tt := range tests {
+		q := New(&testExecutor{}, Default{}).SetStrict(true)
+		err := q.Writef("SELECT * FROM %s LIMIT %v", tt.args...).Exec()
+		if _, isIdentErr := err.(*IdentError); isIdentErr != tt.wantErr {
+			t.Errorf("Writef(%v) error = %v, want ident error %v", tt.args, err, tt.wantErr)
+		}
+	}
+}
+
+func TestQuotedNamePlaceholder(t *testing.T) {
+	fields := []Field{{Name: "id"}, {Name: "order"}}
+	q := New(nil, Default{}).Write("SELECT").WriteFields("{quotedName}", FieldSep, fields...)
+	if want := `SELECT "id", "order"`; q.String() != want {
+		t.Errorf("String() = %q, want %q", q.String(), want)
+	}
+
+	type item struct {
+		Group string `db:"group"`
+	}
+	i := &item{Group: "foo"}
+	q = New(nil, backtickDialect{}).Write("UPDATE item SET").
+		WriteValueMap("{quotedName} = {bindVar}", FieldSep, Values(i), Fields(i).Select()...)
+	if want := "UPDATE item SET `group` = ?"; q.String() != want {
+		t.Errorf("String() = %q, want %q", q.String(), want)
+	}
+}
@@ -0,0 +1,200 @@
+package querier
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// tagOptionID sets the IDGenerator that InsertStruct generates the value of a field with its zero value with, e.g.
+// `db:"id,,pk,id=uuidv7"`.
+const tagOptionID = "id"
+
+// IDGenerator generates a new unique ID, e.g. a string or int64. It's converted to the type of the field it's
+// generated for, integers are formatted in decimal for string fields.
+type IDGenerator func() (interface{}, error)
+
+var (
+	idGeneratorsMu sync.RWMutex
+	idGenerators   = map[string]IDGenerator{
+		"uuidv7": NewUUIDv7,
+		"ksuid":  NewKSUID,
+	}
+)
+
+// RegisterIDGenerator registers the IDGenerator name, for fields tagged with id=name. The generators uuidv7 and ksuid
+// are built-in, a snowflake generator needs the ID of the node and is registered by the application, e.g.
+//
+//	querier.RegisterIDGenerator("snowflake", querier.NewSnowflake(nodeID))
+//
+// Generators must be registered before InsertStruct is used, usually in an init function. A registered generator
+// replaces the previous one of name.
+func RegisterIDGenerator(name string, gen IDGenerator) {
+	idGeneratorsMu.Lock()
+	defer idGeneratorsMu.Unlock()
+	idGenerators[name] = gen
+}
+
+// generateIDs sets the fields with an IDGenerator that have their zero value to a new ID.
+func generateIDs(fields []Field, values ValueMap) error {
+	for _, field := range fields {
+		if field.IDGenerator == "" || !isZeroValue(values[field.Name]) {
+			continue
+		}
+		idGeneratorsMu.RLock()
+		gen, ok := idGenerators[field.IDGenerator]
+		idGeneratorsMu.RUnlock()
+		if !ok {
+			return fmt.Errorf("field %s: unknown ID generator %q", field.Name, field.IDGenerator)
+		}
+		id, err := gen()
+		if err != nil {
+			return fmt.Errorf("field %s: %v", field.Name, err)
+		}
+		dest := values[field.Name]
+		v, ok := convertID(reflect.ValueOf(id), dest.Type())
+		if !ok {
+			return fmt.Errorf("field %s: ID of type %T can't be converted to %s", field.Name, id, dest.Type())
+		}
+		dest.Set(v)
+	}
+	return nil
+}
+
+// convertID converts the ID v to type t. Integers are formatted in decimal for string types and must fit integer
+// types, other IDs only convert to types of the same kind.
+func convertID(v reflect.Value, t reflect.Type) (reflect.Value, bool) {
+	switch {
+	case isIntKind(v.Kind()) && t.Kind() == reflect.String:
+		return reflect.ValueOf(strconv.FormatInt(v.Int(), 10)).Convert(t), true
+	case isUintKind(v.Kind()) && t.Kind() == reflect.String:
+		return reflect.ValueOf(strconv.FormatUint(v.Uint(), 10)).Convert(t), true
+	case isIntKind(v.Kind()) && isIntKind(t.Kind()):
+		return v.Convert(t), !reflect.Zero(t).OverflowInt(v.Int())
+	case isIntKind(v.Kind()) && isUintKind(t.Kind()):
+		return v.Convert(t), v.Int() >= 0 && !reflect.Zero(t).OverflowUint(uint64(v.Int()))
+	case isUintKind(v.Kind()) && isUintKind(t.Kind()):
+		return v.Convert(t), !reflect.Zero(t).OverflowUint(v.Uint())
+	case isUintKind(v.Kind()) && isIntKind(t.Kind()):
+		return v.Convert(t), v.Uint() <= 1<<63-1 && !reflect.Zero(t).OverflowInt(int64(v.Uint()))
+	case v.Kind() == t.Kind() && v.Type().ConvertibleTo(t):
+		return v.Convert(t), true
+	}
+	return reflect.Value{}, false
+}
+
+func isIntKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isUintKind(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uintptr
+}
+
+// NewUUIDv7 returns a new version 7 UUID as a string, e.g. 01890a5d-ac96-774b-bcce-b302099a8057. Version 7 UUIDs
+// start with the Unix time in milliseconds, so they sort by creation time.
+func NewUUIDv7() (interface{}, error) {
+	var u [16]byte
+	if _, err := rand.Read(u[6:]); err != nil {
+		return nil, err
+	}
+	ms := uint64(time.Now().UnixNano() / int64(time.Millisecond))
+	u[0], u[1], u[2], u[3], u[4], u[5] = byte(ms>>40), byte(ms>>32), byte(ms>>24), byte(ms>>16), byte(ms>>8), byte(ms)
+	u[6] = u[6]&0x0f | 0x70 // Version 7.
+	u[8] = u[8]&0x3f | 0x80 // RFC 4122 variant.
+
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf[:]), nil
+}
+
+// ksuidEpoch is the epoch of KSUIDs, 2014-05-13 16:53:20 UTC.
+const ksuidEpoch = 1400000000
+
+const base62Digits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// NewKSUID returns a new KSUID, a string of 27 characters that starts with the time in seconds, so KSUIDs sort by
+// creation time.
+func NewKSUID() (interface{}, error) {
+	var b [20]byte
+	binary.BigEndian.PutUint32(b[:4], uint32(time.Now().Unix()-ksuidEpoch))
+	if _, err := rand.Read(b[4:]); err != nil {
+		return nil, err
+	}
+	return encodeKSUID(b), nil
+}
+
+// encodeKSUID returns the base62 encoding of the 160-bit number b, padded to 27 characters.
+func encodeKSUID(b [20]byte) string {
+	// Long division by 62, the digits are written from the end.
+	var out [27]byte
+	parts := [5]uint32{}
+	for i := range parts {
+		parts[i] = binary.BigEndian.Uint32(b[i*4:])
+	}
+	for i := len(out) - 1; i >= 0; i-- {
+		var rem uint64
+		for j := range parts {
+			acc := rem<<32 | uint64(parts[j])
+			parts[j] = uint32(acc / 62)
+			rem = acc % 62
+		}
+		out[i] = base62Digits[rem]
+	}
+	return string(out[:])
+}
+
+// snowflakeEpoch is the epoch of snowflake IDs, 2010-11-04 01:42:54.657 UTC.
+const snowflakeEpoch = 1288834974657
+
+var errSnowflakeClock = errors.New("clock moved backwards")
+
+// NewSnowflake returns an IDGenerator of snowflake IDs of node, an int64 of the time in milliseconds, node and a
+// sequence number, so IDs sort by creation time. Up to 4096 IDs are generated per millisecond per node. Every process
+// generating IDs must have a unique node from 0 to 1023. Panics if node is out of range.
+func NewSnowflake(node int64) IDGenerator {
+	if node < 0 || node > 1023 {
+		panic("snowflake node must be from 0 to 1023")
+	}
+	var (
+		mu   sync.Mutex
+		last int64
+		seq  int64
+	)
+	return func() (interface{}, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		now := time.Now().UnixNano()/int64(time.Millisecond) - snowflakeEpoch
+		if now < last {
+			return nil, errSnowflakeClock
+		}
+		if now == last {
+			seq = (seq + 1) & 4095
+			if seq == 0 {
+				// The sequence of this millisecond is exhausted, wait for the next.
+				for now <= last {
+					time.Sleep(100 * time.Microsecond)
+					now = time.Now().UnixNano()/int64(time.Millisecond) - snowflakeEpoch
+				}
+			}
+		} else {
+			seq = 0
+		}
+		last = now
+		return now<<22 | node<<12 | seq, nil
+	}
+}
@@ -0,0 +1,97 @@
+package querier
+
+import (
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+func TestInsertStructIDGenerator(t *testing.T) {
+	type event struct {
+		ID   string `db:"id,,pk,id=uuidv7"`
+		Name string `db:"name"`
+	}
+	e := event{Name: "foo"}
+	q := New(nil, Default{}).InsertStruct("event", &e)
+	if err := q.checkBuild(); err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(e.ID) {
+		t.Errorf("ID = %q, want a UUIDv7", e.ID)
+	}
+	e = event{ID: "existing"}
+	New(nil, Default{}).InsertStruct("event", &e)
+	if e.ID != "existing" {
+		t.Errorf("ID = %q, want it unchanged", e.ID)
+	}
+
+	RegisterIDGenerator("test-snowflake", NewSnowflake(5))
+	type order struct {
+		ID int64 `db:"id,,pk,id=test-snowflake"`
+	}
+	var o order
+	New(nil, Default{}).InsertStruct("orders", &o)
+	if o.ID == 0 || o.ID>>12&1023 != 5 {
+		t.Errorf("ID = %d, want a snowflake ID of node 5", o.ID)
+	}
+
+	// Snowflake IDs are formatted in decimal for string fields.
+	type textOrder struct {
+		ID string `db:"id,,pk,id=test-snowflake"`
+	}
+	var to textOrder
+	if err := New(&testExecutor{}, Default{}).InsertStruct("orders", &to).Exec(); err != nil {
+		t.Fatal(err)
+	}
+	if id, err := strconv.ParseInt(to.ID, 10, 64); err != nil || id>>12&1023 != 5 {
+		t.Errorf("ID = %q, want a snowflake ID of node 5 in decimal", to.ID)
+	}
+	type smallOrder struct {
+		ID int32 `db:"id,,pk,id=test-snowflake"`
+	}
+	if err := New(&testExecutor{}, Default{}).InsertStruct("orders", &smallOrder{}).Exec(); err == nil {
+		t.Error("Exec() with a snowflake ID for an int32 field returned no error")
+	}
+
+	type invalid struct {
+		ID int64 `db:"id,,pk,id=ksuid"`
+	}
+	if err := New(&testExecutor{}, Default{}).InsertStruct("invalid", &invalid{}).Exec(); err == nil {
+		t.Error("Exec() with a string ID for an int64 field returned no error")
+	}
+	type unknown struct {
+		ID string `db:"id,,pk,id=unknown"`
+	}
+	if err := New(&testExecutor{}, Default{}).InsertStruct("unknown", &unknown{}).Exec(); err == nil {
+		t.Error("Exec() with an unknown ID generator returned no error")
+	}
+}
+
+func TestIDGenerators(t *testing.T) {
+	ones := [20]byte{}
+	for i := range ones {
+		ones[i] = 0xff
+	}
+	if got, want := encodeKSUID(ones), "aWgEPTl1tmebfsQzFP4bxwgy80V"; got != want {
+		t.Errorf("encodeKSUID(max) = %q, want %q", got, want)
+	}
+	if got, want := encodeKSUID([20]byte{}), "000000000000000000000000000"; got != want {
+		t.Errorf("encodeKSUID(0) = %q, want %q", got, want)
+	}
+	if id, err := NewKSUID(); err != nil || len(id.(string)) != 27 {
+		t.Errorf("NewKSUID() = %v, %v", id, err)
+	}
+
+	snowflake := NewSnowflake(1)
+	var prev int64
+	for i := 0; i < 5000; i++ {
+		id, err := snowflake()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id.(int64) <= prev {
+			t.Fatalf("snowflake ID %d isn't greater than %d", id, prev)
+		}
+		prev = id.(int64)
+	}
+}
@@ -0,0 +1,56 @@
+// Package table contains the helpers of the packages that keep their rows in a table of the application, like queue
+// and outbox.
+package table
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/semrekkers/querier"
+)
+
+// NewID returns a new random ID of 32 hexadecimal characters, for a CHAR(32) column.
+func NewID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b[:]), nil
+}
+
+// CanSkipLocked returns true if dialect d supports FOR UPDATE SKIP LOCKED.
+func CanSkipLocked(d querier.Dialect) bool {
+	locker, ok := d.(querier.Locker)
+	if !ok {
+		locker = querier.Default{}
+	}
+	_, forUpdate := locker.LockClause(querier.LockForUpdate)
+	_, skipLocked := locker.LockClause(querier.LockSkipLocked)
+	return forUpdate && skipLocked
+}
+
+// Model implements the migrator's Model of a table whose primary key is its id column. It's embedded in a struct with
+// the row of the table:
+//
+//	type model struct {
+//		Job
+//		table.Model
+//	}
+type Model struct {
+	Name string `db:"-"`
+}
+
+// TableName returns the name of the table.
+func (m *Model) TableName() string {
+	return m.Name
+}
+
+// CreateTable writes the primary key.
+func (m *Model) CreateTable(q *querier.Q) {
+	q.Write("PRIMARY KEY (id)")
+}
+
+// Migrate does nothing.
+func (m *Model) Migrate(*querier.Q, string) error {
+	return nil
+}
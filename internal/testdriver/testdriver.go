@@ -0,0 +1,172 @@
+// Package testdriver provides a database/sql driver for the tests of the subpackages, whose statements are served by a
+// function of the test instead of a database.
+package testdriver
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// Result is the result of a statement: the rows of a query, or the number of rows affected by an Exec.
+type Result struct {
+	Columns      []string
+	Rows         [][]driver.Value
+	RowsAffected int64
+}
+
+// Handler serves a statement, a nil Result is an empty one. Transactions are passed as the statements BEGIN, COMMIT
+// and ROLLBACK.
+type Handler func(query string, args []driver.Value) (*Result, error)
+
+// DriverName is the name the driver is registered with.
+const DriverName = "querier-testdriver"
+
+var (
+	registerOnce sync.Once
+	mu           sync.Mutex
+	handlers     = make(map[string]Handler)
+)
+
+// DSN registers fn, it returns the data source name of a database whose statements are served by fn.
+func DSN(fn Handler) string {
+	registerOnce.Do(func() {
+		sql.Register(DriverName, testDriver{})
+	})
+	mu.Lock()
+	defer mu.Unlock()
+	dsn := strconv.Itoa(len(handlers))
+	handlers[dsn] = fn
+	return dsn
+}
+
+// Open opens a database whose statements are served by fn. It's closed when the test is finished.
+func Open(t testing.TB, fn Handler) *sql.DB {
+	t.Helper()
+	db, err := sql.Open(DriverName, DSN(fn))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+type testDriver struct{}
+
+func (testDriver) Open(dsn string) (driver.Conn, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	return &conn{fn: handlers[dsn]}, nil
+}
+
+type conn struct {
+	fn Handler
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return &stmt{c: c, query: query}, nil
+}
+
+func (c *conn) Close() error { return nil }
+
+func (c *conn) Begin() (driver.Tx, error) {
+	if _, err := c.fn("BEGIN", nil); err != nil {
+		return nil, err
+	}
+	return tx{c}, nil
+}
+
+func (c *conn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	result, err := c.fn(query, values(args))
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		result = &Result{}
+	}
+	return &rows{columns: result.Columns, values: result.Rows}, nil
+}
+
+func (c *conn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	result, err := c.fn(query, values(args))
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		result = &Result{}
+	}
+	return execResult(result.RowsAffected), nil
+}
+
+// execResult reports the rows affected, and no last insert id.
+type execResult int64
+
+func (r execResult) LastInsertId() (int64, error) { return 0, nil }
+func (r execResult) RowsAffected() (int64, error) { return int64(r), nil }
+
+type stmt struct {
+	c     *conn
+	query string
+}
+
+func (s *stmt) Close() error  { return nil }
+func (s *stmt) NumInput() int { return -1 }
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.c.ExecContext(context.Background(), s.query, namedValues(args))
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.c.QueryContext(context.Background(), s.query, namedValues(args))
+}
+
+type tx struct {
+	c *conn
+}
+
+func (t tx) Commit() error {
+	_, err := t.c.fn("COMMIT", nil)
+	return err
+}
+
+func (t tx) Rollback() error {
+	_, err := t.c.fn("ROLLBACK", nil)
+	return err
+}
+
+type rows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *rows) Columns() []string { return r.columns }
+func (r *rows) Close() error      { return nil }
+
+func (r *rows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func values(args []driver.NamedValue) []driver.Value {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	return values
+}
+
+func namedValues(values []driver.Value) []driver.NamedValue {
+	args := make([]driver.NamedValue, len(values))
+	for i, v := range values {
+		args[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
+	}
+	return args
+}
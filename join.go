@@ -0,0 +1,18 @@
+//go:build !go1.20
+// +build !go1.20
+
+package querier
+
+// joinErrors returns errs joined as Errors, without the nil errors, or nil if there are none.
+func joinErrors(errs []error) error {
+	var joined Errors
+	for _, err := range errs {
+		if err != nil {
+			joined = append(joined, err)
+		}
+	}
+	if joined == nil {
+		return nil
+	}
+	return joined
+}
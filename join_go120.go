@@ -0,0 +1,11 @@
+//go:build go1.20
+// +build go1.20
+
+package querier
+
+import "errors"
+
+// joinErrors returns errs joined with errors.Join, without the nil errors, or nil if there are none.
+func joinErrors(errs []error) error {
+	return errors.Join(errs...)
+}
@@ -0,0 +1,21 @@
+//go:build go1.20
+// +build go1.20
+
+package querier
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDeferErrMessage(t *testing.T) {
+	var ex testExecutor
+	errCleanup := errors.New("cleanup failed")
+	err := New(&ex, Default{}).SetOptions(Options{DeferredErrors: true}).
+		Write("SELECT * FROM user").
+		DeferErr(func(*Q) error { return errCleanup }).
+		Find(&[]struct{}{})
+	if got, want := err.Error(), errTestQuery.Error()+"\n"+errCleanup.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
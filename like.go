@@ -0,0 +1,56 @@
+package querier
+
+import "strings"
+
+// LikeMode is the match mode of WhereLike.
+type LikeMode int
+
+const (
+	// LikeContains matches values containing the input.
+	LikeContains LikeMode = iota
+	// LikePrefix matches values starting with the input.
+	LikePrefix
+	// LikeSuffix matches values ending with the input.
+	LikeSuffix
+	// LikeExact matches values equal to the input.
+	LikeExact
+)
+
+// LikeEscaper can be implemented by a Dialect to write the ESCAPE clause for patterns escaped by EscapeLike. Dialects
+// that don't implement it use the default, ESCAPE '\'.
+type LikeEscaper interface {
+	// LikeEscape returns the ESCAPE clause for a backslash.
+	LikeEscape() string
+}
+
+var likeReplacer = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// EscapeLike escapes the wildcards % and _ in s with a backslash, so s matches literally in a LIKE pattern.
+func EscapeLike(s string) string {
+	return likeReplacer.Replace(s)
+}
+
+// LikeEscape returns the ANSI SQL ESCAPE clause for a backslash.
+func (Default) LikeEscape() string {
+	return `ESCAPE '\'`
+}
+
+// WhereLike writes a LIKE condition on column for input, which is escaped and matched according to mode, e.g.
+//
+//	q.Write("SELECT * FROM user WHERE").WhereLike("name", input, querier.LikePrefix)
+func (q *Q) WhereLike(column, input string, mode LikeMode) *Q {
+	pattern := EscapeLike(input)
+	switch mode {
+	case LikeContains:
+		pattern = "%" + pattern + "%"
+	case LikePrefix:
+		pattern += "%"
+	case LikeSuffix:
+		pattern = "%" + pattern
+	}
+	escaper, ok := q.d.(LikeEscaper)
+	if !ok {
+		escaper = Default{}
+	}
+	return q.WriteValues(column+" LIKE {bindVar} "+escaper.LikeEscape(), "", pattern)
+}
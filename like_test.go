@@ -0,0 +1,42 @@
+package querier
+
+import "testing"
+
+func TestEscapeLike(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"abc", "abc"},
+		{"100%", `100\%`},
+		{"snake_case", `snake\_case`},
+		{`C:\dir`, `C:\\dir`},
+	}
+
+	for _, tt := range tests {
+		if got := EscapeLike(tt.in); got != tt.want {
+			t.Errorf("EscapeLike(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestWhereLike(t *testing.T) {
+	tests := []struct {
+		mode      LikeMode
+		wantParam string
+	}{
+		{LikeContains, `%50\%%`},
+		{LikePrefix, `50\%%`},
+		{LikeSuffix, `%50\%`},
+		{LikeExact, `50\%`},
+	}
+
+	for _, tt := range tests {
+		q := New(nil, Default{}).Write("SELECT * FROM t WHERE").WhereLike("name", "50%", tt.mode)
+		if want := `SELECT * FROM t WHERE name LIKE ? ESCAPE '\'`; q.String() != want {
+			t.Errorf("String() = %q, want %q", q.String(), want)
+		}
+		if len(q.Params()) != 1 || q.Params()[0] != tt.wantParam {
+			t.Errorf("Params() = %q, want [%q]", q.Params(), tt.wantParam)
+		}
+	}
+}
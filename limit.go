@@ -0,0 +1,81 @@
+package querier
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrConcurrencyLimit is returned when a named query is executed while its concurrency limit is reached, see
+// SetConcurrencyLimit.
+var ErrConcurrencyLimit = errors.New("concurrency limit of query reached")
+
+// ConcurrencyLimit limits the concurrent executions of a named query.
+type ConcurrencyLimit struct {
+	// Max is the maximum number of concurrent executions.
+	Max int
+	// Wait makes executions over the limit wait for a slot until their context is done, instead of failing with
+	// ErrConcurrencyLimit right away.
+	Wait bool
+}
+
+var (
+	limitsMu sync.RWMutex
+	limits   = make(map[string]*limiter)
+)
+
+// limiter holds the slots of a ConcurrencyLimit.
+type limiter struct {
+	slots chan struct{}
+	wait  bool
+}
+
+// SetConcurrencyLimit sets the limit of the concurrent executions of the queries named name, see Q.Named, e.g. to
+// protect the database from a stampede of expensive reports. A limit with a Max of 0 removes the limit. Executions in
+// flight keep their slot of a replaced limit.
+func SetConcurrencyLimit(name string, limit ConcurrencyLimit) {
+	limitsMu.Lock()
+	defer limitsMu.Unlock()
+	if limit.Max <= 0 {
+		delete(limits, name)
+		return
+	}
+	limits[name] = &limiter{slots: make(chan struct{}, limit.Max), wait: limit.Wait}
+}
+
+// Named names the query, so its concurrent executions can be limited by SetConcurrencyLimit.
+func (q *Q) Named(name string) *Q {
+	q.name = name
+	return q
+}
+
+// Name returns the name of the query, see Named.
+func (q *Q) Name() string {
+	return q.name
+}
+
+// acquireSlot acquires a slot of the concurrency limit of the query named name in ctx. The returned function releases
+// it.
+func acquireSlot(ctx context.Context, name string) (release func(), err error) {
+	limitsMu.RLock()
+	l := limits[name]
+	limitsMu.RUnlock()
+	if l == nil {
+		return func() {}, nil
+	}
+	release = func() { <-l.slots }
+	select {
+	case l.slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+	if !l.wait {
+		return nil, ErrConcurrencyLimit
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return release, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,66 @@
+package querier
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+// blockingExecutor blocks executions until release is closed, started receives a value when one starts.
+type blockingExecutor struct {
+	testExecutor // Only for QueryContext, ExecContext doesn't record statements so it can run concurrently.
+	started      chan struct{}
+	release      chan struct{}
+}
+
+func (e *blockingExecutor) ExecContext(ctx context.Context, query string, params ...interface{}) (sql.Result, error) {
+	e.started <- struct{}{}
+	<-e.release
+	return testResult{1}, nil
+}
+
+func TestConcurrencyLimit(t *testing.T) {
+	SetConcurrencyLimit("report", ConcurrencyLimit{Max: 1})
+	defer SetConcurrencyLimit("report", ConcurrencyLimit{})
+
+	ex := &blockingExecutor{started: make(chan struct{}, 2), release: make(chan struct{})}
+	errc := make(chan error)
+	go func() {
+		errc <- New(ex, Default{}).Write("SELECT 1").Named("report").Exec()
+	}()
+	<-ex.started
+
+	if err := New(&testExecutor{}, Default{}).Write("SELECT 1").Named("report").Exec(); !errors.Is(err, ErrConcurrencyLimit) {
+		t.Errorf("Exec() over the limit = %v, want ErrConcurrencyLimit", err)
+	}
+	SetConcurrencyLimit("other", ConcurrencyLimit{Max: 1, Wait: true})
+	defer SetConcurrencyLimit("other", ConcurrencyLimit{})
+	go func() {
+		errc <- New(ex, Default{}).Write("SELECT 2").Named("other").Exec()
+	}()
+	<-ex.started
+
+	SetConcurrencyLimit("report", ConcurrencyLimit{Max: 1, Wait: true})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := New(&testExecutor{}, Default{}).Write("SELECT 1").Named("report").ExecContext(ctx); err != nil {
+		t.Errorf("Exec() with a new limit = %v", err)
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := New(&testExecutor{}, Default{}).Write("SELECT 2").Named("other").ExecContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("waiting Exec() over the limit = %v, want context.DeadlineExceeded", err)
+	}
+
+	close(ex.release)
+	for i := 0; i < 2; i++ {
+		if err := <-errc; err != nil {
+			t.Error(err)
+		}
+	}
+	if err := New(&testExecutor{}, Default{}).Write("SELECT 2").Named("other").Exec(); err != nil {
+		t.Errorf("Exec() after release = %v", err)
+	}
+}
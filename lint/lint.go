@@ -0,0 +1,149 @@
+// Package lint implements an analyzer that reports common misuse of the querier builder, catching at build time what
+// would otherwise panic or fail at runtime.
+package lint
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const querierPath = "github.com/semrekkers/querier"
+
+// Analyzer reports Writef calls with a non-constant format, executions of a Q that is known to be empty and First or
+// Find calls with an invalid destination.
+var Analyzer = &analysis.Analyzer{
+	Name:     "querier",
+	Doc:      "report misuse of the querier query builder",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// execMethods are the methods of Q that execute the query.
+var execMethods = map[string]bool{
+	"Exec": true, "ExecContext": true,
+	"First": true, "FirstContext": true,
+	"Find": true, "FindContext": true,
+	"Scan": true, "ScanContext": true,
+	"ForEach": true, "ForEachContext": true,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || !isQMethod(pass, sel.Sel) {
+			return
+		}
+
+		name := sel.Sel.Name
+		if name == "Writef" {
+			checkWritef(pass, call)
+			return
+		}
+		if !execMethods[name] {
+			return
+		}
+		if isEmptyQ(pass, sel.X) {
+			pass.Reportf(call.Pos(), "%s called on an empty Q", name)
+		}
+		if strings.HasPrefix(name, "First") || strings.HasPrefix(name, "Find") {
+			checkDest(pass, call, name)
+		}
+	})
+	return nil, nil
+}
+
+// checkWritef reports a Writef call with a non-constant format, which may contain user input.
+func checkWritef(pass *analysis.Pass, call *ast.CallExpr) {
+	if len(call.Args) == 0 {
+		return
+	}
+	if tv, ok := pass.TypesInfo.Types[call.Args[0]]; ok && tv.Value == nil {
+		pass.Reportf(call.Args[0].Pos(), "non-constant format in call to Writef; use Write with parameters or WriteIdent instead")
+	}
+}
+
+// checkDest reports a First or Find destination that is not a pointer to a struct or a pointer to a slice.
+func checkDest(pass *analysis.Pass, call *ast.CallExpr, name string) {
+	i := 0
+	if strings.HasSuffix(name, "Context") {
+		i = 1
+	}
+	if len(call.Args) <= i {
+		return
+	}
+	t := pass.TypesInfo.TypeOf(call.Args[i])
+	if t == nil || types.IsInterface(t) {
+		// The dynamic type is unknown.
+		return
+	}
+	ptr, ok := t.Underlying().(*types.Pointer)
+	var valid bool
+	if ok {
+		elem := ptr.Elem().Underlying()
+		if strings.HasPrefix(name, "First") {
+			_, valid = elem.(*types.Struct)
+		} else {
+			_, valid = elem.(*types.Slice)
+		}
+	}
+	if !valid {
+		want := "a pointer to a struct"
+		if strings.HasPrefix(name, "Find") {
+			want = "a pointer to a slice"
+		}
+		pass.Reportf(call.Args[i].Pos(), "%s destination must be %s, got %s", name, want, t)
+	}
+}
+
+// isEmptyQ returns true if expr evaluates to a Q without a query, i.e. it is a call to New or Reset.
+func isEmptyQ(pass *analysis.Pass, expr ast.Expr) bool {
+	for {
+		paren, ok := expr.(*ast.ParenExpr)
+		if !ok {
+			break
+		}
+		expr = paren.X
+	}
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	switch fun := call.Fun.(type) {
+	case *ast.SelectorExpr:
+		if isQMethod(pass, fun.Sel) {
+			return fun.Sel.Name == "New" || fun.Sel.Name == "Reset"
+		}
+		obj, ok := pass.TypesInfo.Uses[fun.Sel].(*types.Func)
+		return ok && obj.Pkg() != nil && obj.Pkg().Path() == querierPath && obj.Name() == "New"
+	}
+	return false
+}
+
+// isQMethod returns true if ident refers to a method of querier.Q.
+func isQMethod(pass *analysis.Pass, ident *ast.Ident) bool {
+	fn, ok := pass.TypesInfo.Uses[ident].(*types.Func)
+	if !ok {
+		return false
+	}
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return false
+	}
+	t := recv.Type()
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == querierPath && obj.Name() == "Q"
+}
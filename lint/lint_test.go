@@ -0,0 +1,121 @@
+package lint
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const querierStub = `package querier
+
+type Q struct{}
+
+func New() *Q { return nil }
+
+func (q *Q) Write(query string, params ...interface{}) *Q   { return q }
+func (q *Q) Writef(format string, args ...interface{}) *Q  { return q }
+func (q *Q) New() *Q                                       { return q }
+func (q *Q) Reset() *Q                                     { return q }
+func (q *Q) Exec() error                                   { return nil }
+func (q *Q) First(i interface{}) error                     { return nil }
+func (q *Q) Find(i interface{}) error                      { return nil }
+func (q *Q) FindContext(ctx interface{}, i interface{}) error { return nil }
+`
+
+const userSrc = `package user
+
+import "github.com/semrekkers/querier"
+
+type user struct{ ID int }
+
+func f(q *querier.Q, table string, dest interface{}) {
+	q.Writef("SELECT * FROM users")
+	q.Writef(table)                    // want "non-constant format"
+	querier.New().Exec()               // want "Exec called on an empty Q"
+	q.Reset().Exec()                   // want "Exec called on an empty Q"
+	q.Write("DELETE FROM users").Exec()
+
+	var u user
+	var users []user
+	q.First(&u)
+	q.First(u)                         // want "First destination must be a pointer to a struct"
+	q.Find(&users)
+	q.Find(users)                      // want "Find destination must be a pointer to a slice"
+	q.FindContext(nil, &u)             // want "FindContext destination must be a pointer to a slice"
+	q.First(dest)
+}
+`
+
+func TestAnalyzer(t *testing.T) {
+	fset := token.NewFileSet()
+	querierPkg := typeCheck(t, fset, querierPath, querierStub, nil)
+	file, pkg, info := typeCheckFile(t, fset, "user", userSrc, querierPkg)
+
+	var got []string
+	pass := &analysis.Pass{
+		Analyzer:  Analyzer,
+		Fset:      fset,
+		Files:     []*ast.File{file},
+		Pkg:       pkg,
+		TypesInfo: info,
+		ResultOf:  map[*analysis.Analyzer]interface{}{inspect.Analyzer: inspector.New([]*ast.File{file})},
+		Report: func(d analysis.Diagnostic) {
+			got = append(got, fset.Position(d.Pos).String()+": "+d.Message)
+		},
+	}
+	if _, err := Analyzer.Run(pass); err != nil {
+		t.Fatal(err)
+	}
+
+	var want []string
+	for i, line := range strings.Split(userSrc, "\n") {
+		if j := strings.Index(line, `// want "`); j >= 0 {
+			msg := strings.TrimSuffix(line[j+len(`// want "`):], `"`)
+			want = append(want, "user.go:"+strconv.Itoa(i+1)+":"+msg)
+		}
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d diagnostics, want %d:\n%s", len(got), len(want), strings.Join(got, "\n"))
+	}
+	for i := range want {
+		parts := strings.SplitN(want[i], ":", 3)
+		if !strings.HasPrefix(got[i], parts[0]+":"+parts[1]+":") || !strings.Contains(got[i], parts[2]) {
+			t.Errorf("diagnostic %q, want %q", got[i], want[i])
+		}
+	}
+}
+
+func typeCheck(t *testing.T, fset *token.FileSet, path, src string, dep *types.Package) *types.Package {
+	_, pkg, _ := typeCheckFile(t, fset, path, src, dep)
+	return pkg
+}
+
+func typeCheckFile(t *testing.T, fset *token.FileSet, path, src string, dep *types.Package) (*ast.File, *types.Package, *types.Info) {
+	file, err := parser.ParseFile(fset, path+".go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := &types.Info{
+		Types: make(map[ast.Expr]types.TypeAndValue),
+		Defs:  make(map[*ast.Ident]types.Object),
+		Uses:  make(map[*ast.Ident]types.Object),
+	}
+	conf := types.Config{Importer: importerFunc(func(string) (*types.Package, error) { return dep, nil })}
+	pkg, err := conf.Check(path, fset, []*ast.File{file}, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return file, pkg, info
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) { return f(path) }
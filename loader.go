@@ -0,0 +1,175 @@
+//go:build go1.18
+// +build go1.18
+
+package querier
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// BatchLoadFunc loads the values of keys with q, usually with one IN query. Keys missing from the returned map
+// result in ErrNoRecord.
+type BatchLoadFunc[K comparable, V any] func(q *Q, keys []K) (map[K]V, error)
+
+// Loader coalesces concurrent loads of values by key into batches, to avoid the N+1 queries of e.g. GraphQL
+// resolvers:
+//
+//	users := querier.NewLoader(db, func(q *querier.Q, ids []int64) (map[int64]*User, error) {
+//		var found []*User
+//		if err := q.Write("SELECT * FROM user WHERE id").In(ids).Find(&found); err != nil {
+//			return nil, err
+//		}
+//		byID := make(map[int64]*User, len(found))
+//		for _, u := range found {
+//			byID[u.ID] = u
+//		}
+//		return byID, nil
+//	})
+//	...
+//	author, err := users.Load(ctx, post.AuthorID)
+//
+// Loads within the wait duration of the first one, or until the batch is full, are passed to the BatchLoadFunc
+// together. A key is loaded once, later loads return the cached result, so a Loader is meant to be request-scoped.
+// The batch is executed with the context of the load that started it. A Loader is safe for concurrent use.
+type Loader[K comparable, V any] struct {
+	db       *DB
+	fn       BatchLoadFunc[K, V]
+	wait     time.Duration
+	maxBatch int
+
+	mu    sync.Mutex
+	batch *loaderBatch[K, V]
+	cache map[K]*loaderBatch[K, V]
+}
+
+// loaderBatch is a batch of keys, its results are set when done is closed.
+type loaderBatch[K comparable, V any] struct {
+	keys    []K
+	done    chan struct{}
+	results map[K]V
+	err     error
+}
+
+// NewLoader returns a Loader that loads batches with fn, using a new Querier of db. It waits 1ms for more loads and
+// loads up to 100 keys in a batch, see SetWait and SetMaxBatch.
+func NewLoader[K comparable, V any](db *DB, fn BatchLoadFunc[K, V]) *Loader[K, V] {
+	return &Loader[K, V]{
+		db:       db,
+		fn:       fn,
+		wait:     time.Millisecond,
+		maxBatch: 100,
+		cache:    make(map[K]*loaderBatch[K, V]),
+	}
+}
+
+// SetWait sets how long the Loader waits for more loads after the first load of a batch.
+func (l *Loader[K, V]) SetWait(d time.Duration) *Loader[K, V] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.wait = d
+	return l
+}
+
+// SetMaxBatch sets the maximum number of keys in a batch, a full batch is loaded without waiting. Panics if n is less
+// than 1.
+func (l *Loader[K, V]) SetMaxBatch(n int) *Loader[K, V] {
+	if n < 1 {
+		panic("max batch size must be positive")
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.maxBatch = n
+	return l
+}
+
+// Load returns the value of key, loaded in a batch with the keys of concurrent loads. It returns ErrNoRecord if the
+// batch didn't return a value for key, or ctx.Err() if ctx is done first.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	b, ok := l.cache[key]
+	if !ok {
+		b = l.batch
+		if b == nil {
+			b = &loaderBatch[K, V]{done: make(chan struct{})}
+			l.batch = b
+			time.AfterFunc(l.wait, func() { l.dispatch(ctx, b) })
+		}
+		b.keys = append(b.keys, key)
+		l.cache[key] = b
+		if len(b.keys) >= l.maxBatch {
+			go l.dispatch(ctx, b)
+		}
+	}
+	l.mu.Unlock()
+
+	var zero V
+	select {
+	case <-b.done:
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+	if b.err != nil {
+		return zero, b.err
+	}
+	v, ok := b.results[key]
+	if !ok {
+		return zero, ErrNoRecord
+	}
+	return v, nil
+}
+
+// LoadAll returns the values of keys, see Load. It returns the first error.
+func (l *Loader[K, V]) LoadAll(ctx context.Context, keys []K) ([]V, error) {
+	values := make([]V, len(keys))
+	errs := make([]error, len(keys))
+	var wg sync.WaitGroup
+	wg.Add(len(keys))
+	for i, key := range keys {
+		go func(i int, key K) {
+			defer wg.Done()
+			values[i], errs[i] = l.Load(ctx, key)
+		}(i, key)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// Clear removes key from the cache, so the next load of key loads it again, e.g. after it's updated.
+func (l *Loader[K, V]) Clear(key K) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if b, ok := l.cache[key]; ok && b != l.batch {
+		delete(l.cache, key)
+	}
+}
+
+// dispatch loads batch b, unless it was already dispatched because it was full.
+func (l *Loader[K, V]) dispatch(ctx context.Context, b *loaderBatch[K, V]) {
+	l.mu.Lock()
+	if l.batch != b {
+		l.mu.Unlock()
+		return
+	}
+	l.batch = nil
+	l.mu.Unlock()
+
+	b.results, b.err = l.fn(l.db.New().WithContext(ctx), b.keys)
+	if b.err != nil {
+		// Don't cache failures, the next load tries again.
+		l.mu.Lock()
+		for _, key := range b.keys {
+			if l.cache[key] == b {
+				delete(l.cache, key)
+			}
+		}
+		l.mu.Unlock()
+	}
+	close(b.done)
+}
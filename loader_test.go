@@ -0,0 +1,98 @@
+//go:build go1.18
+// +build go1.18
+
+package querier
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLoader(t *testing.T) {
+	type user struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+	var (
+		mu      sync.Mutex
+		queries []string
+	)
+	sqlDB := openTestDB(t, func(query string, args []driver.Value) (*testRows, error) {
+		mu.Lock()
+		queries = append(queries, query)
+		mu.Unlock()
+		rows := &testRows{columns: []string{"id", "name"}}
+		for _, id := range args {
+			if id.(int64) < 10 {
+				rows.values = append(rows.values, []driver.Value{id, "user"})
+			}
+		}
+		return rows, nil
+	})
+	defer sqlDB.Close()
+	db := NewDB(sqlDB, Default{})
+
+	calls := 0
+	loader := NewLoader(db, func(q *Q, ids []int64) (map[int64]user, error) {
+		calls++
+		var users []user
+		if err := q.Write("SELECT * FROM user WHERE id").In(ids).Find(&users); err != nil {
+			return nil, err
+		}
+		byID := make(map[int64]user, len(users))
+		for _, u := range users {
+			byID[u.ID] = u
+		}
+		return byID, nil
+	}).SetWait(10 * time.Millisecond)
+
+	ctx := context.Background()
+	users, err := loader.LoadAll(ctx, []int64{1, 2, 1, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []user{{1, "user"}, {2, "user"}, {1, "user"}, {3, "user"}}; !reflect.DeepEqual(users, want) {
+		t.Errorf("LoadAll() = %v, want %v", users, want)
+	}
+	if len(queries) != 1 || len(queries[0]) != len("SELECT * FROM user WHERE id IN (?, ?, ?)") {
+		t.Errorf("queries = %q, want one query with 3 keys", queries)
+	}
+
+	if u, err := loader.Load(ctx, 2); err != nil || u.ID != 2 {
+		t.Errorf("cached Load(2) = %v, %v", u, err)
+	}
+	if calls != 1 {
+		t.Errorf("batch function called %d times, want 1", calls)
+	}
+	if _, err := loader.Load(ctx, 42); !errors.Is(err, ErrNoRecord) {
+		t.Errorf("Load(42) = %v, want ErrNoRecord", err)
+	}
+	loader.Clear(2)
+	if _, err := loader.Load(ctx, 2); err != nil || calls != 3 {
+		t.Errorf("Load(2) after Clear() = %v, %d calls, want 3", err, calls)
+	}
+
+	loader.SetMaxBatch(2).SetWait(time.Hour)
+	if _, err := loader.LoadAll(ctx, []int64{4, 5}); err != nil {
+		t.Errorf("LoadAll() of a full batch = %v", err)
+	}
+
+	failing := NewLoader(db, func(q *Q, ids []int64) (map[int64]user, error) {
+		calls++
+		return nil, errors.New("failed")
+	})
+	calls = 0
+	for i := 0; i < 2; i++ {
+		if _, err := failing.Load(ctx, 1); err == nil {
+			t.Error("Load() returned no error")
+		}
+	}
+	if calls != 2 {
+		t.Errorf("failing batch function called %d times, want 2", calls)
+	}
+}
@@ -0,0 +1,69 @@
+package querier
+
+import "errors"
+
+// ErrLockUnsupported is returned when a query with a locking clause the Dialect doesn't support is executed.
+var ErrLockUnsupported = errors.New("locking clause is not supported by the dialect")
+
+// Lock is a row locking clause.
+type Lock int
+
+const (
+	// LockForUpdate locks the selected rows for update.
+	LockForUpdate Lock = iota
+	// LockForShare locks the selected rows against updates by other transactions.
+	LockForShare
+	// LockSkipLocked skips rows that are locked instead of waiting for them.
+	LockSkipLocked
+)
+
+// Locker can be implemented by a Dialect to write row locking clauses. Dialects that don't implement it use the
+// default, which supports all clauses.
+type Locker interface {
+	// LockClause returns the clause for lock. ok is false if the dialect doesn't support it.
+	LockClause(lock Lock) (clause string, ok bool)
+}
+
+// LockClause returns FOR UPDATE, FOR SHARE or SKIP LOCKED.
+func (Default) LockClause(lock Lock) (string, bool) {
+	switch lock {
+	case LockForUpdate:
+		return "FOR UPDATE", true
+	case LockForShare:
+		return "FOR SHARE", true
+	case LockSkipLocked:
+		return "SKIP LOCKED", true
+	}
+	return "", false
+}
+
+// ForUpdate writes a FOR UPDATE clause. Executing the query returns ErrLockUnsupported if the Dialect doesn't support
+// it.
+func (q *Q) ForUpdate() *Q {
+	return q.writeLock(LockForUpdate)
+}
+
+// ForShare writes a FOR SHARE clause. See ForUpdate.
+func (q *Q) ForShare() *Q {
+	return q.writeLock(LockForShare)
+}
+
+// SkipLocked writes a SKIP LOCKED clause, it must follow ForUpdate or ForShare, e.g.
+//
+//	q.Write("SELECT * FROM job WHERE status = ? LIMIT 1", "pending").ForUpdate().SkipLocked()
+func (q *Q) SkipLocked() *Q {
+	return q.writeLock(LockSkipLocked)
+}
+
+func (q *Q) writeLock(lock Lock) *Q {
+	locker, ok := q.d.(Locker)
+	if !ok {
+		locker = Default{}
+	}
+	clause, ok := locker.LockClause(lock)
+	if !ok {
+		q.fail(ErrLockUnsupported)
+		return q
+	}
+	return q.Write(clause)
+}
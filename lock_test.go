@@ -0,0 +1,31 @@
+package querier
+
+import "testing"
+
+type noLockDialect struct {
+	Default
+}
+
+func (noLockDialect) LockClause(Lock) (string, bool) {
+	return "", false
+}
+
+func TestLock(t *testing.T) {
+	q := New(nil, Default{}).Write("SELECT * FROM job").ForUpdate().SkipLocked()
+	if want := "SELECT * FROM job FOR UPDATE SKIP LOCKED"; q.String() != want {
+		t.Errorf("String() = %q, want %q", q.String(), want)
+	}
+
+	q = New(nil, Default{}).Write("SELECT * FROM job").ForShare()
+	if want := "SELECT * FROM job FOR SHARE"; q.String() != want {
+		t.Errorf("String() = %q, want %q", q.String(), want)
+	}
+
+	q = New(new(testExecutor), noLockDialect{}).Write("SELECT * FROM job").ForUpdate()
+	if want := "SELECT * FROM job"; q.String() != want {
+		t.Errorf("String() = %q, want %q", q.String(), want)
+	}
+	if err := q.Exec(); err != ErrLockUnsupported {
+		t.Errorf("Exec() = %v, want %v", err, ErrLockUnsupported)
+	}
+}
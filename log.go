@@ -0,0 +1,70 @@
+package querier
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Logger logs executed queries.
+type Logger interface {
+	// LogQuery is called after query has been executed in ctx, it took d. params are the query's parameters with
+	// sensitive parameters redacted. For queries returning rows, d doesn't include reading the rows.
+	LogQuery(ctx context.Context, query string, params []interface{}, d time.Duration, err error)
+}
+
+// LoggerFunc is a function that implements Logger.
+type LoggerFunc func(ctx context.Context, query string, params []interface{}, d time.Duration, err error)
+
+// LogQuery calls fn.
+func (fn LoggerFunc) LogQuery(ctx context.Context, query string, params []interface{}, d time.Duration, err error) {
+	fn(ctx, query, params, d, err)
+}
+
+// SetLogger sets the Logger of the query, nil disables logging.
+func (q *Q) SetLogger(l Logger) *Q {
+	q.logger = l
+	return q
+}
+
+// exec executes the query as a statement in ctx.
+func (q *Q) exec(ctx context.Context) (sql.Result, error) {
+	ctx, err := q.budgetContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	params, err := q.normalizeParams()
+	if err != nil {
+		return nil, err
+	}
+	query := q.statement(ctx)
+	start := time.Now()
+	result, err := q.ex.ExecContext(ctx, query, params...)
+	err = q.translateError(err)
+	q.logQuery(ctx, query, start, err)
+	return result, err
+}
+
+// queryRows executes the query in ctx and returns its rows.
+func (q *Q) queryRows(ctx context.Context) (*sql.Rows, error) {
+	ctx, err := q.budgetContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	params, err := q.normalizeParams()
+	if err != nil {
+		return nil, err
+	}
+	query := q.statement(ctx)
+	start := time.Now()
+	rows, err := q.ex.QueryContext(ctx, query, params...)
+	err = q.translateError(err)
+	q.logQuery(ctx, query, start, err)
+	return rows, err
+}
+
+func (q *Q) logQuery(ctx context.Context, query string, start time.Time, err error) {
+	if q.logger != nil {
+		q.logger.LogQuery(ctx, query, q.RedactedParams(), time.Since(start), err)
+	}
+}
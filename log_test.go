@@ -0,0 +1,38 @@
+package querier
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLogger(t *testing.T) {
+	var (
+		gotQuery  string
+		gotParams []interface{}
+		gotErr    error
+	)
+	logger := LoggerFunc(func(_ context.Context, query string, params []interface{}, _ time.Duration, err error) {
+		gotQuery, gotParams, gotErr = query, params, err
+	})
+
+	q := New(&testExecutor{}, Default{}).SetLogger(logger)
+	if err := q.Write("UPDATE user SET password = ? WHERE id = ?", Sensitive("secret"), 1).Exec(); err != nil {
+		t.Fatal(err)
+	}
+	if want := "UPDATE user SET password = ? WHERE id = ?"; gotQuery != want {
+		t.Errorf("logged query %q, want %q", gotQuery, want)
+	}
+	if want := []interface{}{Redacted, 1}; !reflect.DeepEqual(gotParams, want) {
+		t.Errorf("logged params %v, want %v", gotParams, want)
+	}
+
+	var u struct{ ID int }
+	if err := q.New().Write("SELECT id FROM user").First(&u); err != errTestQuery {
+		t.Fatalf("First() = %v, want %v", err, errTestQuery)
+	}
+	if gotErr != errTestQuery {
+		t.Errorf("logged error %v, want %v", gotErr, errTestQuery)
+	}
+}
@@ -0,0 +1,125 @@
+package querier
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ColumnMatching controls how First and Find match result columns to field names.
+type ColumnMatching int
+
+const (
+	// MatchExact matches a column to the field with the same name. This is the default.
+	MatchExact ColumnMatching = iota
+	// MatchFold also matches a column to a field whose name only differs in case and underscores, e.g. USER_ID or
+	// userid to UserID, for databases and drivers that return columns in a different case. An exact match takes
+	// precedence, a column matching multiple fields this way isn't matched.
+	MatchFold
+)
+
+// SetColumnMatching sets how result columns are matched to field names by First and Find.
+func (q *Q) SetColumnMatching(m ColumnMatching) *Q {
+	q.columnMatching = m
+	return q
+}
+
+// SetColumnMatching sets the ColumnMatching of the queries created by New.
+func (db *DB) SetColumnMatching(m ColumnMatching) {
+	db.columnMatching = m
+}
+
+// matchColumns returns the names of the fields of m that columns match. Columns without a match are returned as is.
+// columns is returned itself when matching is exact.
+func matchColumns(m ValueMap, columns []string, matching ColumnMatching) []string {
+	if matching != MatchFold {
+		return columns
+	}
+	match := columnMatcher(m, matching)
+	matched := make([]string, len(columns))
+	for i, column := range columns {
+		matched[i] = match(column)
+	}
+	return matched
+}
+
+// columnMatcher returns a function that returns the name of the field of m that a column matches, or the column
+// itself if there is none.
+func columnMatcher(m ValueMap, matching ColumnMatching) func(column string) string {
+	if matching != MatchFold {
+		return func(column string) string { return column }
+	}
+	folded := make(map[string]string, len(m))
+	for name := range m {
+		key := foldName(name)
+		if _, ok := folded[key]; ok {
+			// Ambiguous.
+			folded[key] = ""
+			continue
+		}
+		folded[key] = name
+	}
+	return func(column string) string {
+		if _, ok := m[column]; ok {
+			return column
+		}
+		if name := folded[foldName(column)]; name != "" {
+			return name
+		}
+		return column
+	}
+}
+
+// foldName returns name in lower case without underscores.
+func foldName(name string) string {
+	return strings.ToLower(strings.Replace(name, "_", "", -1))
+}
+
+// ErrAmbiguousColumns means that multiple result columns map to the same field, typically because of a join of tables
+// with columns of the same name. The error returned is an *AmbiguousColumnsError.
+var ErrAmbiguousColumns = errors.New("multiple columns map to the same field")
+
+// AmbiguousColumnsError is returned by First and Find when multiple result columns map to the same field. The
+// columns must be given distinct names, e.g. with aliases like user.id AS user_id.
+type AmbiguousColumnsError struct {
+	// Columns contains the names of the ambiguous columns.
+	Columns []string
+}
+
+func (e *AmbiguousColumnsError) Error() string {
+	return fmt.Sprintf("ambiguous columns %s, use aliases to tell them apart", strings.Join(e.Columns, ", "))
+}
+
+// Is returns true if target is ErrAmbiguousColumns.
+func (e *AmbiguousColumnsError) Is(target error) bool {
+	return target == ErrAmbiguousColumns
+}
+
+// checkColumnNames returns an *AmbiguousColumnsError if multiple of columns map to the same field of m.
+func checkColumnNames(m ValueMap, columns []string) error {
+	var ambiguous []string
+	for i, column := range columns {
+		if _, ok := m[column]; !ok {
+			continue
+		}
+		for _, prev := range columns[:i] {
+			if prev == column {
+				ambiguous = appendUnique(ambiguous, column)
+				break
+			}
+		}
+	}
+	if ambiguous != nil {
+		return &AmbiguousColumnsError{Columns: ambiguous}
+	}
+	return nil
+}
+
+func appendUnique(s []string, v string) []string {
+	for _, e := range s {
+		if e == v {
+			return s
+		}
+	}
+	return append(s, v)
+}
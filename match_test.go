@@ -0,0 +1,87 @@
+package querier
+
+import (
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestColumnMatching(t *testing.T) {
+	type user struct {
+		UserID   int
+		FullName string
+		Email    string `db:"email"`
+	}
+	sqlDB := openTestDB(t, func(string, []driver.Value) (*testRows, error) {
+		return &testRows{
+			columns: []string{"USER_ID", "fullname", "email"},
+			values:  [][]driver.Value{{int64(1), "Foo Bar", "foo@example.com"}},
+		}, nil
+	})
+	defer sqlDB.Close()
+	db := NewDB(sqlDB, Default{})
+
+	var u user
+	if err := db.New().Write("SELECT * FROM user").First(&u); err != nil {
+		t.Fatal(err)
+	}
+	if want := (user{Email: "foo@example.com"}); u != want {
+		t.Errorf("First() with exact matching = %+v, want %+v", u, want)
+	}
+
+	db.SetColumnMatching(MatchFold)
+	want := user{UserID: 1, FullName: "Foo Bar", Email: "foo@example.com"}
+	if err := db.New().Write("SELECT * FROM user").First(&u); err != nil {
+		t.Fatal(err)
+	}
+	if u != want {
+		t.Errorf("First() = %+v, want %+v", u, want)
+	}
+	var users []user
+	if err := db.New().Write("SELECT * FROM user").Find(&users); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(users, []user{want}) {
+		t.Errorf("Find() = %+v, want %+v", users, []user{want})
+	}
+}
+
+func TestMatchColumnsAmbiguous(t *testing.T) {
+	var v struct {
+		UserID  int
+		User_ID int `db:"user_id"`
+		Name    string
+	}
+	got := matchColumns(Values(&v), []string{"USERID", "user_id", "NAME"}, MatchFold)
+	if want := []string{"USERID", "user_id", "Name"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("matchColumns() = %v, want %v", got, want)
+	}
+}
+
+func TestAmbiguousColumns(t *testing.T) {
+	type user struct {
+		ID   int
+		Name string
+	}
+	db := openTestDB(t, func(string, []driver.Value) (*testRows, error) {
+		return &testRows{
+			columns: []string{"ID", "Name", "ID", "created", "created"},
+			values:  [][]driver.Value{{int64(1), "foo", int64(2), nil, nil}},
+		}, nil
+	})
+	defer db.Close()
+
+	var u user
+	err := New(db, Default{}).Write("SELECT * FROM user JOIN account").First(&u)
+	if !errors.Is(err, ErrAmbiguousColumns) {
+		t.Fatalf("First() = %v, want ErrAmbiguousColumns", err)
+	}
+	if got := err.(*AmbiguousColumnsError).Columns; !reflect.DeepEqual(got, []string{"ID"}) {
+		t.Errorf("ambiguous columns = %v, want [ID]", got)
+	}
+	var users []user
+	if err = New(db, Default{}).Write("SELECT * FROM user JOIN account").Find(&users); !errors.Is(err, ErrAmbiguousColumns) {
+		t.Errorf("Find() = %v, want ErrAmbiguousColumns", err)
+	}
+}
@@ -0,0 +1,76 @@
+package querier
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+)
+
+var (
+	memDBOnce sync.Once
+	memDB     *sql.DB
+)
+
+// memQuery returns result set rs as *sql.Rows, for executors that don't query a database. A nil rs is an empty
+// result set.
+func memQuery(ctx context.Context, rs *memResultSet) (*sql.Rows, error) {
+	memDBOnce.Do(func() {
+		memDB = sql.OpenDB(memConnector{})
+	})
+	if rs == nil {
+		rs = new(memResultSet)
+	}
+	return memDB.QueryContext(ctx, "", rs)
+}
+
+// memResultSet is a result set held in memory.
+type memResultSet struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+type memConnector struct{}
+
+func (memConnector) Connect(context.Context) (driver.Conn, error) { return memConn{}, nil }
+func (memConnector) Driver() driver.Driver                        { return memDriver{} }
+
+type memDriver struct{}
+
+func (memDriver) Open(string) (driver.Conn, error) { return memConn{}, nil }
+
+// memConn serves the result set it's passed as the only argument of a query.
+type memConn struct{}
+
+var errMemUnsupported = errors.New("querier: not supported without a database")
+
+func (memConn) Prepare(string) (driver.Stmt, error) { return nil, errMemUnsupported }
+func (memConn) Close() error                        { return nil }
+func (memConn) Begin() (driver.Tx, error)           { return nil, errMemUnsupported }
+
+// CheckNamedValue accepts the result set argument as is.
+func (memConn) CheckNamedValue(*driver.NamedValue) error { return nil }
+
+func (memConn) QueryContext(_ context.Context, _ string, args []driver.NamedValue) (driver.Rows, error) {
+	rs := args[0].Value.(*memResultSet)
+	return &memRows{columns: rs.columns, values: rs.values}, nil
+}
+
+type memRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *memRows) Columns() []string { return r.columns }
+func (r *memRows) Close() error      { return nil }
+
+func (r *memRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
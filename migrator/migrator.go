@@ -2,8 +2,11 @@
 package migrator
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/semrekkers/querier"
 )
@@ -20,22 +23,119 @@ type Model interface {
 	Migrate(q *querier.Q, column string) error
 }
 
-// DBInfo is an interface for retrieving information about the database.
+// DBInfo is an interface for retrieving information about the database. Tables are looked up in the schema of the
+// querier, see Q.Schema, or in the current schema if it's empty.
 type DBInfo interface {
 	querier.Dialect
 	HasTable(*querier.Q, string) (bool, error)
 	TableColumns(*querier.Q, string) ([]string, error)
 }
 
+// EnumInfo can be implemented by a DBInfo to add new members to existing enum columns.
+type EnumInfo interface {
+	// EnumValues returns the members of an existing enum column.
+	EnumValues(q *querier.Q, table, column string) ([]string, error)
+	// AlterEnum alters an existing enum column to the data type of field.
+	AlterEnum(q *querier.Q, table string, field querier.Field) error
+}
+
+// SequenceInfo can be implemented by a DBInfo to find existing sequences. The sequences of models are created with
+// CREATE SEQUENCE IF NOT EXISTS when it isn't implemented, they aren't reported in Result.SequencesCreated then.
+type SequenceInfo interface {
+	// HasSequence returns true if sequence name exists.
+	HasSequence(q *querier.Q, name string) (bool, error)
+}
+
+// TableOptioner can be implemented by a DBInfo to add table options to CREATE TABLE, e.g. the character set.
+type TableOptioner interface {
+	// TableOptions returns the table options written after the definitions of CREATE TABLE, or an empty string.
+	TableOptions() string
+}
+
+// TableRebuilder can be implemented by a DBInfo of a database without querier.FeatureAlterTable, e.g. SQLite, to take
+// part in rebuilding a table. Such a database only adds columns in place. The migrator rebuilds an existing table for
+// the other changes: columns that aren't fields of the model anymore are dropped, columns whose data type or
+// nullability changed are modified (if the DBInfo is a TableInfo), enums with new members are altered and new columns
+// with unique constraints are added. It creates the table anew as new_table, copies the rows of the remaining columns,
+// drops the old table and renames the new one, in a transaction. The methods are called on the connection of the
+// transaction.
+type TableRebuilder interface {
+	// BeforeRebuild is called before the transaction, e.g. to disable foreign keys with PRAGMA foreign_keys = OFF.
+	BeforeRebuild(q *querier.Q) error
+	// TableObjects returns the statements that create the indexes and triggers of table, which are dropped with the
+	// old table. They're executed after the new table is renamed.
+	TableObjects(q *querier.Q, table string) ([]string, error)
+	// CheckRebuild is called before the transaction is committed, e.g. to check the foreign keys with PRAGMA
+	// foreign_key_check. The rebuild is rolled back if it returns an error.
+	CheckRebuild(q *querier.Q, table string) error
+	// AfterRebuild is called after the transaction, also when the rebuild failed, e.g. to enable foreign keys again.
+	AfterRebuild(q *querier.Q) error
+}
+
+// TableInfo can be implemented by a DBInfo to describe existing tables in full, so they can be compared with their
+// models.
+type TableInfo interface {
+	// TableColumnInfo returns the columns of an existing table, in the order of the table.
+	TableColumnInfo(q *querier.Q, table string) ([]Column, error)
+	// TableIndexes returns the indexes of an existing table, including the index of the primary key.
+	TableIndexes(q *querier.Q, table string) ([]Index, error)
+	// TableConstraints returns the constraints of an existing table.
+	TableConstraints(q *querier.Q, table string) ([]Constraint, error)
+}
+
+// Column describes an existing column.
+type Column struct {
+	Name string
+	// DataType is the data type as reported by the database, e.g. varchar(255), without its nullability.
+	DataType string
+	Nullable bool
+	// Default is the expression of the default value, nil if the column has none.
+	Default *string
+	// Extra is additional information of the database, e.g. auto_increment in MySQL.
+	Extra string
+}
+
+// Index describes an existing index.
+type Index struct {
+	Name    string
+	Columns []string
+	Unique  bool
+	Primary bool
+}
+
+// Constraint types of Constraint.
+const (
+	PrimaryKey = "PRIMARY KEY"
+	Unique     = "UNIQUE"
+	ForeignKey = "FOREIGN KEY"
+	Check      = "CHECK"
+)
+
+// Constraint describes an existing constraint.
+type Constraint struct {
+	Name string
+	// Type is the type of the constraint, e.g. ForeignKey.
+	Type    string
+	Columns []string
+	// RefTable and RefColumns are the table and columns referred to by a foreign key.
+	RefTable   string
+	RefColumns []string
+}
+
 // Migrator is the actual migrator. It is safe for multiple goroutines to call it's methods.
 type Migrator struct {
 	db     *sql.DB
 	dbInfo DBInfo
+	schema string
 }
 
+var errEnumMembersRemoved = errors.New("enum members cannot be removed")
+
 // Result contains the results of a successful migration.
 type Result struct {
-	TablesCreated, NewColumns []string
+	TablesCreated, NewColumns, AlteredColumns, SequencesCreated []string
+	// DroppedColumns are the columns dropped by rebuilding their table, see TableRebuilder.
+	DroppedColumns []string
 }
 
 // MigrationError describes a problem encountered during the migration.
@@ -53,7 +153,18 @@ func (e *MigrationError) Error() string {
 
 // New returns a new Migrator.
 func New(db *sql.DB, dbInfo DBInfo) *Migrator {
-	return &Migrator{db, dbInfo}
+	return &Migrator{db: db, dbInfo: dbInfo}
+}
+
+// SetSchema sets the schema that the tables of the models are created, migrated and dropped in, see Q.SetSchema. It
+// must be called before the Migrator is used.
+func (m *Migrator) SetSchema(schema string) {
+	m.schema = schema
+}
+
+// newQuerier returns a new querier of the Migrator's schema.
+func (m *Migrator) newQuerier() *querier.Q {
+	return querier.New(m.db, m.dbInfo).SetSchema(m.schema)
 }
 
 // Migrate migrates the models.
@@ -71,10 +182,10 @@ func (m *Migrator) Migrate(models ...Model) (*Result, error) {
 
 // Drop drops the models.
 func (m *Migrator) Drop(models ...Model) error {
-	q := querier.New(m.db, m.dbInfo)
+	q := m.newQuerier()
 	for _, model := range models {
 		tableName := model.TableName()
-		err := q.Writef("DROP TABLE %s", tableName).Exec()
+		err := q.Writef("DROP TABLE %s", q.Qualify(tableName)).Exec()
 		if err != nil {
 			return &MigrationError{Table: tableName, Err: err}
 		}
@@ -83,9 +194,62 @@ func (m *Migrator) Drop(models ...Model) error {
 	return nil
 }
 
+// Rekey re-encrypts the encrypted fields of the models with the current encryption key of the querier's KeyProvider.
+// It returns the number of updated rows.
+func (m *Migrator) Rekey(models ...Model) (n int64, err error) {
+	q := m.newQuerier()
+	for _, model := range models {
+		tableName := model.TableName()
+		for _, field := range q.Fields(model).Select() {
+			if !field.Encrypted {
+				continue
+			}
+			var ciphertexts [][]byte
+			err = q.Writef("SELECT DISTINCT %s FROM %s", field.Name, q.Qualify(tableName)).
+				ForEach(func(_ *querier.Q, rows *sql.Rows) error {
+					var ciphertext []byte
+					if err := rows.Scan(&ciphertext); err != nil || ciphertext == nil {
+						return err
+					}
+					ciphertexts = append(ciphertexts, ciphertext)
+					return nil
+				})
+			if err != nil {
+				return n, &MigrationError{Table: tableName, Column: field.Name, Err: err}
+			}
+			q.Reset()
+
+			for _, ciphertext := range ciphertexts {
+				rekeyed, changed, err := querier.Rekey(field.Name, ciphertext)
+				if err != nil {
+					return n, &MigrationError{Table: tableName, Column: field.Name, Err: err}
+				}
+				if !changed {
+					continue
+				}
+				err = q.Writef("UPDATE %s", q.Qualify(tableName)).
+					WriteFields("SET {name} = {bindVar}", "", field).
+					AddParams(rekeyed).
+					WriteFields("WHERE {name} = {bindVar}", "", field).
+					AddParams(ciphertext).
+					Exec()
+				if err != nil {
+					return n, &MigrationError{Table: tableName, Column: field.Name, Err: err}
+				}
+				n += q.RowsAffected()
+				q.Reset()
+			}
+		}
+	}
+	return n, nil
+}
+
 func (m *Migrator) migrateModel(model Model, res *Result) error {
-	q := querier.New(m.db, m.dbInfo)
+	q := m.newQuerier()
 	tableName := model.TableName()
+	if err := m.migrateSequences(q, model, res); err != nil {
+		return err
+	}
 	tableExists, err := m.dbInfo.HasTable(q, tableName)
 	if err != nil {
 		return err
@@ -94,12 +258,7 @@ func (m *Migrator) migrateModel(model Model, res *Result) error {
 
 	fieldSelector := q.Fields(model)
 	if !tableExists {
-		q.Writef("CREATE TABLE %s (", tableName).
-			WriteFields("{name} {dataType}", querier.FieldSep, fieldSelector.Select()...).
-			SetSeparator(querier.FieldSep)
-		model.CreateTable(q)
-		q.WriteRaw(")")
-		if err = q.Exec(); err != nil {
+		if err = m.createTable(q, model, tableName); err != nil {
 			return &MigrationError{Table: tableName, Err: err}
 		}
 		res.TablesCreated = append(res.TablesCreated, tableName)
@@ -110,8 +269,15 @@ func (m *Migrator) migrateModel(model Model, res *Result) error {
 		}
 		q.Reset()
 
-		for _, field := range fieldSelector.Except(existing...).Select() {
-			err = q.Writef("ALTER TABLE %s", tableName).
+		newFields := fieldSelector.Except(existing...).Select()
+		alterTable := querier.Supports(m.dbInfo, querier.FeatureAlterTable)
+		if !alterTable {
+			if rebuilt, err := m.rebuildModel(q, model, existing, newFields, res); err != nil || rebuilt {
+				return err
+			}
+		}
+		for _, field := range columnDefinitions(newFields) {
+			err = q.Writef("ALTER TABLE %s", q.Qualify(tableName)).
 				WriteFields("ADD {name} {dataType}", "", field).
 				Exec()
 			if err != nil {
@@ -123,7 +289,342 @@ func (m *Migrator) migrateModel(model Model, res *Result) error {
 			}
 			res.NewColumns = append(res.NewColumns, tableName+"."+field.Name)
 		}
+		// The unique constraints of new columns are added, those including existing columns are left alone.
+		names, constraints := querier.UniqueConstraints(tableName, newFields)
+		_, all := querier.UniqueConstraints(tableName, fieldSelector.Select())
+		for _, name := range names {
+			if len(constraints[name]) != len(all[name]) {
+				continue
+			}
+			err = q.Writef("ALTER TABLE %s ADD CONSTRAINT %s UNIQUE (%s)", q.Qualify(tableName), name,
+				strings.Join(constraints[name], querier.FieldSep)).Exec()
+			if err != nil {
+				return &MigrationError{Table: tableName, Err: err}
+			}
+			q.Reset()
+		}
+
+		if enumInfo, ok := m.dbInfo.(EnumInfo); ok && alterTable {
+			if err = m.migrateEnums(q, enumInfo, model, existing, res); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
 }
+
+// createTable creates the table of model as name, its unique constraints are named after the table of model.
+func (m *Migrator) createTable(q *querier.Q, model Model, name string) error {
+	tableName := model.TableName()
+	fields := q.Fields(model).Select()
+	q.Writef("CREATE TABLE %s (", q.Qualify(name)).
+		WriteFields("{name} {dataType}", querier.FieldSep, columnDefinitions(fields)...).
+		SetSeparator(querier.FieldSep)
+	names, constraints := querier.UniqueConstraints(tableName, fields)
+	for _, constraint := range names {
+		q.Writef("CONSTRAINT %s UNIQUE (%s)", constraint, strings.Join(constraints[constraint], querier.FieldSep))
+	}
+	model.CreateTable(q)
+	q.WriteRaw(")")
+	if optioner, ok := m.dbInfo.(TableOptioner); ok {
+		if options := optioner.TableOptions(); options != "" {
+			q.WriteRaw(" " + options)
+		}
+	}
+	return q.Exec()
+}
+
+// rebuildModel rebuilds the existing table of model if it has changes that can't be made in place without
+// querier.FeatureAlterTable, see TableRebuilder. It returns false if the table isn't rebuilt, its new columns are
+// added in place then.
+func (m *Migrator) rebuildModel(q *querier.Q, model Model, existing []string, newFields []querier.Field,
+	res *Result) (bool, error) {
+	tableName := model.TableName()
+	dropped := droppedColumns(existing, q.Fields(model).Select())
+	altered, err := m.changedColumns(q, model, existing)
+	if err != nil {
+		return false, err
+	}
+	if enumInfo, ok := m.dbInfo.(EnumInfo); ok {
+		enums, err := changedEnums(q, enumInfo, model, existing)
+		if err != nil {
+			return false, err
+		}
+		for _, field := range enums {
+			if !hasField(altered, field.Name) {
+				altered = append(altered, field)
+			}
+		}
+	}
+	newConstraints, _ := querier.UniqueConstraints(tableName, newFields)
+	if len(dropped) == 0 && len(altered) == 0 && len(newConstraints) == 0 {
+		return false, nil
+	}
+	if err = m.rebuildTable(model, existing); err != nil {
+		return false, &MigrationError{Table: tableName, Err: err}
+	}
+	for _, field := range querier.Columns(newFields) {
+		if err = model.Migrate(q, field.Name); err != nil {
+			return false, &MigrationError{Table: tableName, Column: field.Name, Err: err}
+		}
+		q.Reset()
+		res.NewColumns = append(res.NewColumns, tableName+"."+field.Name)
+	}
+	for _, field := range altered {
+		res.AlteredColumns = append(res.AlteredColumns, tableName+"."+field.Name)
+	}
+	for _, column := range dropped {
+		res.DroppedColumns = append(res.DroppedColumns, tableName+"."+column)
+	}
+	return true, nil
+}
+
+// droppedColumns returns the existing columns that aren't columns of fields.
+func droppedColumns(existing []string, fields []querier.Field) (dropped []string) {
+	columns := querier.Columns(fields)
+	for _, column := range existing {
+		if !hasField(columns, column) {
+			dropped = append(dropped, column)
+		}
+	}
+	return dropped
+}
+
+// changedColumns returns the fields of the existing columns of model whose data type or nullability changed, if the
+// DBInfo is a TableInfo.
+func (m *Migrator) changedColumns(q *querier.Q, model Model, existing []string) (changed []querier.Field, err error) {
+	tableInfo, ok := m.dbInfo.(TableInfo)
+	if !ok {
+		return nil, nil
+	}
+	tableName := model.TableName()
+	columns, err := tableInfo.TableColumnInfo(q, tableName)
+	if err != nil {
+		return nil, &MigrationError{Table: tableName, Err: err}
+	}
+	q.Reset()
+	for _, field := range querier.Columns(q.Fields(model).Only(existing...).Select()) {
+		for _, column := range columns {
+			if column.Name == field.Name && columnChanged(column, field) {
+				changed = append(changed, field)
+			}
+		}
+	}
+	return changed, nil
+}
+
+// columnChanged returns true if the data type or nullability of column differs from field. Data types are compared
+// as text, without their case and column constraints.
+func columnChanged(column Column, field querier.Field) bool {
+	if field.DataType == "" {
+		return false
+	}
+	nullable := !strings.Contains(strings.ToUpper(field.DataType), "NOT NULL")
+	return nullable != column.Nullable || !strings.EqualFold(baseDataType(field.DataType), column.DataType)
+}
+
+// columnConstraints are the keywords that start the column constraints of a data type.
+var columnConstraints = []string{" NOT NULL", " NULL", " DEFAULT", " PRIMARY KEY", " UNIQUE", " CHECK", " REFERENCES",
+	" COLLATE", " GENERATED", " AUTO_INCREMENT", " AUTOINCREMENT"}
+
+// baseDataType returns dataType without its column constraints, e.g. INT of INT NOT NULL DEFAULT 0.
+func baseDataType(dataType string) string {
+	upper := strings.ToUpper(dataType)
+	end := len(upper)
+	for _, keyword := range columnConstraints {
+		if i := strings.Index(upper, keyword); i >= 0 && i < end {
+			end = i
+		}
+	}
+	return strings.TrimSpace(dataType[:end])
+}
+
+// hasField returns true if fields contains a field named name.
+func hasField(fields []querier.Field, name string) bool {
+	for _, field := range fields {
+		if field.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// rebuildTable rebuilds the table of model, see TableRebuilder. The existing columns that are still fields of model
+// are copied.
+func (m *Migrator) rebuildTable(model Model, existing []string) (err error) {
+	ctx := context.Background()
+	// Statements outside the transaction, like PRAGMA foreign_keys, must be executed on its connection.
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	rebuilder, _ := m.dbInfo.(TableRebuilder)
+	if rebuilder != nil {
+		if err = rebuilder.BeforeRebuild(querier.New(conn, m.dbInfo).SetSchema(m.schema)); err != nil {
+			return err
+		}
+		defer func() {
+			if aerr := rebuilder.AfterRebuild(querier.New(conn, m.dbInfo).SetSchema(m.schema)); err == nil {
+				err = aerr
+			}
+		}()
+	}
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	q := querier.New(tx, m.dbInfo).SetSchema(m.schema)
+	tableName, newName := model.TableName(), "new_"+model.TableName()
+	var objects []string
+	if rebuilder != nil {
+		if objects, err = rebuilder.TableObjects(q, tableName); err != nil {
+			return err
+		}
+		q.Reset()
+	}
+	if err = m.createTable(q, model, newName); err != nil {
+		return err
+	}
+	q.Reset()
+
+	var columns []string
+	for _, field := range querier.Columns(q.Fields(model).Only(existing...).Select()) {
+		if !field.Generated {
+			columns = append(columns, field.Name)
+		}
+	}
+	if len(columns) > 0 {
+		list := strings.Join(columns, querier.FieldSep)
+		err = q.Writef("INSERT INTO %s (%s) SELECT %s FROM %s", q.Qualify(newName), list, list, q.Qualify(tableName)).
+			Exec()
+		if err != nil {
+			return err
+		}
+		q.Reset()
+	}
+	if err = q.Writef("DROP TABLE %s", q.Qualify(tableName)).Exec(); err != nil {
+		return err
+	}
+	q.Reset()
+	if err = q.Writef("ALTER TABLE %s RENAME TO %s", q.Qualify(newName), tableName).Exec(); err != nil {
+		return err
+	}
+	q.Reset()
+	for _, object := range objects {
+		if err = q.WriteRaw(object).Exec(); err != nil {
+			return err
+		}
+		q.Reset()
+	}
+	if rebuilder != nil {
+		if err = rebuilder.CheckRebuild(q, tableName); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// migrateSequences creates the sequences of the fields of model that don't exist yet.
+func (m *Migrator) migrateSequences(q *querier.Q, model Model, res *Result) error {
+	tableName := model.TableName()
+	seqInfo, hasInfo := m.dbInfo.(SequenceInfo)
+	for _, field := range q.Fields(model).Select() {
+		if field.Sequence == "" {
+			continue
+		}
+		if hasInfo {
+			exists, err := seqInfo.HasSequence(q, field.Sequence)
+			if err != nil {
+				return &MigrationError{Table: tableName, Column: field.Name, Err: err}
+			}
+			q.Reset()
+			if exists {
+				continue
+			}
+			q.Writef("CREATE SEQUENCE %s", field.Sequence)
+		} else {
+			q.Writef("CREATE SEQUENCE IF NOT EXISTS %s", field.Sequence)
+		}
+		if err := q.Exec(); err != nil {
+			return &MigrationError{Table: tableName, Column: field.Name, Err: err}
+		}
+		q.Reset()
+		if hasInfo {
+			res.SequencesCreated = append(res.SequencesCreated, field.Sequence)
+		}
+	}
+	return nil
+}
+
+// columnDefinitions returns the columns of fields, with the generation clause of generated columns appended to their
+// data type.
+func columnDefinitions(fields []querier.Field) []querier.Field {
+	columns := querier.Columns(fields)
+	result := make([]querier.Field, len(columns))
+	for i, field := range columns {
+		if field.GeneratedAs != "" {
+			field.DataType += " GENERATED ALWAYS AS (" + field.GeneratedAs + ") STORED"
+		}
+		result[i] = field
+	}
+	return result
+}
+
+func (m *Migrator) migrateEnums(q *querier.Q, enumInfo EnumInfo, model Model, existing []string, res *Result) error {
+	tableName := model.TableName()
+	altered, err := changedEnums(q, enumInfo, model, existing)
+	if err != nil {
+		return err
+	}
+	for _, field := range altered {
+		if err = enumInfo.AlterEnum(q, tableName, field); err != nil {
+			return &MigrationError{Table: tableName, Column: field.Name, Err: err}
+		}
+		q.Reset()
+		res.AlteredColumns = append(res.AlteredColumns, tableName+"."+field.Name)
+	}
+	return nil
+}
+
+// changedEnums returns the enum fields of the existing columns of model with new members. Removed members are an error.
+func changedEnums(q *querier.Q, enumInfo EnumInfo, model Model, existing []string) (altered []querier.Field, err error) {
+	tableName := model.TableName()
+	for _, field := range q.Fields(model).Only(existing...).Select() {
+		if field.Enum == nil {
+			continue
+		}
+		current, err := enumInfo.EnumValues(q, tableName, field.Name)
+		if err != nil {
+			return nil, &MigrationError{Table: tableName, Column: field.Name, Err: err}
+		}
+		q.Reset()
+		added, removed := diffEnum(current, field.Enum)
+		if removed {
+			return nil, &MigrationError{Table: tableName, Column: field.Name, Err: errEnumMembersRemoved}
+		}
+		if added {
+			altered = append(altered, field)
+		}
+	}
+	return altered, nil
+}
+
+// diffEnum reports whether members were added to or removed from enum current.
+func diffEnum(current, members []string) (added, removed bool) {
+	set := make(map[string]struct{}, len(current))
+	for _, member := range current {
+		set[member] = struct{}{}
+	}
+	for _, member := range members {
+		if _, ok := set[member]; ok {
+			delete(set, member)
+		} else {
+			added = true
+		}
+	}
+	return added, len(set) > 0
+}
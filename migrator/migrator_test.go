@@ -0,0 +1,156 @@
+package migrator
+
+import (
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/semrekkers/querier"
+	"github.com/semrekkers/querier/internal/testdriver"
+)
+
+// rebuildDialect is a dialect without querier.FeatureAlterTable, like SQLite's. It describes the existing table user
+// by its columns and records the calls of the TableRebuilder.
+type rebuildDialect struct {
+	querier.Default
+	columns  []Column
+	checkErr error
+	calls    *[]string
+}
+
+func (rebuildDialect) Supports(f querier.Feature) bool { return f != querier.FeatureAlterTable }
+
+func (rebuildDialect) HasTable(*querier.Q, string) (bool, error) { return true, nil }
+
+func (d rebuildDialect) TableColumns(*querier.Q, string) (columns []string, err error) {
+	for _, column := range d.columns {
+		columns = append(columns, column.Name)
+	}
+	return columns, nil
+}
+
+func (d rebuildDialect) TableColumnInfo(*querier.Q, string) ([]Column, error) { return d.columns, nil }
+func (rebuildDialect) TableIndexes(*querier.Q, string) ([]Index, error)       { return nil, nil }
+func (rebuildDialect) TableConstraints(*querier.Q, string) ([]Constraint, error) {
+	return nil, nil
+}
+
+func (d rebuildDialect) BeforeRebuild(*querier.Q) error {
+	*d.calls = append(*d.calls, "BeforeRebuild")
+	return nil
+}
+
+func (rebuildDialect) TableObjects(*querier.Q, string) ([]string, error) {
+	return []string{"CREATE INDEX user_name ON user (name)"}, nil
+}
+
+func (d rebuildDialect) CheckRebuild(*querier.Q, string) error {
+	*d.calls = append(*d.calls, "CheckRebuild")
+	return d.checkErr
+}
+
+func (d rebuildDialect) AfterRebuild(*querier.Q) error {
+	*d.calls = append(*d.calls, "AfterRebuild")
+	return nil
+}
+
+type user struct {
+	ID   int64  `db:"id"`
+	Name string `db:"name"`
+}
+
+func (*user) TableName() string                         { return "user" }
+func (*user) CreateTable(q *querier.Q)                  { q.Write("PRIMARY KEY (id)") }
+func (*user) Migrate(q *querier.Q, column string) error { return nil }
+
+type userWithEmail struct {
+	user
+	Email string `db:"email"`
+}
+
+var (
+	idColumn   = Column{Name: "id", DataType: "BIGINT"}
+	nameColumn = Column{Name: "name", DataType: "VARCHAR(255)"}
+)
+
+// migrate migrates model with the existing columns, it returns the statements and calls of the TableRebuilder.
+func migrate(t *testing.T, model Model, columns []Column, checkErr error) (*Result, []string, error) {
+	var calls []string
+	db := testdriver.Open(t, func(query string, _ []driver.Value) (*testdriver.Result, error) {
+		calls = append(calls, query)
+		return nil, nil
+	})
+	res, err := New(db, rebuildDialect{columns: columns, checkErr: checkErr, calls: &calls}).Migrate(model)
+	return res, calls, err
+}
+
+func TestMigrateAddColumnInPlace(t *testing.T) {
+	res, calls, err := migrate(t, &userWithEmail{}, []Column{idColumn, nameColumn}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"ALTER TABLE user ADD email VARCHAR(255) NOT NULL"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("executed %q, want %q", calls, want)
+	}
+	if want := []string{"user.email"}; !reflect.DeepEqual(res.NewColumns, want) {
+		t.Errorf("NewColumns = %q, want %q", res.NewColumns, want)
+	}
+}
+
+func TestMigrateRebuild(t *testing.T) {
+	rebuild := []string{
+		"BeforeRebuild",
+		"BEGIN",
+		"CREATE TABLE new_user ( id BIGINT NOT NULL, name VARCHAR(255) NOT NULL, PRIMARY KEY (id))",
+		"INSERT INTO new_user (id, name) SELECT id, name FROM user",
+		"DROP TABLE user",
+		"ALTER TABLE new_user RENAME TO user",
+		"CREATE INDEX user_name ON user (name)",
+		"CheckRebuild",
+		"COMMIT",
+		"AfterRebuild",
+	}
+	tests := []struct {
+		name        string
+		columns     []Column
+		wantDropped []string
+		wantAltered []string
+	}{
+		{"dropped column", []Column{idColumn, nameColumn, {Name: "age", DataType: "INT"}}, []string{"user.age"}, nil},
+		{"data type", []Column{idColumn, {Name: "name", DataType: "TEXT"}}, nil, []string{"user.name"}},
+		{"nullability", []Column{idColumn, {Name: "name", DataType: "varchar(255)", Nullable: true}}, nil,
+			[]string{"user.name"}},
+	}
+	for _, tt := range tests {
+		res, calls, err := migrate(t, &user{}, tt.columns, nil)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(calls, rebuild) {
+			t.Errorf("%s: executed %q, want %q", tt.name, calls, rebuild)
+		}
+		if !reflect.DeepEqual(res.DroppedColumns, tt.wantDropped) || !reflect.DeepEqual(res.AlteredColumns, tt.wantAltered) {
+			t.Errorf("%s: dropped %q and altered %q, want %q and %q", tt.name, res.DroppedColumns, res.AlteredColumns,
+				tt.wantDropped, tt.wantAltered)
+		}
+	}
+
+	// Unchanged tables aren't rebuilt.
+	if _, calls, err := migrate(t, &user{}, []Column{idColumn, nameColumn}, nil); err != nil || len(calls) != 0 {
+		t.Errorf("migrating an unchanged table executed %q, %v", calls, err)
+	}
+}
+
+func TestMigrateRebuildRollback(t *testing.T) {
+	errForeignKey := errors.New("foreign key violation")
+	_, calls, err := migrate(t, &user{}, []Column{idColumn, {Name: "name", DataType: "TEXT"}}, errForeignKey)
+	var migrationErr *MigrationError
+	if !errors.As(err, &migrationErr) || migrationErr.Err != errForeignKey {
+		t.Errorf("Migrate() = %v, want the error of CheckRebuild", err)
+	}
+	if n := len(calls); n < 3 || calls[n-3] != "CheckRebuild" || calls[n-2] != "ROLLBACK" || calls[n-1] != "AfterRebuild" {
+		t.Errorf("executed %q, want CheckRebuild, ROLLBACK and AfterRebuild last", calls)
+	}
+}
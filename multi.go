@@ -0,0 +1,321 @@
+package querier
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"strings"
+)
+
+// FirstMultiContext scans the first row of a join into the structs dests, e.g.
+//
+//	q.Write("SELECT user.*, account.* FROM user JOIN account ON account.user_id = user.id WHERE user.id = ?", id).
+//		FirstMulti(&user, &account)
+//
+// The columns are split across the destinations in order: a column is scanned into the current destination until a
+// column repeats one of its fields, or only maps to a field of the next destination. A column of the form
+// prefix.name, e.g. aliased as "account.id", is scanned into the destination whose table name or type name equals
+// prefix, ignoring case, regardless of its position. The table name is returned by the TableName method of the
+// destination, if it has one. Multiple columns mapping to the same field result in an *AmbiguousColumnsError.
+//
+// A destination can be a pointer to a pointer to a struct, for a relation that may be missing, e.g. of a LEFT JOIN.
+// It's set to nil if all of its columns are NULL, and to a new struct otherwise.
+func (q *Q) FirstMultiContext(ctx context.Context, dests ...interface{}) (err error) {
+	if q.query.Len() == 0 {
+		panic(errEmptyQuery)
+	}
+	if len(dests) == 0 {
+		panic("no destinations")
+	}
+	types := make([]reflect.Type, len(dests))
+	valueMaps := make([]ValueMap, len(dests))
+	nullable := make([]bool, len(dests))
+	structs := make([]reflect.Value, len(dests))
+	for i, dest := range dests {
+		if v := reflect.ValueOf(dest); v.Kind() == reflect.Ptr && v.Elem().Kind() == reflect.Ptr {
+			nullable[i] = true
+			structs[i] = reflect.New(v.Elem().Type().Elem())
+			dest = structs[i].Interface()
+		}
+		valueMaps[i] = ScanValues(dest)
+		types[i] = reflect.TypeOf(dest).Elem()
+	}
+	q.ctx = ctx
+	defer q.runDeferred(&err)
+	if err := q.checkBuild(); err != nil {
+		return q.returnErr(err)
+	}
+	done, err := q.track()
+	if err != nil {
+		return q.returnErr(err)
+	}
+	defer done()
+
+	rows, err := q.queryRows(ctx)
+	if err != nil {
+		return q.returnErr(err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return q.returnErr(ErrNoRecord)
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		return q.returnErr(err)
+	}
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return q.returnErr(err)
+	}
+	split, err := splitColumns(types, valueMaps, columns, q.columnMatching)
+	if err != nil {
+		return q.returnErr(err)
+	}
+	null, err := split.nullDests(rows, valueMaps, nullable)
+	if err != nil {
+		return q.returnErr(err)
+	}
+	fields := getScanSlice()
+	defer putScanSlice(fields)
+	*fields = split.fields(valueMaps, null, *fields)
+	decodeColumns(q.coercion, columnTypes, *fields)
+	if err = rows.Scan(*fields...); err != nil {
+		return q.returnErr(err)
+	}
+	for i, dest := range dests {
+		if !nullable[i] {
+			continue
+		}
+		ptr := reflect.ValueOf(dest).Elem()
+		if null[i] {
+			ptr.Set(reflect.Zero(ptr.Type()))
+		} else {
+			ptr.Set(structs[i])
+		}
+	}
+	return nil
+}
+
+// FirstMulti scans the first row of a join into the structs dests, see FirstMultiContext.
+func (q *Q) FirstMulti(dests ...interface{}) error {
+	return q.FirstMultiContext(q.baseContext(), dests...)
+}
+
+// FindMultiContext scans the rows of a join into the slices of structs dests, every row appends an element to each
+// slice, e.g.
+//
+//	q.Write("SELECT user.*, account.* FROM user JOIN account ON account.user_id = user.id").
+//		FindMulti(&users, &accounts)
+//
+// The columns are split across the slices like FirstMultiContext does. An element of a slice of pointers is nil if all
+// of its columns are NULL, e.g. for a missing relation of a LEFT JOIN.
+func (q *Q) FindMultiContext(ctx context.Context, dests ...interface{}) (err error) {
+	if q.query.Len() == 0 {
+		panic(errEmptyQuery)
+	}
+	if len(dests) == 0 {
+		panic("no destinations")
+	}
+	slices := make([]reflect.Value, len(dests))
+	elemTypes := make([]reflect.Type, len(dests))
+	elemIsPtr := make([]bool, len(dests))
+	for i, dest := range dests {
+		slices[i], elemTypes[i], elemIsPtr[i] = extractStructSliceInfo(dest)
+	}
+	q.ctx = ctx
+	defer q.runDeferred(&err)
+	if err := q.checkBuild(); err != nil {
+		return q.returnErr(err)
+	}
+	done, err := q.track()
+	if err != nil {
+		return q.returnErr(err)
+	}
+	defer done()
+
+	rows, err := q.queryRows(ctx)
+	if err != nil {
+		return q.returnErr(err)
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return q.returnErr(err)
+	}
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return q.returnErr(err)
+	}
+	valueMaps := make([]ValueMap, len(dests))
+	for i, t := range elemTypes {
+		valueMaps[i] = makeValueMap(reflect.New(t).Elem(), nil, "")
+	}
+	split, err := splitColumns(elemTypes, valueMaps, columns, q.columnMatching)
+	if err != nil {
+		return q.returnErr(err)
+	}
+
+	fields := getScanSlice()
+	defer putScanSlice(fields)
+	elements := make([]reflect.Value, len(dests))
+	for rows.Next() {
+		for i, t := range elemTypes {
+			elements[i] = reflect.New(t).Elem()
+			valueMaps[i] = makeValueMap(elements[i], valueMaps[i], "")
+		}
+		null, err := split.nullDests(rows, valueMaps, elemIsPtr)
+		if err != nil {
+			return q.returnErr(err)
+		}
+		*fields = split.fields(valueMaps, null, (*fields)[:0])
+		decodeColumns(q.coercion, columnTypes, *fields)
+		if err = rows.Scan(*fields...); err != nil {
+			return q.returnErr(err)
+		}
+		for i, element := range elements {
+			if null[i] {
+				element = reflect.Zero(reflect.PtrTo(elemTypes[i]))
+			} else if elemIsPtr[i] {
+				element = element.Addr()
+			}
+			slices[i].Set(reflect.Append(slices[i], element))
+		}
+	}
+	return q.returnErr(rows.Err())
+}
+
+// FindMulti scans the rows of a join into the slices of structs dests, see FindMultiContext.
+func (q *Q) FindMulti(dests ...interface{}) error {
+	return q.FindMultiContext(q.baseContext(), dests...)
+}
+
+// multiColumn is the destination of a column scanned into multiple structs.
+type multiColumn struct {
+	// dest is the index of the destination struct.
+	dest int
+	// field is the name of the field, which doesn't exist if the column isn't scanned.
+	field string
+}
+
+type multiColumns []multiColumn
+
+// splitColumns splits columns across the structs of types, whose fields are in valueMaps. See FirstMultiContext.
+func splitColumns(types []reflect.Type, valueMaps []ValueMap, columns []string,
+	matching ColumnMatching) (multiColumns, error) {
+	prefixes := make([][]string, len(types))
+	matchers := make([]func(string) string, len(types))
+	for i, t := range types {
+		prefixes[i] = []string{t.Name()}
+		if tn, ok := reflect.New(t).Interface().(interface{ TableName() string }); ok {
+			prefixes[i] = append(prefixes[i], tn.TableName())
+		}
+		matchers[i] = columnMatcher(valueMaps[i], matching)
+	}
+
+	split := make(multiColumns, len(columns))
+	seen := make(map[string]bool)
+	cur := 0
+	for i, column := range columns {
+		if dest, name, ok := prefixedColumn(prefixes, column); ok {
+			split[i] = multiColumn{dest, matchers[dest](name)}
+			continue
+		}
+		field := matchers[cur](column)
+		_, found := valueMaps[cur][field]
+		if cur+1 < len(types) {
+			next := matchers[cur+1](column)
+			_, inNext := valueMaps[cur+1][next]
+			if found && seen[field] || !found && inNext {
+				// The column starts the next destination.
+				cur++
+				seen = make(map[string]bool)
+				field, found = next, inNext
+			}
+		}
+		if found {
+			seen[field] = true
+		}
+		split[i] = multiColumn{cur, field}
+	}
+
+	for dest, m := range valueMaps {
+		var names []string
+		for _, c := range split {
+			if c.dest == dest {
+				names = append(names, c.field)
+			}
+		}
+		if err := checkColumnNames(m, names); err != nil {
+			return nil, err
+		}
+	}
+	return split, nil
+}
+
+// prefixedColumn returns the index of the destination and the name of column if it's of the form prefix.name, with
+// prefix one of the destination's prefixes.
+func prefixedColumn(prefixes [][]string, column string) (dest int, name string, ok bool) {
+	i := strings.IndexByte(column, '.')
+	if i < 0 {
+		return 0, "", false
+	}
+	for dest, p := range prefixes {
+		for _, prefix := range p {
+			if prefix != "" && strings.EqualFold(prefix, column[:i]) {
+				return dest, column[i+1:], true
+			}
+		}
+	}
+	return 0, "", false
+}
+
+// fields returns the scan destinations of the columns in the structs of valueMaps, appended to values. The columns of
+// the null destinations are ignored.
+func (s multiColumns) fields(valueMaps []ValueMap, null []bool, values []interface{}) []interface{} {
+	for _, c := range s {
+		var v interface{} = &ignore
+		if value, ok := valueMaps[c.dest][c.field]; ok && !null[c.dest] {
+			v = value.Addr().Interface()
+		}
+		values = append(values, v)
+	}
+	return values
+}
+
+// nullDests returns which of the nullable destinations have only NULL columns in the current row of rows. A
+// destination without columns isn't null. The row is scanned to find out, it can be scanned again.
+func (s multiColumns) nullDests(rows *sql.Rows, valueMaps []ValueMap, nullable []bool) ([]bool, error) {
+	null := make([]bool, len(nullable))
+	if !anyTrue(nullable) {
+		return null, nil
+	}
+	values := make([]interface{}, len(s))
+	dest := make([]interface{}, len(s))
+	for i := range dest {
+		dest[i] = &values[i]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return nil, err
+	}
+	notNull := make([]bool, len(nullable))
+	for i, c := range s {
+		if _, ok := valueMaps[c.dest][c.field]; !ok {
+			continue
+		}
+		null[c.dest] = nullable[c.dest] && !notNull[c.dest]
+		if values[i] != nil {
+			null[c.dest], notNull[c.dest] = false, true
+		}
+	}
+	return null, nil
+}
+
+func anyTrue(b []bool) bool {
+	for _, v := range b {
+		if v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package querier
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"testing"
+)
+
+type multiUser struct {
+	ID   int
+	Name string
+}
+
+type multiAccount struct {
+	ID      int
+	UserID  int `db:"user_id"`
+	Balance int
+}
+
+func (*multiAccount) TableName() string { return "accounts" }
+
+func TestFirstMulti(t *testing.T) {
+	tests := []struct {
+		columns []string
+		values  []driver.Value
+	}{
+		// Split by order.
+		{[]string{"ID", "Name", "ID", "user_id", "Balance"}, []driver.Value{int64(1), "foo", int64(2), int64(1), int64(100)}},
+		// Split by prefix.
+		{[]string{"accounts.Balance", "ID", "multiAccount.ID", "Name", "accounts.user_id"}, []driver.Value{int64(100), int64(1), int64(2), "foo", int64(1)}},
+	}
+	for _, tt := range tests {
+		db := openTestDB(t, func(string, []driver.Value) (*testRows, error) {
+			return &testRows{columns: tt.columns, values: [][]driver.Value{tt.values}}, nil
+		})
+		var (
+			user    multiUser
+			account multiAccount
+		)
+		if err := New(db, Default{}).Write("SELECT * FROM user JOIN accounts").FirstMulti(&user, &account); err != nil {
+			t.Fatal(err)
+		}
+		if want := (multiUser{1, "foo"}); user != want {
+			t.Errorf("FirstMulti() of %v user = %+v, want %+v", tt.columns, user, want)
+		}
+		if want := (multiAccount{2, 1, 100}); account != want {
+			t.Errorf("FirstMulti() of %v account = %+v, want %+v", tt.columns, account, want)
+		}
+		db.Close()
+	}
+}
+
+func TestFindMulti(t *testing.T) {
+	db := openTestDB(t, func(string, []driver.Value) (*testRows, error) {
+		return &testRows{
+			columns: []string{"ID", "Name", "ID", "user_id", "Balance"},
+			values: [][]driver.Value{
+				{int64(1), "foo", int64(2), int64(1), int64(100)},
+				{int64(1), "foo", int64(3), int64(1), int64(50)},
+			},
+		}, nil
+	})
+	defer db.Close()
+
+	var (
+		users    []multiUser
+		accounts []*multiAccount
+	)
+	if err := New(db, Default{}).Write("SELECT * FROM user JOIN accounts").FindMulti(&users, &accounts); err != nil {
+		t.Fatal(err)
+	}
+	if want := []multiUser{{1, "foo"}, {1, "foo"}}; !reflect.DeepEqual(users, want) {
+		t.Errorf("FindMulti() users = %+v, want %+v", users, want)
+	}
+	if want := []*multiAccount{{2, 1, 100}, {3, 1, 50}}; !reflect.DeepEqual(accounts, want) {
+		t.Errorf("FindMulti() accounts = %+v, want %+v", accounts, want)
+	}
+}
+
+func TestMultiNullRelation(t *testing.T) {
+	db := openTestDB(t, func(string, []driver.Value) (*testRows, error) {
+		return &testRows{
+			columns: []string{"ID", "Name", "ID", "user_id", "Balance"},
+			values: [][]driver.Value{
+				{int64(1), "foo", nil, nil, nil},
+				{int64(2), "bar", int64(3), int64(2), int64(0)},
+			},
+		}, nil
+	})
+	defer db.Close()
+
+	var (
+		users    []multiUser
+		accounts []*multiAccount
+	)
+	if err := New(db, Default{}).Write("SELECT * FROM user LEFT JOIN accounts").FindMulti(&users, &accounts); err != nil {
+		t.Fatal(err)
+	}
+	if want := []*multiAccount{nil, {3, 2, 0}}; !reflect.DeepEqual(accounts, want) {
+		t.Errorf("FindMulti() accounts = %+v, want %+v", accounts, want)
+	}
+
+	var user multiUser
+	account := &multiAccount{ID: 42}
+	if err := New(db, Default{}).Write("SELECT * FROM user LEFT JOIN accounts").FirstMulti(&user, &account); err != nil {
+		t.Fatal(err)
+	}
+	if account != nil {
+		t.Errorf("FirstMulti() account = %+v, want nil", account)
+	}
+}
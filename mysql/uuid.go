@@ -0,0 +1,81 @@
+package mysql
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+)
+
+var reflectTypeUUID = reflect.TypeOf(UUID(""))
+
+// UUID is a UUID in its text form, e.g. 01890a5d-ac96-774b-bcce-b302099a8057, that's stored in a BINARY(16) column,
+// which makes its indexes less than half the size of a CHAR(36) column. It's converted on the client when it's bound
+// and scanned, so queries compare the column with the UUID as usual:
+//
+//	q.Write("SELECT * FROM user WHERE id = ?", mysql.UUID(id))
+//
+// The bytes are those of UUID_TO_BIN without swapping, so BIN_TO_UUID(id) returns the text form in SQL. The empty
+// UUID is NULL. It can be generated by InsertStruct, e.g. `db:"id,,pk,id=uuidv7"`.
+type UUID string
+
+// Value returns the 16 bytes of u, see driver.Valuer.
+func (u UUID) Value() (driver.Value, error) {
+	if u == "" {
+		return nil, nil
+	}
+	b, err := u.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Bytes returns the 16 bytes of u, or an error if it isn't a UUID. The hyphens are optional.
+func (u UUID) Bytes() ([]byte, error) {
+	s := make([]byte, 0, 32)
+	for i := 0; i < len(u); i++ {
+		if u[i] != '-' {
+			s = append(s, u[i])
+		}
+	}
+	b := make([]byte, 16)
+	if len(s) != 32 {
+		return nil, fmt.Errorf("invalid UUID %q", string(u))
+	}
+	if _, err := hex.Decode(b, s); err != nil {
+		return nil, fmt.Errorf("invalid UUID %q", string(u))
+	}
+	return b, nil
+}
+
+// Scan scans the 16 bytes of a BINARY(16) column, or the text form of BIN_TO_UUID, see sql.Scanner.
+func (u *UUID) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		*u = ""
+	case []byte:
+		if len(src) != 16 {
+			return u.Scan(string(src))
+		}
+		var buf [36]byte
+		hex.Encode(buf[0:8], src[0:4])
+		buf[8] = '-'
+		hex.Encode(buf[9:13], src[4:6])
+		buf[13] = '-'
+		hex.Encode(buf[14:18], src[6:8])
+		buf[18] = '-'
+		hex.Encode(buf[19:23], src[8:10])
+		buf[23] = '-'
+		hex.Encode(buf[24:], src[10:])
+		*u = UUID(buf[:])
+	case string:
+		if _, err := UUID(src).Bytes(); err != nil {
+			return err
+		}
+		*u = UUID(src)
+	default:
+		return fmt.Errorf("can't scan %T into UUID", src)
+	}
+	return nil
+}
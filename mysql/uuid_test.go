@@ -0,0 +1,31 @@
+package mysql
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestUUID(t *testing.T) {
+	const id = "01890a5d-ac96-774b-bcce-b302099a8057"
+	v, err := UUID(id).Value()
+	b, _ := v.([]byte)
+	if err != nil || !bytes.Equal(b, []byte{0x01, 0x89, 0x0a, 0x5d, 0xac, 0x96, 0x77, 0x4b, 0xbc, 0xce, 0xb3, 0x02, 0x09, 0x9a, 0x80, 0x57}) {
+		t.Fatalf("Value() = %x, %v", v, err)
+	}
+	var u UUID
+	if err = u.Scan(b); err != nil || u != id {
+		t.Errorf("Scan() = %q, %v, want %q", u, err, id)
+	}
+	if err = u.Scan([]byte(id)); err != nil || u != id {
+		t.Errorf("Scan() of the text form = %q, %v, want %q", u, err, id)
+	}
+	if err = u.Scan(nil); err != nil || u != "" {
+		t.Errorf("Scan(nil) = %q, %v, want an empty UUID", u, err)
+	}
+	if v, err = UUID("").Value(); v != nil || err != nil {
+		t.Errorf("Value() of the empty UUID = %v, %v, want NULL", v, err)
+	}
+	if _, err = UUID("not-a-uuid").Value(); err == nil {
+		t.Error("Value() of an invalid UUID succeeded")
+	}
+}
@@ -0,0 +1,134 @@
+package querier
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// WriteNamed writes query, replacing named parameters of the form :name with bindvars and adding the values of args
+// as parameters, e.g.
+//
+//	q.WriteNamed("SELECT * FROM user WHERE name = :name", map[string]interface{}{"name": "foo"})
+//
+// Colons in string literals, quoted identifiers, comments and casts (::) are left as is. A missing argument results
+// in an error when the query is executed. Another prefix than the colon can be set with the NamedPrefix of the
+// Options, e.g. @name.
+func (q *Q) WriteNamed(query string, args map[string]interface{}) *Q {
+	return q.writeNamed(query, func(name string) (interface{}, bool) {
+		v, ok := args[name]
+		return v, ok
+	})
+}
+
+// writeNamed writes query, looking up the values of named parameters with arg.
+func (q *Q) writeNamed(query string, arg func(name string) (interface{}, bool)) *Q {
+	q.writeSep()
+	start := len(q.params)
+	prefix := q.opts.namedPrefix()
+	stops := "'\"`-/" + string(prefix)
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end := skipQuoted(query, i)
+			q.query.WriteString(query[i:end])
+			i = end
+		case c == '-' && i+1 < len(query) && query[i+1] == '-':
+			end := indexFrom(query, "\n", i)
+			q.query.WriteString(query[i:end])
+			i = end
+		case c == '/' && i+1 < len(query) && query[i+1] == '*':
+			end := indexFrom(query, "*/", i+2)
+			if end < len(query) {
+				end += 2
+			}
+			q.query.WriteString(query[i:end])
+			i = end
+		case c == prefix && i+1 < len(query) && query[i+1] == prefix:
+			// A cast, e.g. ::text, or an escaped prefix.
+			q.query.WriteString(query[i : i+2])
+			i += 2
+		case c == prefix && i+1 < len(query) && isNameByte(query[i+1]):
+			end := i + 1
+			for end < len(query) && isNameByte(query[end]) {
+				end++
+			}
+			name := query[i+1 : end]
+			v, ok := arg(name)
+			if !ok {
+				q.fail(fmt.Errorf("missing named parameter %q", name))
+			}
+			q.writeBindVar(q.nextBindVar())
+			q.params = append(q.params, v)
+			i = end
+		default:
+			end := i + 1
+			for end < len(query) && strings.IndexByte(stops, query[end]) < 0 {
+				end++
+			}
+			q.writeText(query[i:end])
+			i = end
+		}
+	}
+	q.record(SegmentText, query, nil, start)
+	return q
+}
+
+// skipQuoted returns the index after the quoted string starting at i. A doubled quote is part of the string.
+func skipQuoted(s string, i int) int {
+	quote := s[i]
+	for j := i + 1; j < len(s); j++ {
+		if s[j] == quote {
+			if j+1 < len(s) && s[j+1] == quote {
+				j++
+				continue
+			}
+			return j + 1
+		}
+	}
+	return len(s)
+}
+
+// indexFrom returns the index of substr in s from i, or len(s) if it isn't present.
+func indexFrom(s, substr string, i int) int {
+	for j := i; j+len(substr) <= len(s); j++ {
+		if s[j:j+len(substr)] == substr {
+			return j
+		}
+	}
+	return len(s)
+}
+
+func isNameByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// BindStruct writes query, replacing named parameters with bindvars and adding the values of the fields of struct arg
+// as parameters, e.g.
+//
+//	q.BindStruct("UPDATE user SET name = :name WHERE id = :ID", &user)
+//
+// A named parameter refers to a field by its mapped name, or else by its Go name. Parameters of sensitive fields are
+// marked sensitive and encrypted fields are encrypted. See WriteNamed.
+func (q *Q) BindStruct(query string, arg interface{}) *Q {
+	values := Values(arg)
+	sensitive := make(map[string]bool)
+	for _, field := range Fields(arg).Select() {
+		sensitive[field.Name] = field.Sensitive
+	}
+	v := reflect.ValueOf(arg).Elem()
+	return q.writeNamed(query, func(name string) (interface{}, bool) {
+		if value, ok := values[name]; ok {
+			param := value.Addr().Interface()
+			if sensitive[name] {
+				param = Sensitive(param)
+			}
+			return param, true
+		}
+		if field, ok := v.Type().FieldByName(name); ok && field.PkgPath == "" {
+			return v.FieldByIndex(field.Index).Addr().Interface(), true
+		}
+		return nil, false
+	})
+}
@@ -0,0 +1,53 @@
+package querier
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+type numberedDialect struct {
+	Default
+}
+
+func (numberedDialect) BindVar(_ *Q, i int) string {
+	return "$" + strconv.Itoa(i+1)
+}
+
+func TestWriteNamed(t *testing.T) {
+	args := map[string]interface{}{"id": 1, "name": "foo"}
+	q := New(nil, numberedDialect{}).WriteNamed(
+		`SELECT id::text, ':x', ":y" FROM user -- :z
+WHERE id = :id /* :w */ AND name = :name OR alias = :name`, args)
+
+	want := `SELECT id::text, ':x', ":y" FROM user -- :z
+WHERE id = $1 /* :w */ AND name = $2 OR alias = $3`
+	if q.String() != want {
+		t.Errorf("String() = %q, want %q", q.String(), want)
+	}
+	if want := []interface{}{1, "foo", "foo"}; !reflect.DeepEqual(q.Params(), want) {
+		t.Errorf("Params() = %v, want %v", q.Params(), want)
+	}
+
+	q = New(&testExecutor{}, Default{}).WriteNamed("DELETE FROM user WHERE id = :id", nil)
+	if err := q.Exec(); err == nil {
+		t.Error("Exec() with missing named parameter returned no error")
+	}
+}
+
+func TestBindStruct(t *testing.T) {
+	m := auditModel{ID: 1, Name: "foo", Password: "secret"}
+	q := New(nil, numberedDialect{}).BindStruct("UPDATE user SET name = :name, password = :password WHERE id = :ID", &m)
+
+	if want := "UPDATE user SET name = $1, password = $2 WHERE id = $3"; q.String() != want {
+		t.Errorf("String() = %q, want %q", q.String(), want)
+	}
+	if want := []interface{}{&m.Name, Sensitive(&m.Password), &m.ID}; !reflect.DeepEqual(q.Params(), want) {
+		t.Errorf("Params() = %v, want %v", q.Params(), want)
+	}
+
+	q = New(&testExecutor{}, Default{}).BindStruct("DELETE FROM user WHERE id = :unknown", &m)
+	if err := q.Exec(); err == nil {
+		t.Error("Exec() with unknown field returned no error")
+	}
+}
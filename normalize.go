@@ -0,0 +1,137 @@
+package querier
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ParamConverter converts a parameter of a custom type into a driver.Value, see RegisterParamConverter.
+type ParamConverter func(v interface{}) (driver.Value, error)
+
+var (
+	paramConvertersMu sync.RWMutex
+	paramConverters   = make(map[reflect.Type]ParamConverter)
+)
+
+// RegisterParamConverter registers fn to convert the parameters of the type of sample, for types that don't implement
+// driver.Valuer and can't be changed, e.g. of another package:
+//
+//	querier.RegisterParamConverter(decimal.Decimal{}, func(v interface{}) (driver.Value, error) {
+//		return v.(decimal.Decimal).String(), nil
+//	})
+//
+// Converters must be registered before queries are executed, usually in an init function. A registered converter
+// replaces the previous one of the type. Panics when sample is nil.
+func RegisterParamConverter(sample interface{}, fn ParamConverter) {
+	if sample == nil {
+		panic("sample is nil")
+	}
+	paramConvertersMu.Lock()
+	defer paramConvertersMu.Unlock()
+	paramConverters[reflect.TypeOf(sample)] = fn
+}
+
+// ParamError is returned when a parameter can't be converted into a value the driver accepts.
+type ParamError struct {
+	// Index is the index of the parameter, starting at 0.
+	Index int
+	Param interface{}
+	Err   error
+}
+
+func (e *ParamError) Error() string {
+	return fmt.Sprintf("parameter %d of type %T: %v", e.Index, e.Param, e.Err)
+}
+
+func (e *ParamError) Unwrap() error {
+	return e.Err
+}
+
+// normalizeParams returns the parameters of q converted into driver values before they're passed to the driver:
+// pointers are dereferenced, driver.Valuers are called and the values of custom types are converted by their
+// ParamConverter or into the basic type of their kind, e.g. a string of type Status into a string. Values of basic
+// types are passed as is. A parameter that can't be converted results in a *ParamError, instead of a vague error of
+// the driver. The parameters are passed as is with the RawParams of the Options.
+func (q *Q) normalizeParams() ([]interface{}, error) {
+	if q.opts.RawParams {
+		return q.params, nil
+	}
+	params, copied := q.params, false
+	for i, param := range q.params {
+		v, converted, err := normalizeParam(param)
+		if err != nil {
+			return nil, &ParamError{Index: i, Param: param, Err: err}
+		}
+		if !converted {
+			continue
+		}
+		if !copied {
+			// Copy on the first converted parameter, the parameters of q are kept as is.
+			params, copied = append([]interface{}(nil), q.params...), true
+		}
+		params[i] = v
+	}
+	return params, nil
+}
+
+// normalizeParam returns param converted into a driver value, and whether it was converted. An unconverted param is
+// passed as is.
+func normalizeParam(param interface{}) (interface{}, bool, error) {
+	switch param := param.(type) {
+	case nil:
+		return nil, false, nil
+	case sensitiveParam:
+		// Keep the value redacted in errors of the driver.
+		v, converted, err := normalizeParam(param.v)
+		if !converted {
+			return param, false, err
+		}
+		return sensitiveParam{v}, true, err
+	case sql.NamedArg:
+		v, converted, err := normalizeParam(param.Value)
+		param.Value = v
+		return param, converted, err
+	case sql.Out:
+		return param, false, nil
+	}
+	t := reflect.TypeOf(param)
+	paramConvertersMu.RLock()
+	fn := paramConverters[t]
+	paramConvertersMu.RUnlock()
+	if fn != nil {
+		v, err := fn(param)
+		return v, true, err
+	}
+	if driver.IsValue(param) {
+		return param, false, nil
+	}
+	if k := t.Kind(); k == reflect.Uint64 || k == reflect.Uint {
+		// Drivers that accept large unsigned integers, e.g. MySQL's, convert them themselves.
+		return param, false, nil
+	}
+	if isBasicType(t) {
+		// A value of a basic type, e.g. int, is passed as is, the driver may convert it itself.
+		return param, false, nil
+	}
+	v, err := driver.DefaultParameterConverter.ConvertValue(param)
+	if err != nil {
+		if _, ok := param.(driver.Valuer); ok {
+			return nil, false, err
+		}
+		return nil, false, fmt.Errorf("unsupported type, implement driver.Valuer or register a ParamConverter")
+	}
+	return v, true, nil
+}
+
+// isBasicType returns true if t is an unnamed bool, number or string type.
+func isBasicType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64, reflect.String:
+		return t.PkgPath() == ""
+	}
+	return false
+}
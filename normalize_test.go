@@ -0,0 +1,63 @@
+package querier
+
+import (
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testStatus string
+
+type testMoney struct {
+	cents int64
+}
+
+func TestNormalizeParams(t *testing.T) {
+	RegisterParamConverter(testMoney{}, func(v interface{}) (driver.Value, error) {
+		return v.(testMoney).cents, nil
+	})
+	var ex testExecutor
+	name := "foo"
+	var nilName *string
+	err := New(&ex, Default{}).
+		Write("UPDATE user SET name = ?, nick = ?, status = ?, balance = ?, age = ?", &name, nilName, testStatus("active"), testMoney{150}, 30).
+		Exec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []interface{}{"foo", nil, "active", int64(150), 30}
+	if got := ex.queries[0][1:]; !reflect.DeepEqual(got, want) {
+		t.Errorf("executed with %#v, want %#v", got, want)
+	}
+
+	err = New(&ex, Default{}).Write("SELECT * FROM user WHERE id = ? AND tags = ?", 1, []string{"a"}).Exec()
+	var paramErr *ParamError
+	if !errors.As(err, &paramErr) || paramErr.Index != 1 || !strings.Contains(err.Error(), "[]string") {
+		t.Errorf("Exec() = %v, want a *ParamError of parameter 1", err)
+	}
+	if len(ex.queries) != 1 {
+		t.Error("query with an unsupported parameter was executed")
+	}
+
+	// RawParams passes the parameters as is.
+	tags := []string{"a"}
+	err = New(&ex, Default{}).SetOptions(Options{RawParams: true}).Write("SELECT * FROM user WHERE tags = ?", tags).Exec()
+	if err != nil || !reflect.DeepEqual(ex.queries[1][1:], []interface{}{tags}) {
+		t.Errorf("Exec() with RawParams = %v, executed %v", err, ex.queries[1:])
+	}
+}
+
+func TestNormalizeParamsAllocs(t *testing.T) {
+	q := New(nil, Default{}).Write("SELECT * FROM user WHERE id = ? AND name = ? AND age = ? AND data = ?",
+		int64(1), "foo", 30, []byte("bar"))
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, err := q.normalizeParams(); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("normalizeParams() of driver values and basic types = %v allocs, want 0", allocs)
+	}
+}
@@ -0,0 +1,79 @@
+package querier
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInTooLarge means that In was given more values than the MaxIn of the Options. The error returned is an
+// *InTooLargeError.
+var ErrInTooLarge = errors.New("too many values for IN")
+
+// InTooLargeError is returned when a query is executed whose In was given more values than the MaxIn of the Options.
+type InTooLargeError struct {
+	Values, Max int
+}
+
+func (e *InTooLargeError) Error() string {
+	return fmt.Sprintf("IN of %d values exceeds the maximum of %d", e.Values, e.Max)
+}
+
+// Is returns true if target is ErrInTooLarge.
+func (e *InTooLargeError) Is(target error) bool {
+	return target == ErrInTooLarge
+}
+
+// Options are the defaults of building queries. The zero Options are the defaults of New.
+type Options struct {
+	// Separator is written between the writes of a query, Space if it's empty, see SetSeparator.
+	Separator string
+	// Strict enables strict mode, see SetStrict.
+	Strict bool
+	// NamedPrefix is the character that starts the named parameters of WriteNamed, ':' if it's zero, e.g. '@' for
+	// @name.
+	NamedPrefix byte
+	// MaxIn is the maximum number of values In writes bind variables for, zero for no maximum. More values result in
+	// an *InTooLargeError when the query is executed, instead of a statement that exceeds the limits of the database.
+	MaxIn int
+	// RawParams passes the parameters to the driver as is, instead of converting them into driver values first, for
+	// drivers that accept more types, e.g. slices as arrays.
+	RawParams bool
+	// DeferredErrors returns the errors of the functions deferred with DeferErr from the execution, joined with the
+	// error of the query. They're only returned by Error otherwise.
+	DeferredErrors bool
+	// SkipParamCheck executes queries without comparing the number of bind variables with the number of parameters,
+	// for queries whose bind variables can't be counted, e.g. an operator that's a bind variable in another database.
+	SkipParamCheck bool
+}
+
+func (o Options) separator() string {
+	if o.Separator == "" {
+		return Space
+	}
+	return o.Separator
+}
+
+func (o Options) namedPrefix() byte {
+	if o.NamedPrefix == 0 {
+		return ':'
+	}
+	return o.NamedPrefix
+}
+
+// SetOptions sets the Options of q, which sets its separator and strict mode. Reset restores the separator of the
+// Options, New of q copies them.
+func (q *Q) SetOptions(opts Options) *Q {
+	q.opts = opts
+	q.sep, q.strict = opts.separator(), opts.Strict
+	return q
+}
+
+// Options returns the Options of q, see SetOptions.
+func (q *Q) Options() Options {
+	return q.opts
+}
+
+// SetOptions sets the Options of the queries created by New, so they don't have to be set on every Querier.
+func (db *DB) SetOptions(opts Options) {
+	db.opts = opts
+}
@@ -0,0 +1,40 @@
+package querier
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestOptions(t *testing.T) {
+	db := NewDB(nil, Default{})
+	db.SetOptions(Options{Separator: "\n", Strict: true, NamedPrefix: '@', MaxIn: 2})
+
+	q := db.New().Write("SELECT *").Write("FROM user").WriteNamed("WHERE name = @name AND x = '@y'", map[string]interface{}{"name": "foo"})
+	if got, want := q.String(), "SELECT *\nFROM user\nWHERE name = ? AND x = '@y'"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if err := q.Reset().Writef("SELECT %s", "1; DROP TABLE user").checkBuild(); err == nil {
+		t.Error("Writef() in strict mode accepted an invalid argument")
+	}
+	if got, want := q.Reset().Write("a").Write("b").String(), "a\nb"; got != want {
+		t.Errorf("String() after Reset = %q, want %q", got, want)
+	}
+	if got := q.New().Options(); got != db.opts {
+		t.Errorf("Options() of New = %+v, want %+v", got, db.opts)
+	}
+
+	err := db.New().Write("SELECT * FROM user WHERE id").In([]int{1, 2, 3}).checkBuild()
+	var inErr *InTooLargeError
+	if !errors.Is(err, ErrInTooLarge) || !errors.As(err, &inErr) || inErr.Values != 3 || inErr.Max != 2 {
+		t.Errorf("In() of 3 values = %v, want an *InTooLargeError", err)
+	}
+	if err = db.New().Write("SELECT * FROM user WHERE id").In([]int{1, 2}).checkBuild(); err != nil {
+		t.Errorf("In() of 2 values = %v", err)
+	}
+
+	// The zero Options are the defaults.
+	if got, want := New(nil, Default{}).SetOptions(Options{}).WriteNamed("SELECT :a::text", map[string]interface{}{"a": 1}).String(),
+		"SELECT ?::text"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
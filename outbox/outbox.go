@@ -0,0 +1,167 @@
+// Package outbox implements the transactional outbox pattern using Querier.
+//
+// Events are added to an outbox table in the same transaction as the change they describe, using Add with a Querier of
+// that transaction. The table can be created with the migrator using Outbox.Model. Poll, or Run, dispatches the
+// unpublished events in order to a Handler and marks them published. Delivery is at least once: an event is
+// dispatched again when marking it published fails.
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/semrekkers/querier"
+	"github.com/semrekkers/querier/internal/table"
+	"github.com/semrekkers/querier/migrator"
+)
+
+// DefaultBatchSize is the number of events dispatched by Poll when Outbox.BatchSize is zero.
+const DefaultBatchSize = 100
+
+// Event is an event in the outbox.
+type Event struct {
+	ID          string     `db:"id,CHAR(32) NOT NULL"`
+	Topic       string     `db:"topic"`
+	Payload     []byte     `db:"payload,TEXT NOT NULL"`
+	CreatedAt   time.Time  `db:"created_at"`
+	PublishedAt *time.Time `db:"published_at"`
+}
+
+// Decode decodes the event's JSON payload into v.
+func (e *Event) Decode(v interface{}) error {
+	return json.Unmarshal(e.Payload, v)
+}
+
+// Handler publishes an event. Returning an error stops dispatching, the event is dispatched again by the next Poll.
+type Handler func(ctx context.Context, event *Event) error
+
+// Outbox is an outbox stored in table. It is safe for multiple goroutines to call it's methods.
+type Outbox struct {
+	db    *sql.DB
+	d     querier.Dialect
+	table string
+
+	// BatchSize is the maximum number of events dispatched by Poll. Zero means DefaultBatchSize.
+	BatchSize int
+}
+
+// New returns a new Outbox, stored in table.
+func New(db *sql.DB, d querier.Dialect, table string) *Outbox {
+	return &Outbox{db: db, d: d, table: table}
+}
+
+// Model returns the model of the outbox's table, to be migrated by the migrator.
+func (o *Outbox) Model() migrator.Model {
+	return &model{Model: table.Model{Name: o.table}}
+}
+
+// Add adds an event with payload v, encoded as JSON, to the outbox. It is executed by q's Executor and in q's context,
+// q should be a Querier of the transaction that makes the change the event describes. q itself is not modified.
+func (o *Outbox) Add(q *querier.Q, topic string, v interface{}) error {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	id, err := table.NewID()
+	if err != nil {
+		return err
+	}
+	event := Event{
+		ID:        id,
+		Topic:     topic,
+		Payload:   payload,
+		CreatedAt: time.Now().UTC(),
+	}
+	insert := q.New()
+	fields := insert.Fields(&event).Select()
+	return insert.Writef("INSERT INTO %s (", o.table).
+		WriteFields("{name}", querier.FieldSep, fields...).
+		WriteRaw(") VALUES (").
+		WriteValueMap("{bindVar}", querier.FieldSep, querier.Values(&event), fields...).
+		WriteRaw(")").
+		ExecContext(q.Context())
+}
+
+// Poll dispatches unpublished events to fn in the order they were added, and marks them published. It returns the
+// number of published events. The events are locked using FOR UPDATE SKIP LOCKED when the dialect supports it and
+// transactions, so multiple pollers don't dispatch the same events.
+func (o *Outbox) Poll(ctx context.Context, fn Handler) (n int, err error) {
+	var (
+		ex querier.Executor = o.db
+		tx *sql.Tx
+	)
+	if querier.Supports(o.d, querier.FeatureTransactions) {
+		if tx, err = o.db.BeginTx(ctx, nil); err != nil {
+			return 0, err
+		}
+		defer tx.Rollback()
+		ex = tx
+	}
+
+	batchSize := o.BatchSize
+	if batchSize == 0 {
+		batchSize = DefaultBatchSize
+	}
+	var events []Event
+	q := querier.New(ex, o.d)
+	q.Write("SELECT").
+		WriteFields("{name}", querier.FieldSep, q.Fields(&Event{}).Select()...).
+		Writef("FROM %s WHERE published_at IS NULL ORDER BY created_at, id LIMIT %d", o.table, batchSize)
+	if tx != nil && table.CanSkipLocked(o.d) {
+		q.ForUpdate().SkipLocked()
+	}
+	if err = q.FindContext(ctx, &events); err != nil {
+		return 0, err
+	}
+
+	for i := range events {
+		if err = fn(ctx, &events[i]); err != nil {
+			break
+		}
+		now := time.Now().UTC()
+		err = querier.New(ex, o.d).
+			Writef("UPDATE %s", o.table).
+			WriteValues("SET published_at = {bindVar}", "", now).
+			WriteValues("WHERE id = {bindVar}", "", events[i].ID).
+			ExecContext(ctx)
+		if err != nil {
+			break
+		}
+		events[i].PublishedAt = &now
+		n++
+	}
+	if n == 0 || tx == nil {
+		return n, err
+	}
+	// Keep the events that were published, even if dispatching a later one failed.
+	if commitErr := tx.Commit(); commitErr != nil {
+		return 0, commitErr
+	}
+	return n, err
+}
+
+// Run polls the outbox until ctx is done, waiting interval when there are no events to dispatch or when dispatching
+// failed. It returns the context's error.
+func (o *Outbox) Run(ctx context.Context, interval time.Duration, fn Handler) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		n, err := o.Poll(ctx, fn)
+		if n > 0 && err == nil {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// model is the Model of a outbox's table.
+type model struct {
+	Event
+	table.Model
+}
@@ -0,0 +1,130 @@
+package outbox
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/semrekkers/querier"
+	"github.com/semrekkers/querier/internal/testdriver"
+)
+
+var eventColumns = []string{"id", "topic", "payload", "created_at", "published_at"}
+
+func eventRows(ids ...string) [][]driver.Value {
+	rows := make([][]driver.Value, len(ids))
+	for i, id := range ids {
+		rows[i] = []driver.Value{id, "user.created", []byte(`{"id":1}`), time.Now().UTC(), nil}
+	}
+	return rows
+}
+
+// noTransactions doesn't support transactions.
+type noTransactions struct {
+	querier.Default
+}
+
+func (noTransactions) Supports(f querier.Feature) bool {
+	return f != querier.FeatureTransactions
+}
+
+func TestModel(t *testing.T) {
+	m := New(nil, querier.Default{}, "outbox").Model()
+	if m.TableName() != "outbox" {
+		t.Errorf("TableName() = %q, want outbox", m.TableName())
+	}
+	fields := querier.Fields(m).Select()
+	if len(fields) != len(eventColumns) {
+		t.Fatalf("Select() = %v, want the columns %v", fields, eventColumns)
+	}
+	for i, field := range fields {
+		if field.Name != eventColumns[i] {
+			t.Errorf("field %d = %s, want %s", i, field.Name, eventColumns[i])
+		}
+	}
+}
+
+func TestAdd(t *testing.T) {
+	var query string
+	var args []driver.Value
+	db := testdriver.Open(t, func(q string, a []driver.Value) (*testdriver.Result, error) {
+		query, args = q, a
+		return &testdriver.Result{RowsAffected: 1}, nil
+	})
+
+	o := New(db, querier.Default{}, "outbox")
+	if err := o.Add(querier.New(db, querier.Default{}), "user.created", map[string]int{"id": 1}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(query, "INSERT INTO outbox") {
+		t.Errorf("query = %q, want an insert into outbox", query)
+	}
+	if len(args) != 5 || len(args[0].(string)) != 32 || args[1] != "user.created" || string(args[2].([]byte)) != `{"id":1}` {
+		t.Errorf("args = %v", args)
+	}
+}
+
+func TestPoll(t *testing.T) {
+	var (
+		queries   []string
+		published []string
+	)
+	db := testdriver.Open(t, func(query string, args []driver.Value) (*testdriver.Result, error) {
+		queries = append(queries, query)
+		switch {
+		case strings.HasPrefix(query, "SELECT"):
+			return &testdriver.Result{Columns: eventColumns, Rows: eventRows("a", "b", "c")}, nil
+		case strings.HasPrefix(query, "UPDATE"):
+			published = append(published, args[1].(string))
+			return &testdriver.Result{RowsAffected: 1}, nil
+		}
+		return nil, nil
+	})
+
+	// Dispatching stops at the first error, the events published before it are committed.
+	errHandler := errors.New("broker unavailable")
+	var dispatched []string
+	n, err := New(db, querier.Default{}, "outbox").Poll(context.Background(), func(_ context.Context, event *Event) error {
+		if event.ID == "c" {
+			return errHandler
+		}
+		dispatched = append(dispatched, event.ID)
+		return nil
+	})
+	if n != 2 || err != errHandler {
+		t.Errorf("Poll() = %d, %v, want 2, %v", n, err, errHandler)
+	}
+	if strings.Join(dispatched, ",") != "a,b" || strings.Join(published, ",") != "a,b" {
+		t.Errorf("dispatched %v and published %v, want a and b", dispatched, published)
+	}
+	if queries[0] != "BEGIN" || queries[len(queries)-1] != "COMMIT" {
+		t.Errorf("executed %q, want a committed transaction", queries)
+	}
+	if want := "ORDER BY created_at, id LIMIT 100 FOR UPDATE SKIP LOCKED"; !strings.HasSuffix(queries[1], want) {
+		t.Errorf("select = %q, want suffix %q", queries[1], want)
+	}
+}
+
+func TestPollWithoutTransactions(t *testing.T) {
+	var queries []string
+	db := testdriver.Open(t, func(query string, args []driver.Value) (*testdriver.Result, error) {
+		queries = append(queries, query)
+		if strings.HasPrefix(query, "SELECT") {
+			return &testdriver.Result{Columns: eventColumns, Rows: eventRows("a")}, nil
+		}
+		return &testdriver.Result{RowsAffected: 1}, nil
+	})
+
+	o := New(db, noTransactions{}, "outbox")
+	o.BatchSize = 10
+	n, err := o.Poll(context.Background(), func(context.Context, *Event) error { return nil })
+	if n != 1 || err != nil {
+		t.Errorf("Poll() = %d, %v, want 1, nil", n, err)
+	}
+	if len(queries) != 2 || !strings.HasSuffix(queries[0], "LIMIT 10") {
+		t.Errorf("executed %q, want a select without locks and an update", queries)
+	}
+}
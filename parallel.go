@@ -0,0 +1,77 @@
+package querier
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+)
+
+// Errors contains the errors of concurrent tasks, see Parallel.
+type Errors []error
+
+// Error implements error.
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the errors, for errors.Is and errors.As.
+func (e Errors) Unwrap() []error {
+	return e
+}
+
+// Is returns true if one of the errors is target, for versions of Go without Unwrap() []error.
+func (e Errors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error that matches target, for versions of Go without Unwrap() []error.
+func (e Errors) As(target interface{}) bool {
+	for _, err := range e {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Parallel runs the independent tasks concurrently, each with a new Querier of the database, e.g. the queries of a
+// dashboard. The Queriers use a context derived from ctx, which is canceled when a task returns an error. Parallel
+// waits for all tasks and returns their errors as Errors, or nil if all succeeded.
+func (db *DB) Parallel(ctx context.Context, tasks ...func(*Q) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs Errors
+	)
+	wg.Add(len(tasks))
+	for _, task := range tasks {
+		go func(task func(*Q) error) {
+			defer wg.Done()
+			if err := task(db.New().WithContext(ctx)); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+				cancel()
+			}
+		}(task)
+	}
+	wg.Wait()
+
+	if errs != nil {
+		return errs
+	}
+	return nil
+}
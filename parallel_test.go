@@ -0,0 +1,60 @@
+package querier
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+func TestParallel(t *testing.T) {
+	db := NewDB(openTestDB(t, func(query string, _ []driver.Value) (*testRows, error) {
+		return &testRows{columns: []string{"count"}, values: [][]driver.Value{{int64(len(query))}}}, nil
+	}), Default{})
+	defer db.Close()
+
+	var users, orders int64
+	err := db.Parallel(context.Background(),
+		func(q *Q) error { return q.Write("SELECT COUNT(*) FROM user").Scan(&users) },
+		func(q *Q) error { return q.Write("SELECT COUNT(*) FROM orders").Scan(&orders) },
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if users != int64(len("SELECT COUNT(*) FROM user")) || orders != int64(len("SELECT COUNT(*) FROM orders")) {
+		t.Errorf("got %d and %d", users, orders)
+	}
+}
+
+func TestParallelErrors(t *testing.T) {
+	db := NewDB(nil, Default{})
+	errFoo, errBar := errors.New("foo"), errors.New("bar")
+
+	err := db.Parallel(context.Background(),
+		func(*Q) error { return errFoo },
+		func(q *Q) error {
+			<-q.ctx.Done()
+			return errBar
+		},
+		func(*Q) error { return nil },
+	)
+	var errs Errors
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Fatalf("got %v, want 2 errors", err)
+	}
+	if !errors.Is(err, errFoo) || !errors.Is(err, errBar) {
+		t.Errorf("got %v, want foo and bar", err)
+	}
+}
+
+func TestErrorsAs(t *testing.T) {
+	errFoo := errors.New("foo")
+	err := error(Errors{errFoo, &InTooLargeError{Values: 3, Max: 2}})
+	var inErr *InTooLargeError
+	if !errors.As(err, &inErr) || inErr.Max != 2 {
+		t.Errorf("errors.As(%v) = %v, want the *InTooLargeError", err, inErr)
+	}
+	if !errors.Is(err, errFoo) || !errors.Is(err, ErrInTooLarge) || errors.Is(err, ErrNoRecord) {
+		t.Errorf("errors.Is(%v) doesn't match the errors", err)
+	}
+}
@@ -0,0 +1,262 @@
+package querier
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrParamMismatch means that the number of bind variables in the query differs from the number of parameters. The
+// error returned is a *ParamMismatchError.
+var ErrParamMismatch = errors.New("number of bind variables and parameters differ")
+
+// ParamMismatchError is returned when a query is executed whose number of bind variables differs from its number of
+// parameters.
+type ParamMismatchError struct {
+	BindVars, Params int
+	// Offsets contains the byte offsets of the bind variables in the query.
+	Offsets []int
+}
+
+func (e *ParamMismatchError) Error() string {
+	return fmt.Sprintf("query has %d bind variables at offsets %v, but %d parameters", e.BindVars, e.Offsets, e.Params)
+}
+
+// Is returns true if target is ErrParamMismatch.
+func (e *ParamMismatchError) Is(target error) bool {
+	return target == ErrParamMismatch
+}
+
+// checkBuild returns the error that occurred while building the query, or a *ParamMismatchError if the number of
+// bind variables differs from the number of parameters. Named parameters, sql.NamedArg, aren't bound to ? and aren't
+// counted. The number isn't compared if SkipParamCheck of the Options is set.
+func (q *Q) checkBuild() error {
+	if q.buildErr != nil {
+		return q.buildErr
+	}
+	if _, ok := q.ex.(*sql.Tx); ok && q.afterCommit {
+		return ErrAfterCommitTx
+	}
+	if q.untracked || q.opts.SkipParamCheck {
+		return nil
+	}
+	params := len(q.params)
+	for _, param := range q.params {
+		if _, ok := param.(sql.NamedArg); ok {
+			params--
+		}
+	}
+	if len(q.bindVars) != params {
+		return &ParamMismatchError{BindVars: len(q.bindVars), Params: params, Offsets: q.bindVars}
+	}
+	return nil
+}
+
+// BindOffset sets the index of the next bind variable written by WriteValues, WriteValueMap and WriteNamed to n. Bind
+// variables are numbered automatically, but not those written as text, e.g. by Write, so with numbered placeholders
+// their count must be set to continue the numbering:
+//
+//	q.Write("SELECT * FROM user WHERE tenant = $1", tenant).BindOffset(1).
+//		WriteValues("AND id = {bindVar}", "", id) // AND id = $2
+func (q *Q) BindOffset(n int) *Q {
+	q.bindIndex = n
+	return q
+}
+
+// Param is a parameter that can be referred to multiple times in a query, see NewParam.
+type Param struct {
+	Value interface{}
+}
+
+// NewParam returns a parameter with value v, which can be written multiple times with WriteParam. With a Dialect of
+// numbered bind variables, e.g. $1, every write refers to the same bind variable, so v is bound once. With ? the bind
+// variable is written again and v is added again, so the parameters can't get out of step with the bind variables:
+//
+//	term := querier.NewParam("%foo%")
+//	q.Write("SELECT * FROM user WHERE name LIKE").WriteParam(term).Write("OR email LIKE").WriteParam(term)
+//
+// writes name LIKE $1 OR email LIKE $1 or name LIKE ? OR email LIKE ?, with the parameters %foo% or %foo% and %foo%.
+func NewParam(v interface{}) *Param {
+	return &Param{Value: v}
+}
+
+// WriteParam writes a bind variable for p, see NewParam.
+func (q *Q) WriteParam(p *Param) *Q {
+	q.writeSep()
+	start := len(q.params)
+	if bindVar, ok := q.paramVars[p]; ok {
+		// The bind variable is written again but bound once, it isn't tracked as another one.
+		q.query.WriteString(bindVar)
+		q.record(SegmentValues, bindVar, nil, start)
+		return q
+	}
+	bindVar := q.nextBindVar()
+	q.writeBindVar(bindVar)
+	q.params = append(q.params, p.Value)
+	if numbered := bindVar != q.d.BindVar(q, q.bindIndex); numbered {
+		if q.paramVars == nil {
+			q.paramVars = make(map[*Param]string)
+		}
+		q.paramVars[p] = bindVar
+	}
+	q.record(SegmentValues, bindVar, nil, start)
+	return q
+}
+
+// nextBindVar returns the next bind variable of the Dialect.
+func (q *Q) nextBindVar() string {
+	bindVar := q.d.BindVar(q, q.bindIndex)
+	q.bindIndex++
+	return bindVar
+}
+
+// writeBindVar writes bind variable bindVar and tracks it.
+func (q *Q) writeBindVar(bindVar string) {
+	q.bindVars = append(q.bindVars, q.query.Len())
+	q.query.WriteString(bindVar)
+}
+
+// writeText writes s and tracks the bind variables in it.
+func (q *Q) writeText(s string) {
+	q.trackText(q.query.Len(), s)
+	q.query.WriteString(s)
+}
+
+// trackText tracks the bind variables in s, written at offset. Only bind variables of dialects using ? are tracked,
+// ? in string literals, quoted identifiers and comments is ignored, as are the JSON operators ?| and ?& and ? followed
+// by a string literal, e.g. data ? 'key'. Bind variables of other dialects, e.g. $1, can't be told apart from other
+// text, so if s may contain one, the query's bind variables are untracked.
+func (q *Q) trackText(offset int, s string) {
+	if q.untracked {
+		return
+	}
+	if q.d == nil {
+		q.untracked = true
+		return
+	}
+	bindVar := q.d.BindVar(q, 0)
+	if bindVar != "?" {
+		if bindVar == "" || strings.IndexByte(s, bindVar[0]) >= 0 {
+			q.untracked = true
+		}
+		return
+	}
+	escapes := Supports(q.d, FeatureBackslashEscapes)
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case (c == '\'' || c == '"') && escapes:
+			i = skipEscaped(s, i)
+		case c == '\'' || c == '"' || c == '`':
+			i = skipQuoted(s, i)
+		case c == '-' && i+1 < len(s) && s[i+1] == '-':
+			i = indexFrom(s, "\n", i)
+		case c == '/' && i+1 < len(s) && s[i+1] == '*':
+			i = indexFrom(s, "*/", i+2) + 2
+		case c == '?' && i+1 < len(s) && (s[i+1] == '|' || s[i+1] == '&'):
+			i += 2
+		default:
+			if c == '?' && !isJSONOperator(s, i) {
+				q.bindVars = append(q.bindVars, offset+i)
+			}
+			i++
+		}
+	}
+}
+
+// skipEscaped is skipQuoted for string literals in which a backslash escapes the next character.
+func skipEscaped(s string, i int) int {
+	quote := s[i]
+	for j := i + 1; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case quote:
+			if j+1 < len(s) && s[j+1] == quote {
+				j++
+				continue
+			}
+			return j + 1
+		}
+	}
+	return len(s)
+}
+
+// isJSONOperator returns true if the ? at s[i] is followed by a string literal, which makes it the operator that tests
+// whether a JSON object has a key, e.g. data ? 'key', since a bind variable can't be.
+func isJSONOperator(s string, i int) bool {
+	for i++; i < len(s) && (s[i] == ' ' || s[i] == '\t' || s[i] == '\n'); i++ {
+	}
+	return i < len(s) && s[i] == '\''
+}
+
+// renumber returns s, the query of a sub-query, with its numbered bind variables shifted by n, e.g. $1 to $3 for n 2,
+// the offsets of its tracked bind variables in the result and the index of the bind variable following the highest
+// one. Bind variables in string literals, quoted identifiers and comments are ignored. s is returned as is if the
+// Dialect doesn't number its bind variables.
+func (q *Q) renumber(s string, tracked []int, n int) (string, []int, int) {
+	if q.d == nil {
+		return s, tracked, 0
+	}
+	first := q.d.BindVar(q, 0)
+	prefix := strings.TrimRight(first, "0123456789")
+	if prefix == "" || prefix == first {
+		return s, tracked, 0
+	}
+	base, _ := strconv.Atoi(first[len(prefix):])
+	if q.d.BindVar(q, 1) != prefix+strconv.Itoa(base+1) {
+		return s, tracked, 0
+	}
+
+	var (
+		b       strings.Builder
+		moved   = make([]int, 0, len(tracked))
+		next    int
+		written int // s[:written] is written to b
+	)
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			i = skipQuoted(s, i)
+			continue
+		case c == '-' && i+1 < len(s) && s[i+1] == '-':
+			i = indexFrom(s, "\n", i)
+			continue
+		case c == '/' && i+1 < len(s) && s[i+1] == '*':
+			i = indexFrom(s, "*/", i+2) + 2
+			continue
+		}
+		j := i + len(prefix)
+		if !strings.HasPrefix(s[i:], prefix) || j == len(s) || s[j] < '0' || s[j] > '9' || i > 0 && isNameByte(s[i-1]) {
+			i++
+			continue
+		}
+		for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+			j++
+		}
+		index, err := strconv.Atoi(s[i+len(prefix) : j])
+		if index -= base; err != nil || index < 0 {
+			i = j
+			continue
+		}
+		b.WriteString(s[written:i])
+		for len(moved) < len(tracked) && tracked[len(moved)] <= i {
+			// The offset of a tracked bind variable moves with the text before it.
+			moved = append(moved, tracked[len(moved)]+b.Len()-i)
+		}
+		b.WriteString(q.d.BindVar(q, index+n))
+		if index+n+1 > next {
+			next = index + n + 1
+		}
+		i, written = j, j
+	}
+	if written == 0 {
+		return s, tracked, next
+	}
+	b.WriteString(s[written:])
+	for len(moved) < len(tracked) {
+		moved = append(moved, tracked[len(moved)]+b.Len()-len(s))
+	}
+	return b.String(), moved, next
+}
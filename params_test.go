@@ -0,0 +1,115 @@
+package querier
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParamMismatch(t *testing.T) {
+	tests := []struct {
+		q           *Q
+		wantOffsets []int
+	}{
+		{New(&testExecutor{}, Default{}).Write("SELECT * FROM user WHERE id = ? AND name = ?", 1), []int{30, 43}},
+		{New(&testExecutor{}, Default{}).Write("SELECT * FROM user WHERE name = '?'", "foo"), nil},
+		{New(&testExecutor{}, numberedDialect{}).Write("UPDATE user").WriteFields("SET {name} = {bindVar}", "", Field{Name: "name"}), []int{23}},
+		{New(&testExecutor{}, Default{}).Write("WHERE id = ?", 1).Prepend("SELECT * FROM user").AddParams(2), []int{30}},
+		{New(&testExecutor{}, Default{}).WriteNamed("SELECT * FROM user WHERE id = ? AND name = :name", map[string]interface{}{"name": "foo"}), []int{30, 43}},
+	}
+	for _, tt := range tests {
+		err := tt.q.Exec()
+		if !errors.Is(err, ErrParamMismatch) {
+			t.Errorf("Exec() of %q = %v, want ErrParamMismatch", tt.q.String(), err)
+			continue
+		}
+		if offsets := err.(*ParamMismatchError).Offsets; !reflect.DeepEqual(offsets, tt.wantOffsets) {
+			t.Errorf("Exec() of %q offsets = %v, want %v", tt.q.String(), offsets, tt.wantOffsets)
+		}
+	}
+}
+
+// backslashDialect supports all features, including backslash escapes in string literals.
+type backslashDialect struct {
+	Default
+}
+
+func (backslashDialect) Supports(Feature) bool { return true }
+
+func TestParamTracking(t *testing.T) {
+	tests := []*Q{
+		New(&testExecutor{}, Default{}).Write("SELECT * FROM user WHERE name = '?' AND id = ? -- ?", 1),
+		New(&testExecutor{}, numberedDialect{}).Write("SELECT * FROM user").WriteValues("WHERE id = {bindVar}", "", 1),
+		// Literal bind variables of dialects not using ? are not tracked.
+		New(&testExecutor{}, numberedDialect{}).Write("SELECT * FROM user WHERE id = $1", 1),
+		Union(New(nil, Default{}).Write("SELECT 1 WHERE ?", true), New(nil, Default{}).Write("SELECT 2 WHERE ?", false)),
+		// Named parameters aren't bound to ?.
+		New(&testExecutor{}, Default{}).Write("SELECT * FROM user WHERE id = @id", sql.Named("id", 1)),
+		// Backslashes escape quotes in string literals if the dialect declares it, not by default.
+		New(&testExecutor{}, backslashDialect{}).Write(`SELECT * FROM user WHERE name = 'it\'s ?' AND id = ?`, 1),
+		New(&testExecutor{}, analyticsDialect{}).Write(`SELECT * FROM file WHERE dir = 'C:\' AND id = ?`, 1),
+		New(&testExecutor{}, Default{}).Write(`SELECT * FROM file WHERE path = 'C:\' AND id = ?`, 1),
+		// JSON operators aren't bind variables.
+		New(&testExecutor{}, Default{}).Write("SELECT * FROM doc WHERE data ? 'key' AND data ?| ? AND data ?& ?", "a", "b"),
+	}
+	tests[3].ex = &testExecutor{}
+	for _, q := range tests {
+		if err := q.Exec(); err != nil {
+			t.Errorf("Exec() of %q = %v", q.String(), err)
+		}
+	}
+}
+
+func TestSkipParamCheck(t *testing.T) {
+	q := New(&testExecutor{}, Default{}).Write("SELECT * FROM doc WHERE data ? name")
+	if err := q.Exec(); !errors.Is(err, ErrParamMismatch) {
+		t.Errorf("Exec() = %v, want ErrParamMismatch", err)
+	}
+	if err := q.SetOptions(Options{SkipParamCheck: true}).Exec(); err != nil {
+		t.Errorf("Exec() with SkipParamCheck = %v", err)
+	}
+}
+
+func TestBindOffset(t *testing.T) {
+	q := New(nil, numberedDialect{}).
+		Write("SELECT * FROM user WHERE").
+		WriteValues("id IN ({bindVar})", FieldSep, 1, 2).
+		WriteValues("AND name = {bindVar}", "", "foo").
+		WriteNamed("AND email = :email", map[string]interface{}{"email": "foo@example.com"})
+	if want := "SELECT * FROM user WHERE id IN ($1), id IN ($2) AND name = $3 AND email = $4"; q.String() != want {
+		t.Errorf("String() = %q, want %q", q.String(), want)
+	}
+
+	q.Reset().Write("SELECT * FROM user WHERE tenant = $1", 1).BindOffset(1).WriteValues("AND id = {bindVar}", "", 2)
+	if want := "SELECT * FROM user WHERE tenant = $1 AND id = $2"; q.String() != want {
+		t.Errorf("String() = %q, want %q", q.String(), want)
+	}
+
+	sub := New(nil, numberedDialect{}).WriteValues("SELECT id FROM admin WHERE active = {bindVar}", "", true)
+	q.Reset().InsertFromSelect("archive", nil, sub).WriteValues("AND id > {bindVar}", "", 10)
+	if want := "INSERT INTO archive SELECT id FROM admin WHERE active = $1 AND id > $2"; q.String() != want {
+		t.Errorf("String() = %q, want %q", q.String(), want)
+	}
+}
+
+func TestWriteParam(t *testing.T) {
+	var ex testExecutor
+	q := New(&ex, Default{})
+	term := NewParam("%foo%")
+	err := q.Write("SELECT * FROM user WHERE name LIKE").WriteParam(term).Write("OR email LIKE").WriteParam(term).Exec()
+	want := []interface{}{"SELECT * FROM user WHERE name LIKE ? OR email LIKE ?", "%foo%", "%foo%"}
+	if err != nil || !reflect.DeepEqual(ex.queries[0], want) {
+		t.Errorf("Exec() = %v, executed %v, want %v", err, ex.queries[0], want)
+	}
+
+	q = New(&ex, numberedDialect{})
+	term = NewParam("%foo%")
+	err = q.Write("SELECT * FROM user WHERE name LIKE").WriteParam(term).
+		Write("OR email LIKE").WriteParam(term).
+		WriteValues("AND id = {bindVar}", "", 1).Exec()
+	want = []interface{}{"SELECT * FROM user WHERE name LIKE $1 OR email LIKE $1 AND id = $2", "%foo%", 1}
+	if err != nil || !reflect.DeepEqual(ex.queries[1], want) {
+		t.Errorf("Exec() = %v, executed %v, want %v", err, ex.queries[1], want)
+	}
+}
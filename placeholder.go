@@ -0,0 +1,67 @@
+package querier
+
+import (
+	"strings"
+	"sync"
+)
+
+// PlaceholderFunc returns the text of a custom placeholder in a format of WriteFields, WriteValues or WriteValueMap.
+// field is the field being formatted, it's zero for WriteValues. arg is the text after the colon of the placeholder,
+// e.g. "t" for {qualified:t}.
+type PlaceholderFunc func(q *Q, field Field, arg string) string
+
+var (
+	placeholdersMu sync.RWMutex
+	placeholders   = make(map[string]PlaceholderFunc)
+)
+
+// RegisterPlaceholder registers placeholder {name} or {name:arg} for formats, e.g.
+//
+//	querier.RegisterPlaceholder("qualified", func(q *querier.Q, field querier.Field, arg string) string {
+//		return arg + "." + field.Name
+//	})
+//	q.WriteFields("{qualified:u} AS {name}", querier.FieldSep, fields...)
+//
+// Panics when name is already registered or is a built-in placeholder.
+func RegisterPlaceholder(name string, fn PlaceholderFunc) {
+	placeholdersMu.Lock()
+	defer placeholdersMu.Unlock()
+	if _, ok := builtinPlaceholders[name]; ok {
+		panic("placeholder {" + name + "} is built-in")
+	}
+	if _, ok := placeholders[name]; ok {
+		panic("placeholder {" + name + "} already registered")
+	}
+	placeholders[name] = fn
+}
+
+// builtinPlaceholders maps the names of the built-in placeholders to their segment kind.
+var builtinPlaceholders = map[string]int{
+	"name":       segmentName,
+	"quotedName": segmentQuotedName,
+	"dataType":   segmentDataType,
+	"bindVar":    segmentBindVar,
+}
+
+// parsePlaceholder returns the segment of the placeholder at the start of s and its length, or false if s doesn't
+// start with a known placeholder.
+func parsePlaceholder(s string) (seg formatSegment, n int, ok bool) {
+	end := strings.IndexByte(s, '}')
+	if end < 0 {
+		return seg, 0, false
+	}
+	name, arg := s[1:end], ""
+	if kind, ok := builtinPlaceholders[name]; ok {
+		return formatSegment{kind: kind}, end + 1, true
+	}
+	if i := strings.IndexByte(name, ':'); i >= 0 {
+		name, arg = name[:i], name[i+1:]
+	}
+	placeholdersMu.RLock()
+	fn := placeholders[name]
+	placeholdersMu.RUnlock()
+	if fn == nil {
+		return seg, 0, false
+	}
+	return formatSegment{kind: segmentCustom, text: arg, fn: fn}, end + 1, true
+}
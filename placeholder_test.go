@@ -0,0 +1,38 @@
+package querier
+
+import "testing"
+
+func init() {
+	RegisterPlaceholder("qualified", func(_ *Q, field Field, arg string) string {
+		return arg + "." + field.Name
+	})
+	RegisterPlaceholder("cast", func(_ *Q, _ Field, arg string) string {
+		return "::" + arg
+	})
+}
+
+func TestCustomPlaceholder(t *testing.T) {
+	q := New(nil, Default{}).
+		Write("SELECT").
+		WriteFields("{qualified:u} AS {name}", FieldSep, Field{Name: "id"}, Field{Name: "name"}).
+		Write("FROM user u WHERE").
+		WriteValues("u.created_at > {bindVar}{cast:timestamp}", "", "2020-01-01").
+		WriteFields("{unknown}", "", Field{Name: "x"})
+	want := "SELECT u.id AS id, u.name AS name FROM user u WHERE u.created_at > ?::timestamp {unknown}"
+	if q.String() != want {
+		t.Errorf("String() = %q, want %q", q.String(), want)
+	}
+}
+
+func TestRegisterPlaceholderPanics(t *testing.T) {
+	for _, name := range []string{"name", "qualified"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RegisterPlaceholder(%q) didn't panic", name)
+				}
+			}()
+			RegisterPlaceholder(name, func(*Q, Field, string) string { return "" })
+		}()
+	}
+}
@@ -0,0 +1,50 @@
+package querier
+
+import "sync"
+
+var (
+	scanSlicePool = sync.Pool{
+		New: func() interface{} {
+			s := make([]interface{}, 0, 16)
+			return &s
+		},
+	}
+	querierPool = sync.Pool{
+		New: func() interface{} {
+			return new(Q)
+		},
+	}
+)
+
+// getScanSlice returns an empty slice for scan destinations from the pool.
+func getScanSlice() *[]interface{} {
+	s := scanSlicePool.Get().(*[]interface{})
+	*s = (*s)[:0]
+	return s
+}
+
+// putScanSlice returns s to the pool. Its destinations are cleared, so they can be garbage collected.
+func putScanSlice(s *[]interface{}) {
+	for i := range *s {
+		(*s)[i] = nil
+	}
+	scanSlicePool.Put(s)
+}
+
+// AcquireQuerier returns a new Querier like New, reusing a released Querier and its buffers if one is available. The
+// Querier is fully cleared, nothing of its previous use is kept. It should be released with Release when it's no longer
+// used.
+func (db *DB) AcquireQuerier() *Q {
+	q := querierPool.Get().(*Q).reinit(db, db.d)
+	q.logger, q.columnMatching, q.schema = db.logger, db.columnMatching, db.schema
+	q.SetOptions(db.opts)
+	if db.ctx != nil {
+		q.WithContext(db.ctx)
+	}
+	return q
+}
+
+// Release releases q, acquired by AcquireQuerier, for reuse. q must not be used after it has been released.
+func (db *DB) Release(q *Q) {
+	querierPool.Put(q.reinit(nil, nil))
+}
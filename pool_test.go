@@ -0,0 +1,40 @@
+package querier
+
+import (
+	"database/sql/driver"
+	"testing"
+)
+
+func TestAcquireQuerier(t *testing.T) {
+	db := NewDB(openTestDB(t, func(string, []driver.Value) (*testRows, error) {
+		return nil, nil
+	}), Default{})
+	defer db.Close()
+
+	for i := 0; i < 3; i++ {
+		q := db.AcquireQuerier()
+		if q.String() != "" || len(q.Params()) != 0 || q.Error() != nil {
+			t.Fatalf("acquired querier is not reset: %q %v %v", q.String(), q.Params(), q.Error())
+		}
+		if err := q.Write("DELETE FROM user WHERE id = ?", i).Exec(); err != nil {
+			t.Fatal(err)
+		}
+		db.Release(q)
+	}
+}
+
+func BenchmarkAcquireInsertStruct(b *testing.B) {
+	db := NewDB(nil, Default{})
+	ex := new(testExecutor)
+	m := benchModel{ID: 1, Name: "foo", Email: "foo@example.com", Age: 42, Active: true}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ex.queries = ex.queries[:0]
+		q := db.AcquireQuerier()
+		q.ex = ex
+		if err := q.InsertStruct("user", &m).Exec(); err != nil {
+			b.Fatal(err)
+		}
+		db.Release(q)
+	}
+}
@@ -0,0 +1,107 @@
+package querier
+
+import (
+	"context"
+	"database/sql"
+)
+
+// PreparedQuery is a query prepared as a statement by the database, see DB.PrepareQuery. Its SQL is fixed, only its
+// parameters vary between executions. It's safe for concurrent use.
+type PreparedQuery struct {
+	db    *DB
+	stmt  *sql.Stmt
+	query string
+}
+
+// PrepareQuery prepares the query of q as a statement. The parameters of q are ignored, every execution passes its own.
+// Prepared statements skip the parsing and planning of the query on every execution, which helps hot queries. Warm
+// prepares the statement on more connections ahead of time.
+func (db *DB) PrepareQuery(ctx context.Context, q *Q) (*PreparedQuery, error) {
+	if q.query.Len() == 0 {
+		panic(errEmptyQuery)
+	}
+	if q.buildErr != nil {
+		return nil, q.buildErr
+	}
+	query := q.statement(ctx)
+	stmt, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &PreparedQuery{db: db, stmt: stmt, query: query}, nil
+}
+
+// String returns the SQL of the prepared query.
+func (p *PreparedQuery) String() string {
+	return p.query
+}
+
+// Q returns a Querier of the prepared query with params, which is executed by the prepared statement. It must only be
+// executed, not written to.
+func (p *PreparedQuery) Q(params ...interface{}) *Q {
+	q := p.db.New()
+	q.ex = stmtExecutor{p}
+	return q.Write(p.query, params...)
+}
+
+// Exec executes the prepared query with params.
+func (p *PreparedQuery) Exec(ctx context.Context, params ...interface{}) error {
+	return p.Q(params...).ExecContext(ctx)
+}
+
+// First scans the first row of the prepared query with params into struct dest, see Q.First.
+func (p *PreparedQuery) First(ctx context.Context, dest interface{}, params ...interface{}) error {
+	return p.Q(params...).FirstContext(ctx, dest)
+}
+
+// Find scans the rows of the prepared query with params into slice dest, see Q.Find.
+func (p *PreparedQuery) Find(ctx context.Context, dest interface{}, params ...interface{}) error {
+	return p.Q(params...).FindContext(ctx, dest)
+}
+
+// Warm prepares the statement on n connections of the pool, so the first executions on them don't have to, e.g. at
+// startup to avoid latency spikes of the first requests. n is limited to the maximum number of open connections.
+func (p *PreparedQuery) Warm(ctx context.Context, n int) error {
+	if maxOpen := p.db.Stats().MaxOpenConnections; maxOpen > 0 && n > maxOpen {
+		n = maxOpen
+	}
+	txs := make([]*sql.Tx, 0, n)
+	defer func() {
+		for _, tx := range txs {
+			tx.Rollback()
+		}
+	}()
+	for i := 0; i < n; i++ {
+		// Every transaction holds another connection. Tx.StmtContext prepares the statement on it, where it stays
+		// prepared after the transaction has ended.
+		tx, err := p.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		txs = append(txs, tx)
+		tx.StmtContext(ctx, p.stmt)
+	}
+	return nil
+}
+
+// Close closes the prepared statement.
+func (p *PreparedQuery) Close() error {
+	return p.stmt.Close()
+}
+
+// stmtExecutor executes a prepared query, the query passed to its methods is that of the statement.
+type stmtExecutor struct {
+	p *PreparedQuery
+}
+
+func (e stmtExecutor) ExecContext(ctx context.Context, _ string, args ...interface{}) (sql.Result, error) {
+	return e.p.stmt.ExecContext(ctx, args...)
+}
+
+func (e stmtExecutor) QueryContext(ctx context.Context, _ string, args ...interface{}) (*sql.Rows, error) {
+	return e.p.stmt.QueryContext(ctx, args...)
+}
+
+func (e stmtExecutor) track() (func(), error) {
+	return e.p.db.track()
+}
@@ -0,0 +1,60 @@
+package querier
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestPrepareQuery(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		prepares int
+		args     []driver.Value
+	)
+	sqlDB := openTestDB(t, func(query string, a []driver.Value) (*testRows, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if strings.HasPrefix(query, "PREPARE ") {
+			prepares++
+			return nil, nil
+		}
+		args = a
+		return &testRows{columns: []string{"ID", "Name"}, values: [][]driver.Value{{a[0], "foo"}}}, nil
+	})
+	db := NewDB(sqlDB, Default{})
+	defer db.Close()
+	ctx := context.Background()
+
+	p, err := db.PrepareQuery(ctx, db.New().Write("SELECT * FROM user WHERE id = ?", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+	if want := "SELECT * FROM user WHERE id = ?"; p.String() != want {
+		t.Errorf("String() = %q, want %q", p.String(), want)
+	}
+
+	var u struct {
+		ID   int
+		Name string
+	}
+	if err = p.First(ctx, &u, 42); err != nil {
+		t.Fatal(err)
+	}
+	if u.ID != 42 || u.Name != "foo" || len(args) != 1 || args[0] != int64(42) {
+		t.Errorf("First() = %+v with args %v", u, args)
+	}
+	if err = p.Exec(ctx, 1, 2); err == nil {
+		t.Error("Exec() with too many params succeeded")
+	}
+
+	if err = p.Warm(ctx, 3); err != nil {
+		t.Fatal(err)
+	}
+	if prepares != 3 {
+		t.Errorf("prepared %d times after Warm(3), want 3", prepares)
+	}
+}
@@ -1,4 +1,18 @@
 // Package querier provides a simple SQL query builder and executor.
+//
+// Struct fields are mapped using the "db" field tag, of the form `db:"name,dataType,option,..."`. A name of "-" ignores
+// the field, unexported fields are ignored as well, except embedded structs whose exported fields are mapped. Inline
+// structs are flattened; with the inline option, e.g. `db:"addr_,,inline"`, the names of their fields are prefixed with
+// the tag's name. The squash option flattens them without a prefix, which is the default. Pointer fields, e.g. *string
+// or *time.Time, map to nullable columns; a nil pointer is bound and scanned as NULL. The enum option, e.g.
+// `db:"status,,enum=pending;active;done"`, restricts a field to a set of values. The encrypted option, e.g.
+// `db:"ssn,,encrypted"`, encrypts a field at rest using the KeyProvider set by SetKeyProvider. The sensitive option,
+// e.g. `db:"password,,sensitive"`, redacts the field's value whenever its parameter is formatted. The pk option, e.g.
+// `db:"id,,pk"`, marks the primary key fields, which identify the row in UpdateStruct and DeleteStruct. The omitempty
+// option leaves a zero field out of InsertStruct. The computed option, e.g. `db:"total_count,computed"`, marks a field
+// computed by queries, like an aggregate, which is scanned but not inserted, updated or migrated. Options may directly
+// follow the name, e.g. `db:"id,omitempty"`, so structs tagged for sqlx are mapped as expected; *sqlx.DB and *sqlx.Tx
+// can be used as Executor. Custom options can be added by RegisterTagOption.
 package querier
 
 import (
@@ -21,6 +35,8 @@ const (
 var (
 	// ErrNoRecord means that the record was not found.
 	ErrNoRecord = errors.New("no record found")
+	// ErrStopIteration can be returned by a ScanFunc to stop ForEach without an error.
+	ErrStopIteration = errors.New("stop iteration")
 
 	errEmptyQuery = errors.New("query is empty")
 )
@@ -34,25 +50,67 @@ type Executor interface {
 // DeferFunc runs when the query is finished.
 type DeferFunc func(*Q)
 
-// ScanFunc is called for each row in the result set.
+// ScanFunc is called for each row in the result set. The index of the row is returned by RowIndex of the Querier. It
+// can return ErrStopIteration to stop at the row without an error.
 type ScanFunc func(*Q, *sql.Rows) error
 
 // Q can build and execute queries.
 type Q struct {
-	ex Executor
-	d  Dialect
+	ex      Executor
+	d       Dialect
+	ctx     context.Context
+	baseCtx context.Context
 
 	// Query builder
 	query    bytes.Buffer
 	sep      string
 	preWrite string
 	params   []interface{}
+	strict   bool
+	buildErr error
+	comment  [][]string
+	hints    []string
+	segments []segment
+	// name is the name of the query, see Named.
+	name string
+	// bindVars contains the offsets of the bind variables in query. untracked is true if not all bind variables could
+	// be tracked.
+	bindVars  []int
+	untracked bool
+	// bindIndex is the index of the next bind variable returned by the Dialect, see BindOffset.
+	bindIndex      int
+	logger         Logger
+	coercion       *Coercion
+	scanWorkers    int
+	columnMatching ColumnMatching
+	// schema qualifies the table names of the builder helpers, see SetSchema.
+	schema string
+	// rowIndex is the index of the current row of ForEach.
+	rowIndex int
+	// budget is the fraction of the time left that queries get, see WithBudget. cancelBudget cancels the context of
+	// the execution with the shaved deadline.
+	budget       float64
+	cancelBudget context.CancelFunc
+	// constraints maps unique constraints to the fields of the statement, see UniqueViolation.Fields.
+	constraints map[string][]string
+	// paramVars contains the bind variables of the Params written with numbered bind variables, see WriteParam.
+	paramVars map[*Param]string
+	// opts are the defaults of the builder, see SetOptions.
+	opts Options
 
 	// For deffered functions.
 	err          error
 	lastInsertID int64
 	rowsAffected int64
 	deferred     []DeferFunc
+	// deferredErrs are the errors of the deferred functions of DeferErr.
+	deferredErrs []error
+	// beforeExec runs right before the statement executes, an error is returned without executing it.
+	beforeExec []func(*Q) error
+	// afterExec runs after the statement executed successfully, before the deferred functions. Its error is returned.
+	afterExec []func(*Q) error
+	// afterCommit is set by AfterCommit, which requires an Executor that isn't a *sql.Tx.
+	afterCommit bool
 }
 
 // New returns a new querier.
@@ -63,40 +121,75 @@ func New(ex Executor, d Dialect) *Q {
 // Write writes a string (query) to the Querier. A single space is appended after query.
 func (q *Q) Write(query string, params ...interface{}) *Q {
 	q.writeSep()
-	q.query.WriteString(query)
+	q.writeText(query)
+	start := len(q.params)
 	q.params = append(q.params, params...)
+	q.record(SegmentText, query, nil, start)
 	return q
 }
 
 // Writef writes a formatted string (format) to the Querier. A single space is appended after query.
 func (q *Q) Writef(format string, args ...interface{}) *Q {
+	if q.strict {
+		q.checkStrict(args)
+	}
 	q.writeSep()
-	q.query.WriteString(fmt.Sprintf(format, args...))
+	text := fmt.Sprintf(format, args...)
+	q.writeText(text)
+	q.record(SegmentText, text, nil, len(q.params))
 	return q
 }
 
+// WriteFields writes format for every field, separated by sep. The format's placeholders are {name}, {quotedName},
+// which is quoted by the Dialect's IdentQuoter, {dataType}, {bindVar} and the placeholders registered by
+// RegisterPlaceholder, e.g.
+//
+//	q.WriteFields("{quotedName} {dataType}", querier.FieldSep, fields...)
 func (q *Q) WriteFields(format, sep string, fields ...Field) *Q {
 	q.writeSep()
 	q.writeFormat(format, sep, fields, len(fields))
+	q.record(SegmentFields, format, fields, len(q.params))
 	return q
 }
 
 func (q *Q) WriteValues(format, sep string, values ...interface{}) *Q {
 	q.writeSep()
 	q.writeFormat(format, sep, nil, len(values))
+	start := len(q.params)
 	q.params = append(q.params, values...)
+	q.record(SegmentValues, format, nil, start)
 	return q
 }
 
 func (q *Q) WriteValueMap(format, sep string, valueMap ValueMap, fields ...Field) *Q {
 	q.writeSep()
-	q.writeFormat(format, sep, fields, len(fields))
-	q.params = append(q.params, valueMap.MapToFields(fields, nil)...)
+	q.writeValueMap(format, sep, valueMap, fields)
 	return q
 }
 
+func (q *Q) writeValueMap(format, sep string, valueMap ValueMap, fields []Field) {
+	q.writeFormat(format, sep, fields, len(fields))
+	offset := len(q.params)
+	q.params = valueMap.MapToFields(fields, q.params)
+	for i := range fields {
+		if fields[i].Sensitive {
+			q.params[offset+i] = Sensitive(q.params[offset+i])
+		}
+		if fields[i].Enum == nil {
+			continue
+		}
+		if value, ok := valueMap[fields[i].Name]; ok {
+			if err := checkEnum(&fields[i], value); err != nil {
+				q.fail(err)
+			}
+		}
+	}
+	q.record(SegmentValues, format, fields, offset)
+}
+
 func (q *Q) WriteRaw(s string) *Q {
-	q.query.WriteString(s)
+	q.writeText(s)
+	q.record(SegmentRaw, s, nil, len(q.params))
 	return q
 }
 
@@ -104,15 +197,24 @@ func (q *Q) Prepend(query string) *Q {
 	var buf bytes.Buffer
 	buf.WriteString(query)
 	buf.WriteString(q.sep)
+	shift := buf.Len()
 	q.query.WriteTo(&buf)
 	q.query = buf
+	for i := range q.bindVars {
+		q.bindVars[i] += shift
+	}
+	q.trackText(0, query)
+	q.segments = append(q.segments, segment{})
+	copy(q.segments[1:], q.segments)
+	q.segments[0] = segment{kind: SegmentText, text: query}
 	return q
 }
 
 func (q *Q) PreWrite() *Q {
 	if q.preWrite != "" {
 		q.writeSep()
-		q.query.WriteString(q.preWrite)
+		q.writeText(q.preWrite)
+		q.record(SegmentText, q.preWrite, nil, len(q.params))
 	}
 	return q
 }
@@ -132,8 +234,41 @@ func (q *Q) SetDialect(d Dialect) *Q {
 	return q
 }
 
+// SetContext sets the context of the query. It is available to the Dialect while building the query, e.g. in
+// BindVar, so dialects can make per-request decisions. Executing the query replaces it with the execution context.
+func (q *Q) SetContext(ctx context.Context) *Q {
+	q.ctx = ctx
+	return q
+}
+
+// Context returns the context of the query, see SetContext. It defaults to the base context.
+func (q *Q) Context() context.Context {
+	if q.ctx == nil {
+		return q.baseContext()
+	}
+	return q.ctx
+}
+
+// WithContext sets the base context of the query, which is used by the methods without a context parameter, e.g.
+// Exec and Find, so they take part in cancellation and tracing. It also sets the context of the query, see
+// SetContext. The base context is kept by Reset and New.
+func (q *Q) WithContext(ctx context.Context) *Q {
+	q.baseCtx, q.ctx = ctx, ctx
+	return q
+}
+
+// baseContext returns the base context, it defaults to context.Background().
+func (q *Q) baseContext() context.Context {
+	if q.baseCtx == nil {
+		return context.Background()
+	}
+	return q.baseCtx
+}
+
 func (q *Q) AddParams(params ...interface{}) *Q {
+	start := len(q.params)
 	q.params = append(q.params, params...)
+	q.record(SegmentParams, "", nil, start)
 	return q
 }
 
@@ -150,6 +285,18 @@ func (q *Q) Defer(fn DeferFunc) *Q {
 	return q
 }
 
+// DeferErr defers fn like Defer, but the error it returns, e.g. of a query it executes to clean up, isn't lost: it's
+// joined with the error of the query returned by Error, and returned by the execution with the DeferredErrors of the
+// Options.
+func (q *Q) DeferErr(fn func(*Q) error) *Q {
+	q.deferred = append(q.deferred, func(q *Q) {
+		if err := fn(q); err != nil {
+			q.deferredErrs = append(q.deferredErrs, err)
+		}
+	})
+	return q
+}
+
 func (q *Q) DeferSuccess(fn DeferFunc) *Q {
 	q.deferred = append(q.deferred, func(q *Q) {
 		if q.err == nil {
@@ -159,36 +306,74 @@ func (q *Q) DeferSuccess(fn DeferFunc) *Q {
 	return q
 }
 
-func (q *Q) ExecContext(ctx context.Context) error {
+func (q *Q) ExecContext(ctx context.Context) (err error) {
 	if q.query.Len() == 0 {
 		panic(errEmptyQuery)
 	}
-	defer q.runDeferred()
-
-	result, err := q.ex.ExecContext(ctx, q.query.String(), q.params...)
+	q.ctx = ctx
+	defer q.runDeferred(&err)
+	if err := q.checkBuild(); err != nil {
+		return q.returnErr(err)
+	}
+	done, err := q.track()
 	if err != nil {
 		return q.returnErr(err)
 	}
-	if q.rowsAffected, err = result.RowsAffected(); err != nil {
+	defer done()
+
+	for _, fn := range q.beforeExec {
+		if err = fn(q); err != nil {
+			return q.returnErr(err)
+		}
+	}
+	result, err := q.exec(ctx)
+	if err != nil {
 		return q.returnErr(err)
 	}
-	q.lastInsertID, err = result.LastInsertId()
+	if Supports(q.d, FeatureRowsAffected) {
+		if q.rowsAffected, err = result.RowsAffected(); err != nil {
+			return q.returnErr(err)
+		}
+	}
+	if Supports(q.d, FeatureLastInsertID) {
+		if q.lastInsertID, err = result.LastInsertId(); err != nil {
+			return q.returnErr(err)
+		}
+	}
+	for _, fn := range q.afterExec {
+		if err = fn(q); err != nil {
+			break
+		}
+	}
 
 	return q.returnErr(err)
 }
 
 func (q *Q) Exec() error {
-	return q.ExecContext(context.Background())
+	return q.ExecContext(q.baseContext())
 }
 
-func (q *Q) FirstContext(ctx context.Context, i interface{}) error {
+func (q *Q) FirstContext(ctx context.Context, i interface{}) (err error) {
 	if q.query.Len() == 0 {
 		panic(errEmptyQuery)
 	}
-	valueMap := Values(i)
-	defer q.runDeferred()
+	s, fast := i.(Scannable)
+	var valueMap ValueMap
+	if !fast {
+		valueMap = ScanValues(i)
+	}
+	q.ctx = ctx
+	defer q.runDeferred(&err)
+	if err := q.checkBuild(); err != nil {
+		return q.returnErr(err)
+	}
+	done, err := q.track()
+	if err != nil {
+		return q.returnErr(err)
+	}
+	defer done()
 
-	rows, err := q.ex.QueryContext(ctx, q.query.String(), q.params...)
+	rows, err := q.queryRows(ctx)
 	if err != nil {
 		return q.returnErr(err)
 	}
@@ -197,42 +382,87 @@ func (q *Q) FirstContext(ctx context.Context, i interface{}) error {
 	if !rows.Next() {
 		return q.returnErr(ErrNoRecord)
 	}
+	if fast {
+		if err = checkColumns(rows, i); err != nil {
+			return q.returnErr(err)
+		}
+		return q.returnErr(s.ScanRow(rows))
+	}
 	columns, err := rows.Columns()
 	if err != nil {
 		return q.returnErr(err)
 	}
-	err = rows.Scan(valueMap.MapToColumns(columns, nil)...)
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return q.returnErr(err)
+	}
+	columns = matchColumns(valueMap, columns, q.columnMatching)
+	if err = checkColumnNames(valueMap, columns); err != nil {
+		return q.returnErr(err)
+	}
+	fields := getScanSlice()
+	defer putScanSlice(fields)
+	*fields = valueMap.MapToColumns(columns, *fields)
+	decodeColumns(q.coercion, columnTypes, *fields)
+	err = rows.Scan(*fields...)
 	return q.returnErr(err)
 }
 
 func (q *Q) First(i interface{}) error {
-	return q.FirstContext(context.Background(), i)
+	return q.FirstContext(q.baseContext(), i)
 }
 
-func (q *Q) FindContext(ctx context.Context, i interface{}) error {
+func (q *Q) FindContext(ctx context.Context, i interface{}) (err error) {
 	if q.query.Len() == 0 {
 		panic(errEmptyQuery)
 	}
 	v, elemType, elemIsPtr := extractStructSliceInfo(i)
-	defer q.runDeferred()
+	q.ctx = ctx
+	defer q.runDeferred(&err)
+	if err := q.checkBuild(); err != nil {
+		return q.returnErr(err)
+	}
+	done, err := q.track()
+	if err != nil {
+		return q.returnErr(err)
+	}
+	defer done()
 
-	rows, err := q.ex.QueryContext(ctx, q.query.String(), q.params...)
+	rows, err := q.queryRows(ctx)
 	if err != nil {
 		return q.returnErr(err)
 	}
 	defer rows.Close()
+	if reflect.PtrTo(elemType).Implements(reflectTypeScannable) {
+		return q.returnErr(findScannable(rows, v, elemType, elemIsPtr))
+	}
 	columns, err := rows.Columns()
 	if err != nil {
 		return q.returnErr(err)
 	}
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return q.returnErr(err)
+	}
+	valueMap := makeValueMap(reflect.New(elemType).Elem(), nil, "")
+	columns = matchColumns(valueMap, columns, q.columnMatching)
+	if err = checkColumnNames(valueMap, columns); err != nil {
+		return q.returnErr(err)
+	}
 
-	var fields []interface{}
-	var valueMap ValueMap
+	if q.scanWorkers > 1 {
+		return q.returnErr(findConcurrent(ctx, rows, v, elemType, elemIsPtr, columns, columnTypes, q.coercion,
+			q.scanWorkers))
+	}
+
+	fields := getScanSlice()
+	defer putScanSlice(fields)
 	for rows.Next() {
 		element := reflect.New(elemType).Elem()
-		valueMap = makeValueMap(element, valueMap)
-		fields = valueMap.MapToColumns(columns, fields)
-		err = rows.Scan(fields...)
+		valueMap = makeValueMap(element, valueMap, "")
+		*fields = valueMap.MapToColumns(columns, (*fields)[:0])
+		decodeColumns(q.coercion, columnTypes, *fields)
+		err = rows.Scan(*fields...)
 		if err != nil {
 			return q.returnErr(err)
 		}
@@ -240,23 +470,31 @@ func (q *Q) FindContext(ctx context.Context, i interface{}) error {
 			element = element.Addr()
 		}
 		v.Set(reflect.Append(v, element))
-		fields = fields[:0]
 	}
 
-	return nil
+	return q.returnErr(rows.Err())
 }
 
 func (q *Q) Find(i interface{}) error {
-	return q.FindContext(context.Background(), i)
+	return q.FindContext(q.baseContext(), i)
 }
 
-func (q *Q) ScanContext(ctx context.Context, dest ...interface{}) error {
+func (q *Q) ScanContext(ctx context.Context, dest ...interface{}) (err error) {
 	if q.query.Len() == 0 {
 		panic(errEmptyQuery)
 	}
-	defer q.runDeferred()
+	q.ctx = ctx
+	defer q.runDeferred(&err)
+	if err := q.checkBuild(); err != nil {
+		return q.returnErr(err)
+	}
+	done, err := q.track()
+	if err != nil {
+		return q.returnErr(err)
+	}
+	defer done()
 
-	rows, err := q.ex.QueryContext(ctx, q.query.String(), q.params...)
+	rows, err := q.queryRows(ctx)
 	if err != nil {
 		return q.returnErr(err)
 	}
@@ -270,32 +508,48 @@ func (q *Q) ScanContext(ctx context.Context, dest ...interface{}) error {
 }
 
 func (q *Q) Scan(dest ...interface{}) error {
-	return q.ScanContext(context.Background(), dest...)
+	return q.ScanContext(q.baseContext(), dest...)
 }
 
-func (q *Q) ForEachContext(ctx context.Context, fn ScanFunc) error {
+func (q *Q) ForEachContext(ctx context.Context, fn ScanFunc) (err error) {
 	if q.query.Len() == 0 {
 		panic(errEmptyQuery)
 	}
-	defer q.runDeferred()
+	q.ctx = ctx
+	defer q.runDeferred(&err)
+	if err := q.checkBuild(); err != nil {
+		return q.returnErr(err)
+	}
+	done, err := q.track()
+	if err != nil {
+		return q.returnErr(err)
+	}
+	defer done()
 
-	rows, err := q.ex.QueryContext(ctx, q.query.String(), q.params...)
+	rows, err := q.queryRows(ctx)
 	if err != nil {
 		return q.returnErr(err)
 	}
 	defer rows.Close()
 
-	for rows.Next() {
-		if err = fn(q, rows); err != nil {
+	for q.rowIndex = 0; rows.Next(); q.rowIndex++ {
+		if err = fn(q, rows); errors.Is(err, ErrStopIteration) {
+			return q.returnErr(nil)
+		} else if err != nil {
 			return q.returnErr(err)
 		}
 	}
 
-	return nil
+	return q.returnErr(rows.Err())
+}
+
+// RowIndex returns the index of the current row in a ScanFunc of ForEach, starting at 0.
+func (q *Q) RowIndex() int {
+	return q.rowIndex
 }
 
 func (q *Q) ForEach(fn ScanFunc) error {
-	return q.ForEachContext(context.Background(), fn)
+	return q.ForEachContext(q.baseContext(), fn)
 }
 
 func (q *Q) RowsAffected() int64 {
@@ -306,12 +560,17 @@ func (q *Q) LastInsertID() int64 {
 	return q.lastInsertID
 }
 
+// Error returns the error of the last execution, joined with the errors of its deferred functions, see DeferErr.
 func (q *Q) Error() error {
 	return q.err
 }
 
 func (q *Q) New() *Q {
-	return New(q.ex, q.d)
+	n := New(q.ex, q.d).SetContext(q.ctx).SetCoercion(q.coercion).SetLogger(q.logger)
+	n.baseCtx, n.scanWorkers, n.columnMatching, n.schema = q.baseCtx, q.scanWorkers, q.columnMatching, q.schema
+	n.budget = q.budget
+	n.SetOptions(q.opts)
+	return n
 }
 
 func (q *Q) Clone() *Q {
@@ -320,20 +579,58 @@ func (q *Q) Clone() *Q {
 	return clone
 }
 
+// Reset clears the query, its parameters, results and deferred functions, so q can build and execute another query.
+// The configuration of q is kept: its Executor, Dialect, contexts, pre-write, strict mode, logger, coercion, scan
+// workers, column matching, schema, budget and Options. The separator is reset to the one of the Options.
+// ResetKeepDialect and ResetAll clear the configuration as well.
 func (q *Q) Reset() *Q {
 	q.query.Reset()
-	if q.params != nil {
-		q.params = q.params[:0]
-	}
-	q.sep = Space
+	q.params = clearParams(q.params)
+	q.sep = q.opts.separator()
+	q.buildErr = nil
+	q.bindVars, q.untracked, q.bindIndex = q.bindVars[:0], false, 0
+	q.segments = q.segments[:0]
+	q.comment, q.hints, q.name = nil, nil, ""
+	q.constraints, q.paramVars = nil, nil
 	q.err = nil
 	q.lastInsertID, q.rowsAffected = 0, 0
-	if q.deferred != nil {
-		q.deferred = q.deferred[:0]
+	for i := range q.deferred {
+		// Don't keep the closures, and what they reference, alive.
+		q.deferred[i] = nil
 	}
+	q.deferred = q.deferred[:0]
+	q.deferredErrs = nil
+	q.beforeExec, q.afterExec, q.afterCommit = nil, nil, false
 	return q
 }
 
+// ResetKeepDialect resets q to the state of New with its Executor and Dialect, clearing the configuration that Reset
+// keeps, e.g. the pre-write and context. The buffers of q are reused.
+func (q *Q) ResetKeepDialect() *Q {
+	return q.reinit(q.ex, q.d)
+}
+
+// ResetAll resets q to the state of New with its Executor and the Default Dialect, see ResetKeepDialect.
+func (q *Q) ResetAll() *Q {
+	return q.reinit(q.ex, Default{})
+}
+
+// reinit resets q to the state of New(ex, d), reusing its buffers.
+func (q *Q) reinit(ex Executor, d Dialect) *Q {
+	query, params, bindVars := q.query, clearParams(q.params), q.bindVars[:0]
+	query.Reset()
+	*q = Q{ex: ex, d: d, sep: Space, query: query, params: params, bindVars: bindVars}
+	return q
+}
+
+// clearParams returns params truncated, its parameters are cleared so they can be garbage collected.
+func clearParams(params []interface{}) []interface{} {
+	for i := range params {
+		params[i] = nil
+	}
+	return params[:0]
+}
+
 // Fields does the same thing as Fields() but it also sets the Dialect.
 func (q *Q) Fields(i interface{}) *FieldSelector {
 	return Fields(i).SetDialect(q.d)
@@ -345,57 +642,123 @@ func (q *Q) writeSep() {
 	}
 }
 
+// fail records an error that occurred while building the query. It is returned when the query is executed, only the
+// first error is kept.
+func (q *Q) fail(err error) {
+	if q.buildErr == nil {
+		q.buildErr = err
+	}
+}
+
 func (q *Q) returnErr(err error) error {
 	q.err = err
 	return err
 }
 
-func (q *Q) runDeferred() {
+// runDeferred runs the deferred functions of the execution that returns err. Their errors are joined with the error of
+// the query, see DeferErr.
+func (q *Q) runDeferred(err *error) {
+	if q.cancelBudget != nil {
+		q.cancelBudget()
+		q.cancelBudget = nil
+	}
 	for _, fn := range q.deferred {
 		fn(q)
 	}
+	if len(q.deferredErrs) == 0 {
+		return
+	}
+	q.err = joinErrors(append([]error{q.err}, q.deferredErrs...))
+	if q.opts.DeferredErrors {
+		*err = q.err
+	}
 }
 
-const (
-	phName     = "{name}"
-	phDataType = "{dataType}"
-	phBindVar  = "{bindVar}"
-)
-
 func (q *Q) writeFormat(format, sep string, fields []Field, n int) {
 	if n < 1 {
 		return
 	}
 
-	var (
-		hasName     = strings.Contains(format, phName)
-		hasDataType = strings.Contains(format, phDataType)
-		hasBindVar  = strings.Contains(format, phBindVar)
-	)
-
-	if fields == nil && (hasName || hasDataType) {
-		panic("format contains placeholder {name} or {dataType}, this is not allowed when only formatting values")
+	// Compile the format once, instead of replacing its placeholders for every field.
+	var buf [8]formatSegment
+	segments := compileFormat(buf[:0], format)
+	if fields == nil {
+		for _, seg := range segments {
+			if seg.kind == segmentName || seg.kind == segmentQuotedName || seg.kind == segmentDataType {
+				panic("format contains placeholder {name}, {quotedName} or {dataType}, this is not allowed when only formatting values")
+			}
+		}
 	}
 
-	fmtr := func(i int, f *Field) {
-		part := format
-		if hasName {
-			part = strings.Replace(part, phName, f.Name, -1)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			q.query.WriteString(sep)
 		}
-		if hasDataType {
-			part = strings.Replace(part, phDataType, f.DataType, -1)
+		var bindVar string
+		for _, seg := range segments {
+			switch seg.kind {
+			case segmentText:
+				q.query.WriteString(seg.text)
+			case segmentName:
+				q.query.WriteString(fields[i].Name)
+			case segmentQuotedName:
+				q.query.WriteString(q.quoteIdent(fields[i].Name))
+			case segmentDataType:
+				q.query.WriteString(fields[i].DataType)
+			case segmentBindVar:
+				if bindVar == "" {
+					bindVar = q.nextBindVar()
+				}
+				q.writeBindVar(bindVar)
+			case segmentCustom:
+				var field Field
+				if fields != nil {
+					field = fields[i]
+				}
+				q.writeText(seg.fn(q, field, seg.text))
+			}
 		}
-		if hasBindVar {
-			part = strings.Replace(part, phBindVar, q.d.BindVar(q, i), -1)
-		}
-		q.query.WriteString(part)
 	}
+}
+
+const (
+	segmentText = iota
+	segmentName
+	segmentQuotedName
+	segmentDataType
+	segmentBindVar
+	segmentCustom
+)
+
+// formatSegment is a segment of a compiled format, either text or a placeholder. The text of a custom placeholder is
+// its argument.
+type formatSegment struct {
+	kind int
+	text string
+	fn   PlaceholderFunc
+}
 
-	fmtr(0, &fields[0])
-	for i := 1; i < n; i++ {
-		q.query.WriteString(sep)
-		fmtr(i, &fields[i])
+// compileFormat appends the segments of format to segments.
+func compileFormat(segments []formatSegment, format string) []formatSegment {
+	for len(format) > 0 {
+		i := strings.IndexByte(format, '{')
+		if i < 0 {
+			return append(segments, formatSegment{kind: segmentText, text: format})
+		}
+		seg, n, ok := parsePlaceholder(format[i:])
+		if !ok {
+			// Not a placeholder, keep the brace as text.
+			segments = append(segments, formatSegment{kind: segmentText, text: format[:i+1]})
+			format = format[i+1:]
+			continue
+		}
+		if i > 0 {
+			segments = append(segments, formatSegment{kind: segmentText, text: format[:i]})
+		}
+		segments = append(segments, seg)
+		format = format[i+n:]
 	}
+	return segments
 }
 
 func extractStructSliceInfo(i interface{}) (v reflect.Value, elemType reflect.Type, elemIsPtr bool) {
@@ -0,0 +1,215 @@
+package querier
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+var errTestQuery = errors.New("test executor does not support queries")
+
+// testExecutor records executed statements.
+type testExecutor struct {
+	queries [][]interface{}
+}
+
+type testResult struct {
+	n int64
+}
+
+func (r testResult) LastInsertId() (int64, error) { return r.n, nil }
+func (r testResult) RowsAffected() (int64, error) { return r.n, nil }
+
+func (e *testExecutor) ExecContext(_ context.Context, query string, params ...interface{}) (sql.Result, error) {
+	e.queries = append(e.queries, append([]interface{}{query}, params...))
+	return testResult{1}, nil
+}
+
+func (e *testExecutor) QueryContext(_ context.Context, query string, params ...interface{}) (*sql.Rows, error) {
+	e.queries = append(e.queries, append([]interface{}{query}, params...))
+	return nil, errTestQuery
+}
+
+func TestExec(t *testing.T) {
+	var ex testExecutor
+	q := New(&ex, Default{})
+
+	var deferred bool
+	err := q.Write("UPDATE user SET name = ?", "foo").
+		Write("WHERE id = ?", 1).
+		DeferSuccess(func(*Q) { deferred = true }).
+		Exec()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]interface{}{{"UPDATE user SET name = ? WHERE id = ?", "foo", 1}}
+	if !reflect.DeepEqual(ex.queries, want) {
+		t.Errorf("executed %v, want %v", ex.queries, want)
+	}
+	if q.RowsAffected() != 1 || q.LastInsertID() != 1 {
+		t.Errorf("RowsAffected() = %d, LastInsertID() = %d, want 1, 1", q.RowsAffected(), q.LastInsertID())
+	}
+	if !deferred {
+		t.Error("DeferSuccess() function was not called")
+	}
+}
+
+type ctxKey struct{}
+
+// ctxDialect uses a bind variable from the context.
+type ctxDialect struct {
+	Default
+}
+
+func (ctxDialect) BindVar(q *Q, _ int) string {
+	if bindVar, ok := q.Context().Value(ctxKey{}).(string); ok {
+		return bindVar
+	}
+	return "?"
+}
+
+func TestContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), ctxKey{}, "@p")
+	q := New(&testExecutor{}, ctxDialect{})
+	if q.Context() != context.Background() {
+		t.Error("Context() is not the background context by default")
+	}
+
+	q.SetContext(ctx).WriteValues("{bindVar}", FieldSep, 1)
+	if q.String() != "@p" {
+		t.Errorf("String() = %q, want %q", q.String(), "@p")
+	}
+	if q.New().Context() != ctx {
+		t.Error("New() did not keep the context")
+	}
+
+	execCtx := context.WithValue(ctx, ctxKey{}, "exec")
+	var deferredCtx context.Context
+	q.Defer(func(q *Q) { deferredCtx = q.Context() }).ExecContext(execCtx)
+	if deferredCtx != execCtx {
+		t.Error("deferred function did not get the execution context")
+	}
+}
+
+func TestWithContext(t *testing.T) {
+	base := context.WithValue(context.Background(), ctxKey{}, "base")
+	var execCtx context.Context
+	q := New(&testExecutor{}, Default{}).WithContext(base).Defer(func(q *Q) { execCtx = q.Context() })
+	if err := q.Write("DELETE FROM user").Exec(); err != nil {
+		t.Fatal(err)
+	}
+	if execCtx != base {
+		t.Error("Exec() did not use the base context")
+	}
+
+	other := context.WithValue(context.Background(), ctxKey{}, "other")
+	q.Reset().Write("DELETE FROM user").ExecContext(other)
+	q.Reset().Defer(func(q *Q) { execCtx = q.Context() }).Write("DELETE FROM user").Exec()
+	if execCtx != base {
+		t.Error("Exec() after ExecContext() did not use the base context")
+	}
+	if q.New().baseContext() != base {
+		t.Error("New() did not keep the base context")
+	}
+}
+
+func TestReset(t *testing.T) {
+	ex := &testExecutor{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "base")
+	deferred := 0
+	q := New(ex, numberedDialect{}).WithContext(ctx).SetPreWrite("/* app */").SetStrict(true).
+		Defer(func(*Q) { deferred++ }).Write("SELECT ?", 1)
+	q.fail(errors.New("failed"))
+
+	q.Reset()
+	if q.String() != "" || len(q.Params()) != 0 || q.buildErr != nil || len(q.deferred) != 0 {
+		t.Errorf("Reset() kept the query: %q %v %v %d", q.String(), q.Params(), q.buildErr, len(q.deferred))
+	}
+	if q.preWrite != "/* app */" || !q.strict || q.baseCtx != ctx || q.d != (numberedDialect{}) {
+		t.Error("Reset() cleared the configuration")
+	}
+	if err := q.Write("DELETE FROM user").Exec(); err != nil || deferred != 0 {
+		t.Errorf("Exec() after Reset() = %v, ran %d deferred functions", err, deferred)
+	}
+
+	q.Write("SELECT ?", 1).ResetKeepDialect()
+	if q.String() != "" || q.preWrite != "" || q.strict || q.baseCtx != nil || q.ex != ex || q.d != (numberedDialect{}) {
+		t.Error("ResetKeepDialect() didn't reset q to the state of New")
+	}
+	q.SetPreWrite("/* app */").ResetAll()
+	if q.preWrite != "" || q.ex != ex || q.d != (Default{}) {
+		t.Error("ResetAll() didn't reset q to the state of New with the Default Dialect")
+	}
+}
+
+func TestForEachStop(t *testing.T) {
+	db := openTestDB(t, func(string, []driver.Value) (*testRows, error) {
+		return &testRows{columns: []string{"id"}, values: [][]driver.Value{{int64(1)}, {int64(2)}, {int64(3)}}}, nil
+	})
+	defer db.Close()
+
+	var indexes []int
+	deferred := false
+	err := New(db, Default{}).Write("SELECT id FROM user").Defer(func(*Q) { deferred = true }).
+		ForEach(func(q *Q, rows *sql.Rows) error {
+			indexes = append(indexes, q.RowIndex())
+			if q.RowIndex() == 1 {
+				return ErrStopIteration
+			}
+			return nil
+		})
+	if err != nil {
+		t.Errorf("ForEach() stopped with ErrStopIteration = %v", err)
+	}
+	if !reflect.DeepEqual(indexes, []int{0, 1}) || !deferred {
+		t.Errorf("ForEach() visited rows %v, ran deferred functions: %v", indexes, deferred)
+	}
+	// A wrapped ErrStopIteration stops as well.
+	indexes = nil
+	err = New(db, Default{}).Write("SELECT id FROM user").ForEach(func(q *Q, rows *sql.Rows) error {
+		indexes = append(indexes, q.RowIndex())
+		return fmt.Errorf("done: %w", ErrStopIteration)
+	})
+	if err != nil || !reflect.DeepEqual(indexes, []int{0}) {
+		t.Errorf("ForEach() stopped with a wrapped ErrStopIteration = %v, visited rows %v", err, indexes)
+	}
+
+	// Stopping clears the error of the previous execution.
+	errRow := errors.New("row failed")
+	q := New(db, Default{}).Write("SELECT id FROM user")
+	q.ForEach(func(*Q, *sql.Rows) error { return errRow })
+	q.ForEach(func(*Q, *sql.Rows) error { return ErrStopIteration })
+	if err := q.Error(); err != nil {
+		t.Errorf("Error() after stopping = %v, want nil", err)
+	}
+}
+
+func TestDeferErr(t *testing.T) {
+	var ex testExecutor
+	errCleanup := errors.New("cleanup failed")
+	q := New(&ex, Default{}).Write("DELETE FROM user").DeferErr(func(*Q) error { return errCleanup })
+	if err := q.Exec(); err != nil {
+		t.Errorf("Exec() = %v, want nil without DeferredErrors", err)
+	}
+	if err := q.Error(); !errors.Is(err, errCleanup) {
+		t.Errorf("Error() = %v, want %v", err, errCleanup)
+	}
+
+	err := New(&ex, Default{}).SetOptions(Options{DeferredErrors: true}).
+		Write("SELECT * FROM user").
+		DeferErr(func(*Q) error { return errCleanup }).
+		DeferErr(func(*Q) error { return nil }).
+		Find(&[]struct{}{})
+	if !errors.Is(err, errTestQuery) || !errors.Is(err, errCleanup) {
+		t.Errorf("Find() = %v, want the errors of the query and the deferred function", err)
+	}
+
+	if err = q.Reset().Write("DELETE FROM user").Exec(); err != nil || q.Error() != nil {
+		t.Errorf("Exec() after Reset = %v, Error() = %v, want nil", err, q.Error())
+	}
+}
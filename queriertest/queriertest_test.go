@@ -0,0 +1,114 @@
+package queriertest
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/semrekkers/querier"
+	"github.com/semrekkers/querier/internal/testdriver"
+)
+
+// testDialect has no tables.
+type testDialect struct {
+	querier.Default
+}
+
+func (testDialect) HasTable(*querier.Q, string) (bool, error)         { return false, nil }
+func (testDialect) TableColumns(*querier.Q, string) ([]string, error) { return nil, nil }
+
+type user struct {
+	ID   int64  `db:"id"`
+	Name string `db:"name"`
+}
+
+func (*user) TableName() string                { return "user" }
+func (*user) CreateTable(q *querier.Q)         { q.Write("PRIMARY KEY (id)") }
+func (*user) Migrate(*querier.Q, string) error { return nil }
+
+var (
+	registerOnce sync.Once
+	mu           sync.Mutex
+	executed     []string
+)
+
+// testDSN returns a DSN of scheme queriertest, whose statements are recorded in executed.
+func testDSN() string {
+	registerOnce.Do(func() {
+		querier.Register("queriertest", func(dsn string) (string, string, querier.Dialect, error) {
+			return testdriver.DriverName, strings.TrimPrefix(dsn, "queriertest://"), testDialect{}, nil
+		})
+	})
+	return "queriertest://" + testdriver.DSN(func(query string, args []driver.Value) (*testdriver.Result, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		executed = append(executed, query)
+		return nil, nil
+	})
+}
+
+func TestWithDatabase(t *testing.T) {
+	os.Setenv(EnvVar("queriertest"), testDSN())
+	defer os.Unsetenv(EnvVar("queriertest"))
+	executed = nil
+
+	t.Run("test", func(t *testing.T) {
+		db := WithDatabase(t, "queriertest", &user{})
+		if err := db.New().Write("DELETE FROM user").Exec(); err != nil {
+			t.Error(err)
+		}
+	})
+	want := []string{"CREATE TABLE user", "DELETE FROM user", "DROP TABLE user"}
+	if len(executed) != len(want) {
+		t.Fatalf("executed %q, want %q", executed, want)
+	}
+	for i := range want {
+		if !strings.HasPrefix(executed[i], want[i]) {
+			t.Errorf("statement %d = %q, want %s", i, executed[i], want[i])
+		}
+	}
+}
+
+func TestWithDatabaseSkipped(t *testing.T) {
+	var skipped bool
+	t.Run("test", func(t *testing.T) {
+		defer func() { skipped = t.Skipped() }()
+		WithDatabase(t, "queriertest-unset")
+	})
+	if !skipped {
+		t.Error("WithDatabase() didn't skip the test without a database")
+	}
+}
+
+func TestStarter(t *testing.T) {
+	starts, stops := 0, 0
+	RegisterStarter("queriertest-started", func(context.Context) (string, func() error, error) {
+		starts++
+		return testDSN(), func() error { stops++; return nil }, nil
+	})
+	for i := 0; i < 2; i++ {
+		t.Run("test", func(t *testing.T) {
+			WithDatabase(t, "queriertest-started")
+		})
+	}
+	if starts != 1 {
+		t.Errorf("started %d times, want once", starts)
+	}
+	if err := Stop(); err != nil || stops != 1 {
+		t.Errorf("Stop() = %v, stopped %d times, want once", err, stops)
+	}
+
+	// A database that failed to start fails the tests using it.
+	errStart := errors.New("no container runtime")
+	RegisterStarter("queriertest-failed", func(context.Context) (string, func() error, error) {
+		return "", nil, errStart
+	})
+	if _, err := dsn("queriertest-failed"); err != errStart {
+		t.Errorf("dsn() = %v, want %v", err, errStart)
+	}
+	Stop()
+}
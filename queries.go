@@ -0,0 +1,100 @@
+//go:build go1.16
+// +build go1.16
+
+package querier
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// Queries are named queries loaded from .sql files.
+type Queries struct {
+	queries map[string]string
+}
+
+// FromFS loads the named queries of the .sql files in fsys, e.g. embedded with go:embed. A file contains a single
+// query named after the file, e.g. "get_user.sql" contains query "get_user", or multiple queries that each start with
+// a name comment:
+//
+//	-- name: get_user
+//	SELECT * FROM user WHERE id = :id;
+//
+//	-- name: delete_user
+//	DELETE FROM user WHERE id = :id;
+//
+// Queries use named parameters, see WriteNamed. A trailing semicolon is removed. Query names must be unique.
+func FromFS(fsys fs.FS) (*Queries, error) {
+	s := &Queries{queries: make(map[string]string)}
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Ext(p) != ".sql" {
+			return err
+		}
+		b, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		return s.parse(strings.TrimSuffix(path.Base(p), ".sql"), string(b))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// parse adds the queries in file name.
+func (s *Queries) parse(name, file string) error {
+	var query strings.Builder
+	add := func() error {
+		text := strings.TrimSuffix(strings.TrimSpace(query.String()), ";")
+		query.Reset()
+		if text == "" {
+			return nil
+		}
+		if _, ok := s.queries[name]; ok {
+			return fmt.Errorf("duplicate query %q", name)
+		}
+		s.queries[name] = text
+		return nil
+	}
+	scanner := bufio.NewScanner(strings.NewReader(file))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "--") {
+			comment := strings.TrimSpace(strings.TrimPrefix(trimmed, "--"))
+			if strings.HasPrefix(comment, "name:") {
+				if err := add(); err != nil {
+					return err
+				}
+				name = strings.TrimSpace(strings.TrimPrefix(comment, "name:"))
+				continue
+			}
+		}
+		query.WriteString(line)
+		query.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return add()
+}
+
+// Query returns the text of query name, or false if there is no such query.
+func (s *Queries) Query(name string) (string, bool) {
+	query, ok := s.queries[name]
+	return query, ok
+}
+
+// New returns a new Querier of query name with args for its named parameters. A missing argument results in an error
+// when the query is executed. Panics when there is no query name.
+func (s *Queries) New(ex Executor, d Dialect, name string, args map[string]interface{}) *Q {
+	query, ok := s.queries[name]
+	if !ok {
+		panic("unknown query " + name)
+	}
+	return New(ex, d).WriteNamed(query, args)
+}
@@ -0,0 +1,63 @@
+//go:build go1.16
+// +build go1.16
+
+package querier
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFromFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sql/count_users.sql": {Data: []byte("SELECT COUNT(*) FROM user;\n")},
+		"sql/user.sql": {Data: []byte(`-- name: get_user
+-- Returns a user by id.
+SELECT * FROM user
+WHERE id = :id;
+
+-- name: delete_user
+DELETE FROM user WHERE id = :id;
+`)},
+		"README.md": {Data: []byte("-- name: ignored")},
+	}
+	queries, err := FromFS(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name, want string
+	}{
+		{"count_users", "SELECT COUNT(*) FROM user"},
+		{"get_user", "-- Returns a user by id.\nSELECT * FROM user\nWHERE id = :id"},
+		{"delete_user", "DELETE FROM user WHERE id = :id"},
+	}
+	for _, tt := range tests {
+		if got, ok := queries.Query(tt.name); !ok || got != tt.want {
+			t.Errorf("Query(%q) = %q, %v, want %q", tt.name, got, ok, tt.want)
+		}
+	}
+	if _, ok := queries.Query("ignored"); ok {
+		t.Error("Query() returned a query of a non .sql file")
+	}
+
+	q := queries.New(nil, Default{}, "delete_user", map[string]interface{}{"id": 1})
+	if want := "DELETE FROM user WHERE id = ?"; q.String() != want {
+		t.Errorf("String() = %q, want %q", q.String(), want)
+	}
+	if want := []interface{}{1}; !reflect.DeepEqual(q.Params(), want) {
+		t.Errorf("Params() = %v, want %v", q.Params(), want)
+	}
+}
+
+func TestFromFSDuplicate(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.sql": {Data: []byte("-- name: q\nSELECT 1;")},
+		"b.sql": {Data: []byte("-- name: q\nSELECT 2;")},
+	}
+	if _, err := FromFS(fsys); err == nil {
+		t.Error("FromFS() with duplicate query names returned no error")
+	}
+}
@@ -0,0 +1,359 @@
+// Package queue implements a simple database backed job queue using Querier.
+//
+// Jobs are stored in a single table, which can be created with the migrator using Queue.Model. Workers claim jobs with
+// Claim, which locks the job's row using FOR UPDATE SKIP LOCKED when the dialect supports it. Otherwise jobs are
+// claimed by conditionally updating their status. A claimed job is leased to its worker: when the worker doesn't
+// complete or fail it within the Lease, e.g. because it crashed, the job is claimed again. A job that failed
+// MaxAttempts times is moved to the dead letters.
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/semrekkers/querier"
+	"github.com/semrekkers/querier/internal/table"
+	"github.com/semrekkers/querier/migrator"
+)
+
+// Job statuses.
+const (
+	StatusPending = "pending"
+	StatusRunning = "running"
+	StatusDone    = "done"
+	StatusDead    = "dead"
+)
+
+// DefaultMaxAttempts is the number of attempts of a job when Queue.MaxAttempts is zero.
+const DefaultMaxAttempts = 5
+
+// DefaultLease is the lease of a claimed job when Queue.Lease is zero.
+const DefaultLease = 5 * time.Minute
+
+var (
+	// ErrNoJob means that there is no job ready to be claimed.
+	ErrNoJob = errors.New("no job ready")
+	// ErrNotClaimed means that a job isn't claimed by the caller anymore, e.g. because its lease expired and it was
+	// claimed again.
+	ErrNotClaimed = errors.New("job is not claimed")
+)
+
+// Job is a job in the queue.
+type Job struct {
+	ID        string    `db:"id,CHAR(32) NOT NULL"`
+	Payload   []byte    `db:"payload,TEXT NOT NULL"`
+	Status    string    `db:"status,,enum=pending;running;done;dead"`
+	Attempts  int       `db:"attempts"`
+	LastError string    `db:"last_error,TEXT NOT NULL"`
+	RunAt     time.Time `db:"run_at"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+	// LockedUntil is the end of the lease of a running job.
+	LockedUntil *time.Time `db:"locked_until"`
+}
+
+// Decode decodes the job's JSON payload into v.
+func (j *Job) Decode(v interface{}) error {
+	return json.Unmarshal(j.Payload, v)
+}
+
+// Queue is a job queue stored in a table. It is safe for multiple goroutines, and processes, to call it's methods.
+type Queue struct {
+	db    *sql.DB
+	d     querier.Dialect
+	table string
+
+	// MaxAttempts is the number of times a job is attempted before it is moved to the dead letters. Zero means
+	// DefaultMaxAttempts.
+	MaxAttempts int
+	// Lease is how long a claimed job stays claimed without being completed, failed or extended, after which it's
+	// claimed again. Zero means DefaultLease.
+	Lease time.Duration
+	// Backoff returns the delay before a failed job is retried, attempts is the number of attempts so far. Nil means
+	// an exponential backoff starting at one second.
+	Backoff func(attempts int) time.Duration
+}
+
+// New returns a new Queue, stored in table.
+func New(db *sql.DB, d querier.Dialect, table string) *Queue {
+	return &Queue{db: db, d: d, table: table}
+}
+
+// Model returns the model of the queue's table, to be migrated by the migrator.
+func (qu *Queue) Model() migrator.Model {
+	return &model{Model: table.Model{Name: qu.table}}
+}
+
+// Enqueue adds a job with payload v, encoded as JSON, that is ready to run. It returns the job's ID.
+func (qu *Queue) Enqueue(ctx context.Context, v interface{}) (string, error) {
+	return qu.EnqueueAt(ctx, v, time.Now())
+}
+
+// EnqueueAt adds a job with payload v, encoded as JSON, that runs at or after runAt. It returns the job's ID.
+func (qu *Queue) EnqueueAt(ctx context.Context, v interface{}, runAt time.Time) (string, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	id, err := table.NewID()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now().UTC()
+	job := Job{
+		ID:        id,
+		Payload:   payload,
+		Status:    StatusPending,
+		RunAt:     runAt.UTC(),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	q := querier.New(qu.db, qu.d)
+	fields := q.Fields(&job).Select()
+	err = q.Writef("INSERT INTO %s (", qu.table).
+		WriteFields("{name}", querier.FieldSep, fields...).
+		WriteRaw(") VALUES (").
+		WriteValueMap("{bindVar}", querier.FieldSep, querier.Values(&job), fields...).
+		WriteRaw(")").
+		ExecContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// Claim claims the next job that is ready to run, or whose lease expired, and marks it running. It returns ErrNoJob if
+// there is none. The caller must call Complete or Fail when it's done with the job, within the Lease or after
+// extending it with Extend. A job whose lease expired after its last attempt is moved to the dead letters instead.
+func (qu *Queue) Claim(ctx context.Context) (*Job, error) {
+	for {
+		job, err := qu.claim(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if job.Attempts <= qu.maxAttempts() {
+			return job, nil
+		}
+		// The worker of its last attempt didn't finish it, e.g. because the job crashes the worker.
+		err = qu.update(ctx, job, StatusDead, job.RunAt, "lease expired")
+		if err != nil && err != ErrNotClaimed {
+			return nil, err
+		}
+	}
+}
+
+func (qu *Queue) claim(ctx context.Context) (*Job, error) {
+	if !querier.Supports(qu.d, querier.FeatureTransactions) || !table.CanSkipLocked(qu.d) {
+		return qu.claimUpdate(ctx)
+	}
+
+	tx, err := qu.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var job Job
+	q := querier.New(tx, qu.d)
+	err = qu.selectReady(q).ForUpdate().SkipLocked().FirstContext(ctx, &job)
+	if err == querier.ErrNoRecord {
+		return nil, ErrNoJob
+	} else if err != nil {
+		return nil, err
+	}
+	if _, err = qu.markRunning(ctx, querier.New(tx, qu.d), &job); err != nil {
+		return nil, err
+	}
+	return &job, tx.Commit()
+}
+
+// claimUpdate claims a job without row locks. A job claimed by another worker in the meantime isn't updated, in which
+// case the next job is tried.
+func (qu *Queue) claimUpdate(ctx context.Context) (*Job, error) {
+	for {
+		var job Job
+		err := qu.selectReady(querier.New(qu.db, qu.d)).FirstContext(ctx, &job)
+		if err == querier.ErrNoRecord {
+			return nil, ErrNoJob
+		} else if err != nil {
+			return nil, err
+		}
+		claimed, err := qu.markRunning(ctx, querier.New(qu.db, qu.d), &job)
+		if err != nil {
+			return nil, err
+		}
+		if claimed {
+			return &job, nil
+		}
+	}
+}
+
+func (qu *Queue) selectReady(q *querier.Q) *querier.Q {
+	now := time.Now().UTC()
+	return q.Write("SELECT").
+		WriteFields("{name}", querier.FieldSep, q.Fields(&Job{}).Select()...).
+		Writef("FROM %s", qu.table).
+		WriteValues("WHERE (status = {bindVar}", "", StatusPending).
+		WriteValues("AND run_at <= {bindVar})", "", now).
+		WriteValues("OR (status = {bindVar}", "", StatusRunning).
+		WriteValues("AND locked_until <= {bindVar})", "", now).
+		Write("ORDER BY run_at LIMIT 1")
+}
+
+// markRunning marks job running if it's still pending or its lease expired, it reports whether the job was claimed.
+func (qu *Queue) markRunning(ctx context.Context, q *querier.Q, job *Job) (bool, error) {
+	now := time.Now().UTC()
+	lockedUntil := now.Add(qu.lease())
+	err := q.Writef("UPDATE %s", qu.table).
+		WriteValues("SET status = {bindVar},", "", StatusRunning).
+		Write("attempts = attempts + 1,").
+		WriteValues("locked_until = {bindVar},", "", lockedUntil).
+		WriteValues("updated_at = {bindVar}", "", now).
+		WriteValues("WHERE id = {bindVar}", "", job.ID).
+		WriteValues("AND (status = {bindVar}", "", StatusPending).
+		WriteValues("OR status = {bindVar}", "", StatusRunning).
+		WriteValues("AND locked_until <= {bindVar})", "", now).
+		ExecContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	if querier.Supports(qu.d, querier.FeatureRowsAffected) && q.RowsAffected() == 0 {
+		return false, nil
+	}
+	job.Status = StatusRunning
+	job.Attempts++
+	job.LockedUntil = &lockedUntil
+	job.UpdatedAt = now
+	return true, nil
+}
+
+// Extend extends the lease of a claimed job by the Lease from now, for jobs that run longer than it. It returns
+// ErrNotClaimed if the job isn't claimed by the caller anymore.
+func (qu *Queue) Extend(ctx context.Context, job *Job) error {
+	now := time.Now().UTC()
+	lockedUntil := now.Add(qu.lease())
+	q := querier.New(qu.db, qu.d)
+	err := q.Writef("UPDATE %s", qu.table).
+		WriteValues("SET locked_until = {bindVar},", "", lockedUntil).
+		WriteValues("updated_at = {bindVar}", "", now).
+		WriteValues("WHERE id = {bindVar}", "", job.ID).
+		WriteValues("AND status = {bindVar}", "", StatusRunning).
+		WriteValues("AND attempts = {bindVar}", "", job.Attempts).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+	if querier.Supports(qu.d, querier.FeatureRowsAffected) && q.RowsAffected() == 0 {
+		return ErrNotClaimed
+	}
+	job.LockedUntil, job.UpdatedAt = &lockedUntil, now
+	return nil
+}
+
+// Complete marks a claimed job done. It returns ErrNotClaimed if the job isn't claimed by the caller anymore.
+func (qu *Queue) Complete(ctx context.Context, job *Job) error {
+	return qu.update(ctx, job, StatusDone, job.RunAt, "")
+}
+
+// Fail records that a claimed job failed with jobErr, which may be nil. The job is retried after the Backoff delay,
+// unless it was attempted MaxAttempts times, then it is moved to the dead letters. It returns ErrNotClaimed if the job
+// isn't claimed by the caller anymore.
+func (qu *Queue) Fail(ctx context.Context, job *Job, jobErr error) error {
+	var lastError string
+	if jobErr != nil {
+		lastError = jobErr.Error()
+	}
+	if job.Attempts >= qu.maxAttempts() {
+		return qu.update(ctx, job, StatusDead, job.RunAt, lastError)
+	}
+	return qu.update(ctx, job, StatusPending, time.Now().UTC().Add(qu.backoff(job.Attempts)), lastError)
+}
+
+// DeadLetters returns the jobs that failed MaxAttempts times, oldest first.
+func (qu *Queue) DeadLetters(ctx context.Context) (jobs []Job, err error) {
+	q := querier.New(qu.db, qu.d)
+	err = q.Write("SELECT").
+		WriteFields("{name}", querier.FieldSep, q.Fields(&Job{}).Select()...).
+		Writef("FROM %s", qu.table).
+		WriteValues("WHERE status = {bindVar}", "", StatusDead).
+		Write("ORDER BY updated_at").
+		FindContext(ctx, &jobs)
+	return
+}
+
+// Retry moves a dead letter back to the queue, it is ready to run with its attempts reset.
+func (qu *Queue) Retry(ctx context.Context, id string) error {
+	q := querier.New(qu.db, qu.d)
+	now := time.Now().UTC()
+	err := q.Writef("UPDATE %s", qu.table).
+		WriteValues("SET status = {bindVar},", "", StatusPending).
+		Write("attempts = 0,").
+		WriteValues("run_at = {bindVar},", "", now).
+		WriteValues("updated_at = {bindVar}", "", now).
+		WriteValues("WHERE id = {bindVar}", "", id).
+		WriteValues("AND status = {bindVar}", "", StatusDead).
+		ExecContext(ctx)
+	if err == nil && querier.Supports(qu.d, querier.FeatureRowsAffected) && q.RowsAffected() == 0 {
+		return fmt.Errorf("job %s is not a dead letter", id)
+	}
+	return err
+}
+
+// update updates the status of a claimed job. The attempts identify the claim, a job that was claimed again since has
+// more attempts.
+func (qu *Queue) update(ctx context.Context, job *Job, status string, runAt time.Time, lastError string) error {
+	now := time.Now().UTC()
+	q := querier.New(qu.db, qu.d)
+	err := q.Writef("UPDATE %s", qu.table).
+		WriteValues("SET status = {bindVar},", "", status).
+		WriteValues("run_at = {bindVar},", "", runAt).
+		WriteValues("last_error = {bindVar},", "", lastError).
+		Write("locked_until = NULL,").
+		WriteValues("updated_at = {bindVar}", "", now).
+		WriteValues("WHERE id = {bindVar}", "", job.ID).
+		WriteValues("AND status = {bindVar}", "", StatusRunning).
+		WriteValues("AND attempts = {bindVar}", "", job.Attempts).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+	if querier.Supports(qu.d, querier.FeatureRowsAffected) && q.RowsAffected() == 0 {
+		return ErrNotClaimed
+	}
+	job.Status, job.RunAt, job.LastError, job.LockedUntil, job.UpdatedAt = status, runAt, lastError, nil, now
+	return nil
+}
+
+func (qu *Queue) maxAttempts() int {
+	if qu.MaxAttempts == 0 {
+		return DefaultMaxAttempts
+	}
+	return qu.MaxAttempts
+}
+
+func (qu *Queue) lease() time.Duration {
+	if qu.Lease == 0 {
+		return DefaultLease
+	}
+	return qu.Lease
+}
+
+func (qu *Queue) backoff(attempts int) time.Duration {
+	if qu.Backoff != nil {
+		return qu.Backoff(attempts)
+	}
+	if attempts > 16 {
+		attempts = 16
+	}
+	return time.Second << uint(attempts-1)
+}
+
+// model is the Model of a queue's table.
+type model struct {
+	Job
+	table.Model
+}
@@ -0,0 +1,258 @@
+package queue
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/semrekkers/querier"
+	"github.com/semrekkers/querier/internal/testdriver"
+)
+
+var jobColumns = []string{"id", "payload", "status", "attempts", "last_error", "run_at", "created_at", "updated_at", "locked_until"}
+
+func jobRow(id, status string, attempts int64) []driver.Value {
+	now := time.Now().UTC()
+	return []driver.Value{id, []byte(`{}`), status, attempts, "", now, now, now, nil}
+}
+
+// noSkipLocked doesn't support SKIP LOCKED.
+type noSkipLocked struct {
+	querier.Default
+}
+
+func (noSkipLocked) LockClause(lock querier.Lock) (string, bool) {
+	if lock == querier.LockSkipLocked {
+		return "", false
+	}
+	return querier.Default{}.LockClause(lock)
+}
+
+func TestEnqueue(t *testing.T) {
+	var query string
+	var args []driver.Value
+	db := testdriver.Open(t, func(q string, a []driver.Value) (*testdriver.Result, error) {
+		query, args = q, a
+		return &testdriver.Result{RowsAffected: 1}, nil
+	})
+
+	id, err := New(db, querier.Default{}, "job").Enqueue(context.Background(), map[string]int{"n": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "INSERT INTO job ( id, payload, status, attempts, last_error, run_at, created_at, updated_at, locked_until) VALUES ( ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	if query != want {
+		t.Errorf("query = %q, want %q", query, want)
+	}
+	if len(id) != 32 || args[0] != id || string(args[1].([]byte)) != `{"n":1}` || args[2] != StatusPending {
+		t.Errorf("Enqueue() = %q, args %v", id, args)
+	}
+}
+
+func TestClaim(t *testing.T) {
+	var queries []string
+	db := testdriver.Open(t, func(query string, args []driver.Value) (*testdriver.Result, error) {
+		queries = append(queries, query)
+		switch {
+		case strings.HasPrefix(query, "SELECT"):
+			return &testdriver.Result{Columns: jobColumns, Rows: [][]driver.Value{jobRow("a", StatusPending, 0)}}, nil
+		case strings.HasPrefix(query, "UPDATE"):
+			return &testdriver.Result{RowsAffected: 1}, nil
+		}
+		return nil, nil
+	})
+
+	qu := New(db, querier.Default{}, "job")
+	qu.Lease = time.Minute
+	job, err := qu.Claim(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.ID != "a" || job.Status != StatusRunning || job.Attempts != 1 {
+		t.Errorf("Claim() = %+v", job)
+	}
+	if job.LockedUntil == nil || time.Until(*job.LockedUntil) <= 0 || time.Until(*job.LockedUntil) > time.Minute {
+		t.Errorf("Claim() LockedUntil = %v, want in a minute", job.LockedUntil)
+	}
+	if len(queries) != 4 || queries[0] != "BEGIN" || queries[3] != "COMMIT" {
+		t.Fatalf("executed %q", queries)
+	}
+	// Jobs whose lease expired are claimed again.
+	if want := "WHERE (status = ? AND run_at <= ?) OR (status = ? AND locked_until <= ?) ORDER BY run_at LIMIT 1 FOR UPDATE SKIP LOCKED"; !strings.HasSuffix(queries[1], want) {
+		t.Errorf("select = %q, want suffix %q", queries[1], want)
+	}
+	if want := "WHERE id = ? AND (status = ? OR status = ? AND locked_until <= ?)"; !strings.HasSuffix(queries[2], want) {
+		t.Errorf("update = %q, want suffix %q", queries[2], want)
+	}
+}
+
+func TestClaimNoJob(t *testing.T) {
+	db := testdriver.Open(t, func(string, []driver.Value) (*testdriver.Result, error) {
+		return nil, nil
+	})
+	if _, err := New(db, querier.Default{}, "job").Claim(context.Background()); err != ErrNoJob {
+		t.Errorf("Claim() = %v, want ErrNoJob", err)
+	}
+}
+
+func TestClaimUpdate(t *testing.T) {
+	// Without SKIP LOCKED, a job claimed by another worker in the meantime is skipped.
+	selects := 0
+	db := testdriver.Open(t, func(query string, args []driver.Value) (*testdriver.Result, error) {
+		switch {
+		case query == "BEGIN":
+			t.Error("claimed in a transaction")
+		case strings.HasPrefix(query, "SELECT"):
+			selects++
+			id := "a"
+			if selects > 1 {
+				id = "b"
+			}
+			return &testdriver.Result{Columns: jobColumns, Rows: [][]driver.Value{jobRow(id, StatusPending, 0)}}, nil
+		case strings.HasPrefix(query, "UPDATE"):
+			if args[3] == "a" {
+				return &testdriver.Result{RowsAffected: 0}, nil
+			}
+			return &testdriver.Result{RowsAffected: 1}, nil
+		}
+		return nil, nil
+	})
+
+	job, err := New(db, noSkipLocked{}, "job").Claim(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.ID != "b" || selects != 2 {
+		t.Errorf("Claim() = %s after %d selects, want b after 2", job.ID, selects)
+	}
+}
+
+func TestClaimLeaseExpired(t *testing.T) {
+	// A job whose lease expired after its last attempt is moved to the dead letters.
+	var dead []driver.Value
+	selects := 0
+	db := testdriver.Open(t, func(query string, args []driver.Value) (*testdriver.Result, error) {
+		switch {
+		case strings.HasPrefix(query, "SELECT"):
+			if selects++; selects > 1 {
+				return nil, nil
+			}
+			return &testdriver.Result{Columns: jobColumns, Rows: [][]driver.Value{jobRow("a", StatusRunning, 2)}}, nil
+		case strings.HasPrefix(query, "UPDATE") && strings.Contains(query, "last_error"):
+			dead = args
+			return &testdriver.Result{RowsAffected: 1}, nil
+		case strings.HasPrefix(query, "UPDATE"):
+			return &testdriver.Result{RowsAffected: 1}, nil
+		}
+		return nil, nil
+	})
+
+	qu := New(db, querier.Default{}, "job")
+	qu.MaxAttempts = 2
+	if _, err := qu.Claim(context.Background()); err != ErrNoJob {
+		t.Fatalf("Claim() = %v, want ErrNoJob", err)
+	}
+	if dead == nil || dead[0] != StatusDead || dead[2] != "lease expired" || dead[6] != int64(3) {
+		t.Errorf("dead letter update %v", dead)
+	}
+}
+
+func TestCompleteNotClaimed(t *testing.T) {
+	var args []driver.Value
+	db := testdriver.Open(t, func(_ string, a []driver.Value) (*testdriver.Result, error) {
+		args = a
+		return &testdriver.Result{RowsAffected: 0}, nil
+	})
+
+	job := &Job{ID: "a", Status: StatusRunning, Attempts: 1}
+	if err := New(db, querier.Default{}, "job").Complete(context.Background(), job); err != ErrNotClaimed {
+		t.Errorf("Complete() = %v, want ErrNotClaimed", err)
+	}
+	if job.Status != StatusRunning {
+		t.Errorf("Complete() changed the status to %s", job.Status)
+	}
+	if args[4] != "a" || args[5] != StatusRunning || args[6] != int64(1) {
+		t.Errorf("update args %v, want the claim of job a", args)
+	}
+}
+
+func TestFail(t *testing.T) {
+	var args []driver.Value
+	db := testdriver.Open(t, func(_ string, a []driver.Value) (*testdriver.Result, error) {
+		args = a
+		return &testdriver.Result{RowsAffected: 1}, nil
+	})
+	qu := New(db, querier.Default{}, "job")
+	qu.Backoff = func(int) time.Duration { return time.Hour }
+
+	job := &Job{ID: "a", Status: StatusRunning, Attempts: 1}
+	if err := qu.Fail(context.Background(), job, nil); err != nil {
+		t.Fatal(err)
+	}
+	if job.Status != StatusPending || job.LastError != "" || time.Until(job.RunAt) < 59*time.Minute {
+		t.Errorf("Fail() = %+v, want pending in an hour", job)
+	}
+
+	job = &Job{ID: "a", Status: StatusRunning, Attempts: DefaultMaxAttempts}
+	if err := qu.Fail(context.Background(), job, errors.New("boom")); err != nil {
+		t.Fatal(err)
+	}
+	if job.Status != StatusDead || args[2] != "boom" {
+		t.Errorf("Fail() = %+v, args %v, want dead", job, args)
+	}
+}
+
+func TestExtend(t *testing.T) {
+	affected := int64(1)
+	db := testdriver.Open(t, func(string, []driver.Value) (*testdriver.Result, error) {
+		return &testdriver.Result{RowsAffected: affected}, nil
+	})
+	qu := New(db, querier.Default{}, "job")
+
+	job := &Job{ID: "a", Status: StatusRunning, Attempts: 1}
+	if err := qu.Extend(context.Background(), job); err != nil || job.LockedUntil == nil {
+		t.Errorf("Extend() = %v, LockedUntil %v", err, job.LockedUntil)
+	}
+	affected = 0
+	if err := qu.Extend(context.Background(), job); err != ErrNotClaimed {
+		t.Errorf("Extend() = %v, want ErrNotClaimed", err)
+	}
+}
+
+// noRowsAffected doesn't report the rows affected, like an analytics database.
+type noRowsAffected struct {
+	querier.Default
+}
+
+func (noRowsAffected) Supports(f querier.Feature) bool { return f != querier.FeatureRowsAffected }
+
+func TestRetry(t *testing.T) {
+	db := testdriver.Open(t, func(string, []driver.Value) (*testdriver.Result, error) {
+		return &testdriver.Result{}, nil
+	})
+	if err := New(db, querier.Default{}, "job").Retry(context.Background(), "a"); err == nil {
+		t.Error("Retry() of a job that isn't a dead letter succeeded")
+	}
+	if err := New(db, noRowsAffected{}, "job").Retry(context.Background(), "a"); err != nil {
+		t.Errorf("Retry() without RowsAffected = %v", err)
+	}
+}
+
+func TestModel(t *testing.T) {
+	m := New(nil, querier.Default{}, "job").Model()
+	if m.TableName() != "job" {
+		t.Errorf("TableName() = %q, want job", m.TableName())
+	}
+	fields := querier.Fields(m).Select()
+	if len(fields) != len(jobColumns) {
+		t.Fatalf("Select() = %v, want the columns %v", fields, jobColumns)
+	}
+	for i, field := range fields {
+		if field.Name != jobColumns[i] {
+			t.Errorf("field %d = %s, want %s", i, field.Name, jobColumns[i])
+		}
+	}
+}
@@ -0,0 +1,308 @@
+package querier
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RecordMode is the mode of a Recorder.
+type RecordMode int
+
+const (
+	// Replay serves recorded results without a database.
+	Replay RecordMode = iota
+	// Record executes statements on a database and records their results.
+	Record
+)
+
+var errNotRecorded = errors.New("querier: not recorded")
+
+// Recorder is an Executor that records the results of statements in a file and replays them, so tests of code that
+// runs many queries can run without a database, e.g. in CI. In Record mode statements are executed by the underlying
+// Executor and their results are recorded, in Replay mode the recorded results are served:
+//
+//	mode := querier.Replay
+//	if os.Getenv("QUERIER_RECORD") != "" {
+//		mode = querier.Record
+//	}
+//	rec, err := querier.NewRecorder(db, "testdata/users.json", mode)
+//	...
+//	defer rec.Save()
+//
+// Results are keyed by the statement with its parameters interpolated and whitespace collapsed. Sensitive parameters
+// are redacted, so they don't end up in the recording. A statement that is executed multiple times replays its
+// results in the order they were recorded, the last result is repeated when they run out. A Recorder is safe for
+// concurrent use.
+type Recorder struct {
+	ex   Executor
+	mode RecordMode
+	path string
+
+	mu         sync.Mutex
+	recordings []*recording
+	byKey      map[string][]*recording
+	replayed   map[string]int
+}
+
+// recording is the recorded result of a statement.
+type recording struct {
+	Query        string             `json:"query"`
+	Columns      []string           `json:"columns,omitempty"`
+	Rows         [][]*recordedValue `json:"rows,omitempty"`
+	RowsAffected *int64             `json:"rowsAffected,omitempty"`
+	LastInsertID *int64             `json:"lastInsertId,omitempty"`
+	Err          string             `json:"error,omitempty"`
+}
+
+// recordedValue is a driver.Value that keeps its type when encoded as JSON. NULL is encoded as null.
+type recordedValue struct {
+	Int    *int64     `json:"int,omitempty"`
+	Float  *float64   `json:"float,omitempty"`
+	Bool   *bool      `json:"bool,omitempty"`
+	Bytes  *[]byte    `json:"bytes,omitempty"`
+	String *string    `json:"string,omitempty"`
+	Time   *time.Time `json:"time,omitempty"`
+}
+
+// NewRecorder returns a Recorder of the results in the file at path. In Record mode statements are executed by ex and
+// the file is written by Save, in Replay mode the file is read and ex may be nil.
+func NewRecorder(ex Executor, path string, mode RecordMode) (*Recorder, error) {
+	r := &Recorder{
+		ex:       ex,
+		mode:     mode,
+		path:     path,
+		byKey:    make(map[string][]*recording),
+		replayed: make(map[string]int),
+	}
+	if mode == Record {
+		return r, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &r.recordings); err != nil {
+		return nil, fmt.Errorf("querier: invalid recording %s: %v", path, err)
+	}
+	for _, rec := range r.recordings {
+		r.byKey[rec.Query] = append(r.byKey[rec.Query], rec)
+	}
+	return r, nil
+}
+
+// ExecContext implements Executor.
+func (r *Recorder) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	key := recordKey(query, args)
+	if r.mode == Replay {
+		rec, err := r.replay(key)
+		if err != nil {
+			return nil, err
+		}
+		return recordedResult{rec}, nil
+	}
+
+	rec := &recording{Query: key}
+	result, err := r.ex.ExecContext(ctx, query, args...)
+	if err != nil {
+		rec.Err = err.Error()
+		r.record(rec)
+		return nil, err
+	}
+	if n, err := result.RowsAffected(); err == nil {
+		rec.RowsAffected = &n
+	}
+	if id, err := result.LastInsertId(); err == nil {
+		rec.LastInsertID = &id
+	}
+	r.record(rec)
+	return result, nil
+}
+
+// QueryContext implements Executor. In Record mode the result set is read completely before it's returned.
+func (r *Recorder) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	key := recordKey(query, args)
+	if r.mode == Replay {
+		rec, err := r.replay(key)
+		if err != nil {
+			return nil, err
+		}
+		return memQuery(ctx, rec.resultSet())
+	}
+
+	rec := &recording{Query: key}
+	rs, err := r.query(ctx, query, args)
+	if err != nil {
+		rec.Err = err.Error()
+		r.record(rec)
+		return nil, err
+	}
+	rec.Columns = rs.columns
+	rec.Rows = make([][]*recordedValue, len(rs.values))
+	for i, values := range rs.values {
+		rec.Rows[i] = make([]*recordedValue, len(values))
+		for j, v := range values {
+			rec.Rows[i][j] = newRecordedValue(v)
+		}
+	}
+	r.record(rec)
+	return memQuery(ctx, rs)
+}
+
+// query runs query on the underlying Executor and reads its result set.
+func (r *Recorder) query(ctx context.Context, query string, args []interface{}) (*memResultSet, error) {
+	rows, err := r.ex.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	rs := &memResultSet{columns: columns}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		dest := make([]interface{}, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err = rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		row := make([]driver.Value, len(values))
+		for i, v := range values {
+			row[i] = v
+		}
+		rs.values = append(rs.values, row)
+	}
+	return rs, rows.Err()
+}
+
+func (r *Recorder) record(rec *recording) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.recordings = append(r.recordings, rec)
+}
+
+func (r *Recorder) replay(key string) (*recording, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	recs := r.byKey[key]
+	if len(recs) == 0 {
+		return nil, fmt.Errorf("querier: no recording of %q", key)
+	}
+	i := r.replayed[key]
+	if i < len(recs)-1 {
+		r.replayed[key]++
+	} else {
+		i = len(recs) - 1
+	}
+	if recs[i].Err != "" {
+		return nil, errors.New(recs[i].Err)
+	}
+	return recs[i], nil
+}
+
+// Save writes the recorded results to the file, creating its directory if needed. It does nothing in Replay mode.
+func (r *Recorder) Save() error {
+	if r.mode == Replay {
+		return nil
+	}
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.recordings, "", "\t")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.path, append(data, '\n'), 0644)
+}
+
+// recordKey returns the key of the results of query with args.
+func recordKey(query string, args []interface{}) string {
+	return strings.Join(strings.Fields(interpolate(nil, query, args)), " ")
+}
+
+func (rec *recording) resultSet() *memResultSet {
+	rs := &memResultSet{columns: rec.Columns, values: make([][]driver.Value, len(rec.Rows))}
+	for i, row := range rec.Rows {
+		rs.values[i] = make([]driver.Value, len(row))
+		for j, v := range row {
+			rs.values[i][j] = v.value()
+		}
+	}
+	return rs
+}
+
+// recordedResult is the recorded result of an Exec.
+type recordedResult struct {
+	rec *recording
+}
+
+func (r recordedResult) LastInsertId() (int64, error) {
+	if r.rec.LastInsertID == nil {
+		return 0, errNotRecorded
+	}
+	return *r.rec.LastInsertID, nil
+}
+
+func (r recordedResult) RowsAffected() (int64, error) {
+	if r.rec.RowsAffected == nil {
+		return 0, errNotRecorded
+	}
+	return *r.rec.RowsAffected, nil
+}
+
+func newRecordedValue(v driver.Value) *recordedValue {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case int64:
+		return &recordedValue{Int: &v}
+	case float64:
+		return &recordedValue{Float: &v}
+	case bool:
+		return &recordedValue{Bool: &v}
+	case []byte:
+		return &recordedValue{Bytes: &v}
+	case string:
+		return &recordedValue{String: &v}
+	case time.Time:
+		return &recordedValue{Time: &v}
+	}
+	// Not a driver.Value, but some drivers return other types anyway.
+	s := fmt.Sprint(v)
+	return &recordedValue{String: &s}
+}
+
+func (v *recordedValue) value() driver.Value {
+	switch {
+	case v == nil:
+		return nil
+	case v.Int != nil:
+		return *v.Int
+	case v.Float != nil:
+		return *v.Float
+	case v.Bool != nil:
+		return *v.Bool
+	case v.Bytes != nil:
+		return *v.Bytes
+	case v.String != nil:
+		return *v.String
+	case v.Time != nil:
+		return *v.Time
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package querier
+
+import (
+	"database/sql/driver"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecorder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "querier")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "testdata", "user.json")
+
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	db := openTestDB(t, func(query string, args []driver.Value) (*testRows, error) {
+		if strings.HasPrefix(query, "SELECT") {
+			return &testRows{
+				columns: []string{"id", "name", "avatar", "score", "created"},
+				values:  [][]driver.Value{{int64(1), "foo", []byte{1, 2}, 1.5, created}, {int64(2), nil, nil, 0.0, created}},
+			}, nil
+		}
+		return &testRows{values: [][]driver.Value{{}}}, nil
+	})
+	defer db.Close()
+
+	type user struct {
+		ID      int
+		Name    *string
+		Avatar  []byte
+		Score   float64
+		Created time.Time
+	}
+	run := func(ex Executor) ([]user, int64) {
+		var users []user
+		if err := New(ex, Default{}).Write("SELECT * FROM user  WHERE active = ?", true).Find(&users); err != nil {
+			t.Fatal(err)
+		}
+		q := New(ex, Default{}).Write("UPDATE user SET seen = ?", created)
+		if err := q.Exec(); err != nil {
+			t.Fatal(err)
+		}
+		return users, q.RowsAffected()
+	}
+
+	rec, err := NewRecorder(db, path, Record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantUsers, wantAffected := run(rec)
+	if err = rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	rec, err = NewRecorder(nil, path, Replay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	users, affected := run(rec)
+	if !reflect.DeepEqual(users, wantUsers) || affected != wantAffected {
+		t.Errorf("replayed %+v, %d, want %+v, %d", users, affected, wantUsers, wantAffected)
+	}
+
+	if err = New(rec, Default{}).Write("DELETE FROM user").Exec(); err == nil || !strings.Contains(err.Error(), "no recording") {
+		t.Errorf("Exec() of unrecorded statement = %v, want no recording error", err)
+	}
+}
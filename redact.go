@@ -0,0 +1,63 @@
+package querier
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"io"
+)
+
+const (
+	// tagOptionSensitive marks a field as sensitive, e.g. `db:"password,,sensitive"`.
+	tagOptionSensitive = "sensitive"
+
+	// Redacted replaces sensitive parameters when they are formatted.
+	Redacted = "[REDACTED]"
+)
+
+// sensitiveParam is a parameter that is bound as is, but formatted as Redacted.
+type sensitiveParam struct {
+	v interface{}
+}
+
+// Sensitive marks param as sensitive. It is bound as is, but it is formatted as Redacted, so its value doesn't end up
+// in logs or error messages. Parameters of sensitive fields are marked sensitive automatically by WriteValueMap.
+func Sensitive(param interface{}) interface{} {
+	if _, ok := param.(sensitiveParam); ok {
+		return param
+	}
+	return sensitiveParam{param}
+}
+
+// IsSensitive returns true if param is marked sensitive.
+func IsSensitive(param interface{}) bool {
+	_, ok := param.(sensitiveParam)
+	return ok
+}
+
+// Value implements driver.Valuer.
+func (p sensitiveParam) Value() (driver.Value, error) {
+	return driver.DefaultParameterConverter.ConvertValue(p.v)
+}
+
+// String implements fmt.Stringer.
+func (sensitiveParam) String() string {
+	return Redacted
+}
+
+// Format implements fmt.Formatter, the value is redacted for any verb.
+func (sensitiveParam) Format(f fmt.State, _ rune) {
+	io.WriteString(f, Redacted)
+}
+
+// RedactedParams returns the query's parameters with sensitive parameters replaced by Redacted. It is meant for
+// logging and error reporting.
+func (q *Q) RedactedParams() []interface{} {
+	params := make([]interface{}, len(q.params))
+	for i, param := range q.params {
+		if IsSensitive(param) {
+			param = Redacted
+		}
+		params[i] = param
+	}
+	return params
+}
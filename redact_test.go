@@ -0,0 +1,40 @@
+package querier
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSensitiveParams(t *testing.T) {
+	type model struct {
+		Username string
+		Password string `db:"password,,sensitive"`
+	}
+	in := model{"alice", "hunter2"}
+
+	q := New(nil, Default{})
+	q.WriteValueMap("{bindVar}", FieldSep, Values(&in), q.Fields(&in).Select()...).
+		Write("AND token = ?", Sensitive("secret-token"))
+
+	for _, format := range []string{"%v", "%+v", "%#v", "%s", "%q"} {
+		out := fmt.Sprintf(format, q.Params())
+		if strings.Contains(out, in.Password) || strings.Contains(out, "secret-token") {
+			t.Errorf("Sprintf(%q, Params()) = %s, leaks sensitive values", format, out)
+		}
+	}
+
+	redacted := q.RedactedParams()
+	if redacted[1] != Redacted || redacted[2] != Redacted {
+		t.Errorf("RedactedParams() = %v", redacted)
+	}
+	if IsSensitive(q.Params()[0]) {
+		t.Error("IsSensitive(Username) = true, want false")
+	}
+
+	v, err := q.Params()[1].(driver.Valuer).Value()
+	if err != nil || v != in.Password {
+		t.Errorf("Value() = %v, %v, want %q", v, err, in.Password)
+	}
+}
@@ -0,0 +1,272 @@
+package querier
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errNotIntegral = errors.New("value is not integral")
+
+// DefaultTimeLayouts are tried in order when a time is scanned from text.
+var DefaultTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999999",
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02",
+}
+
+// Coercion configures the conversions applied by First and Find while scanning, on top of the conversions of
+// database/sql. This reduces scan errors with drivers that return values in different types. Times are always
+// coerced from text; a nil *Coercion uses the defaults.
+type Coercion struct {
+	// TimeLayouts are tried in order when a time.Time is scanned from text. Defaults to DefaultTimeLayouts.
+	TimeLayouts []string
+
+	// Location is the location of times scanned from text without a time zone. Defaults to UTC.
+	Location *time.Location
+
+	// Numbers enables lenient numeric conversions, e.g. scanning 1e+06 or "2.0" into an integer. Integral values are
+	// required and values are checked for overflow.
+	Numbers bool
+
+	// Funcs contains custom conversions by destination type. The returned value must be assignable to the type.
+	Funcs map[reflect.Type]func(src interface{}) (interface{}, error)
+}
+
+// SetCoercion sets the Coercion used for scanning.
+func (q *Q) SetCoercion(c *Coercion) *Q {
+	q.coercion = c
+	return q
+}
+
+// decodeColumns wraps the destinations in dest which are coerced by c, based on the column types.
+func decodeColumns(c *Coercion, columnTypes []*sql.ColumnType, dest []interface{}) {
+	for i, d := range dest {
+		if _, ok := d.(sql.Scanner); ok {
+			continue
+		}
+		v := reflect.ValueOf(d).Elem()
+		if !c.coerces(v.Type()) {
+			continue
+		}
+		var typeName string
+		if i < len(columnTypes) {
+			typeName = columnTypes[i].DatabaseTypeName()
+		}
+		dest[i] = &coercingScanner{c: c, dst: v, typeName: typeName}
+	}
+}
+
+// coerces returns true if values of type t are coerced.
+func (c *Coercion) coerces(t reflect.Type) bool {
+	if c != nil && c.Funcs[t] != nil {
+		return true
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+		if c != nil && c.Funcs[t] != nil {
+			return true
+		}
+	}
+	if t == reflectTypeTime {
+		return true
+	}
+	if c == nil || !c.Numbers || reflect.PtrTo(t).Implements(reflectTypeScanner) {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// coercingScanner scans into dst using a Coercion.
+type coercingScanner struct {
+	c        *Coercion
+	dst      reflect.Value
+	typeName string
+}
+
+// Scan implements sql.Scanner.
+func (s *coercingScanner) Scan(src interface{}) error {
+	dst := s.dst
+	if fn := s.c.fn(dst.Type()); fn != nil {
+		return s.call(fn, dst, src)
+	}
+	if dst.Kind() == reflect.Ptr {
+		if src == nil {
+			dst.Set(reflect.Zero(dst.Type()))
+			return nil
+		}
+		elem := reflect.New(dst.Type().Elem())
+		if err := s.coerce(elem.Elem(), src); err != nil {
+			return err
+		}
+		dst.Set(elem)
+		return nil
+	}
+	return s.coerce(dst, src)
+}
+
+func (s *coercingScanner) coerce(v reflect.Value, src interface{}) error {
+	if src == nil {
+		return fmt.Errorf("converting NULL to %s is unsupported", v.Type())
+	}
+	if fn := s.c.fn(v.Type()); fn != nil {
+		return s.call(fn, v, src)
+	}
+	if v.Type() == reflectTypeTime {
+		t, err := s.c.time(src, s.typeName)
+		if err == nil {
+			v.Set(reflect.ValueOf(t))
+		}
+		return err
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f, intText, err := number(src)
+		if err != nil {
+			return err
+		}
+		i := int64(f)
+		if intText != "" {
+			i, err = strconv.ParseInt(intText, 10, 64)
+		} else if f != math.Trunc(f) || float64(i) != f {
+			err = errNotIntegral
+		}
+		if err != nil || v.OverflowInt(i) {
+			return fmt.Errorf("cannot coerce %v into %s", src, v.Type())
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		f, intText, err := number(src)
+		if err != nil {
+			return err
+		}
+		u := uint64(f)
+		if intText != "" {
+			u, err = strconv.ParseUint(intText, 10, 64)
+		} else if f < 0 || f != math.Trunc(f) || float64(u) != f {
+			err = errNotIntegral
+		}
+		if err != nil || v.OverflowUint(u) {
+			return fmt.Errorf("cannot coerce %v into %s", src, v.Type())
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, _, err := number(src)
+		if err != nil {
+			return err
+		}
+		if v.OverflowFloat(f) {
+			return fmt.Errorf("cannot coerce %v into %s", src, v.Type())
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported Scan, storing driver.Value type %T into type %s", src, v.Type())
+	}
+	return nil
+}
+
+// call sets v to the result of fn.
+func (s *coercingScanner) call(fn func(interface{}) (interface{}, error), v reflect.Value, src interface{}) error {
+	result, err := fn(src)
+	if err != nil {
+		return err
+	}
+	if result == nil {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	rv := reflect.ValueOf(result)
+	if !rv.Type().AssignableTo(v.Type()) {
+		return fmt.Errorf("coercion into %s returned %T", v.Type(), result)
+	}
+	v.Set(rv)
+	return nil
+}
+
+func (c *Coercion) fn(t reflect.Type) func(interface{}) (interface{}, error) {
+	if c == nil {
+		return nil
+	}
+	return c.Funcs[t]
+}
+
+// time returns src as a time.Time. Text is parsed, using the layout for DATE columns if typeName is DATE.
+func (c *Coercion) time(src interface{}, typeName string) (time.Time, error) {
+	var value string
+	switch src := src.(type) {
+	case time.Time:
+		return src, nil
+	case []byte:
+		value = string(src)
+	case string:
+		value = src
+	default:
+		return time.Time{}, fmt.Errorf("unsupported Scan, storing driver.Value type %T into type time.Time", src)
+	}
+
+	if strings.HasPrefix(value, "0000-00-00") {
+		// MySQL's zero date.
+		return time.Time{}, nil
+	}
+	layouts, loc := DefaultTimeLayouts, time.UTC
+	if c != nil && c.TimeLayouts != nil {
+		layouts = c.TimeLayouts
+	}
+	if c != nil && c.Location != nil {
+		loc = c.Location
+	}
+	if strings.ToUpper(typeName) == "DATE" {
+		layouts = []string{"2006-01-02"}
+	}
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot decode %q into time.Time", value)
+}
+
+// number returns src as a float, or as text if src is an integer which must be parsed to avoid losing precision.
+func number(src interface{}) (f float64, intText string, err error) {
+	switch src := src.(type) {
+	case int64:
+		return 0, strconv.FormatInt(src, 10), nil
+	case float64:
+		return src, "", nil
+	case bool:
+		if src {
+			return 1, "", nil
+		}
+		return 0, "", nil
+	case []byte:
+		return parseNumber(string(src))
+	case string:
+		return parseNumber(src)
+	}
+	return 0, "", fmt.Errorf("cannot coerce %T into a number", src)
+}
+
+func parseNumber(s string) (float64, string, error) {
+	s = strings.TrimSpace(s)
+	if _, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return 0, s, nil
+	}
+	if _, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return 0, s, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	return f, "", err
+}
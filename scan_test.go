@@ -0,0 +1,118 @@
+package querier
+
+import (
+	"database/sql/driver"
+	"math"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestDecodeTimeColumns(t *testing.T) {
+	type event struct {
+		ID        int
+		CreatedAt time.Time
+		DeletedAt *time.Time
+	}
+	db := openTestDB(t, func(string, []driver.Value) (*testRows, error) {
+		return &testRows{
+			columns: []string{"ID", "CreatedAt", "DeletedAt"},
+			values: [][]driver.Value{
+				{[]byte("1"), []byte("2018-03-04 05:06:07"), nil},
+				{[]byte("2"), "2018-03-04T05:06:07.5Z", []byte("0000-00-00 00:00:00")},
+			},
+		}, nil
+	})
+	defer db.Close()
+
+	var events []event
+	if err := New(db, Default{}).Write("SELECT * FROM event").Find(&events); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("Find() returned %d events, want 2", len(events))
+	}
+	want := time.Date(2018, 3, 4, 5, 6, 7, 0, time.UTC)
+	if events[0].ID != 1 || !events[0].CreatedAt.Equal(want) || events[0].DeletedAt != nil {
+		t.Errorf("Find()[0] = %+v", events[0])
+	}
+	if !events[1].CreatedAt.Equal(want.Add(500*time.Millisecond)) || events[1].DeletedAt == nil || !events[1].DeletedAt.IsZero() {
+		t.Errorf("Find()[1] = %+v", events[1])
+	}
+
+	var e event
+	if err := New(db, Default{}).Write("SELECT * FROM event").First(&e); err != nil {
+		t.Fatal(err)
+	}
+	if !e.CreatedAt.Equal(want) {
+		t.Errorf("First() CreatedAt = %v, want %v", e.CreatedAt, want)
+	}
+}
+
+func TestCoercion(t *testing.T) {
+	type amount int64
+	type row struct {
+		Count   int32
+		Big     uint64
+		Ratio   float32
+		Amount  amount
+		Day     time.Time
+		Missing *int
+	}
+	db := openTestDB(t, func(string, []driver.Value) (*testRows, error) {
+		return &testRows{
+			columns: []string{"Count", "Big", "Ratio", "Amount", "Day", "Missing"},
+			values:  [][]driver.Value{{1e+06, []byte("18446744073709551615"), "0.5", "12.34", "04/03/2018", nil}},
+		}, nil
+	})
+	defer db.Close()
+
+	loc := time.FixedZone("test", 3600)
+	c := &Coercion{
+		TimeLayouts: []string{"02/01/2006"},
+		Location:    loc,
+		Numbers:     true,
+		Funcs: map[reflect.Type]func(interface{}) (interface{}, error){
+			reflect.TypeOf(amount(0)): func(src interface{}) (interface{}, error) {
+				f, err := strconv.ParseFloat(src.(string), 64)
+				return amount(f*100 + 0.5), err
+			},
+		},
+	}
+	var r row
+	if err := New(db, Default{}).SetCoercion(c).Write("SELECT").First(&r); err != nil {
+		t.Fatal(err)
+	}
+	want := row{1000000, math.MaxUint64, 0.5, 1234, time.Date(2018, 3, 4, 0, 0, 0, 0, loc), nil}
+	if !reflect.DeepEqual(r, want) {
+		t.Errorf("First() = %+v, want %+v", r, want)
+	}
+}
+
+func TestCoercionErrors(t *testing.T) {
+	var (
+		i8 int8
+		u  uint
+		tm time.Time
+	)
+	c := &Coercion{Numbers: true}
+	tests := []struct {
+		dst interface{}
+		src interface{}
+	}{
+		{&i8, int64(128)},
+		{&i8, 1.5},
+		{&u, int64(-1)},
+		{&u, "-2.0"},
+		{&i8, nil},
+		{&tm, "yesterday"},
+	}
+
+	for _, tt := range tests {
+		s := &coercingScanner{c: c, dst: reflect.ValueOf(tt.dst).Elem()}
+		if err := s.Scan(tt.src); err == nil {
+			t.Errorf("Scan(%v) into %T succeeded", tt.src, tt.dst)
+		}
+	}
+}
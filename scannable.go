@@ -0,0 +1,63 @@
+package querier
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+// Scannable can be implemented by the destination of First and Find to scan a row without reflection, e.g. by
+// generated code or by hand for hot paths. In a ForEach ScanFunc, ScanRow can be called with the rows directly.
+type Scannable interface {
+	// ScanRow scans the current row of rows.
+	ScanRow(rows *sql.Rows) error
+}
+
+// ColumnLister can be implemented by a Scannable to declare the columns it scans, in order. First and Find return an
+// error if the columns of the result differ, so ScanRow can scan by position.
+type ColumnLister interface {
+	// DBColumns returns the names of the scanned columns.
+	DBColumns() []string
+}
+
+var reflectTypeScannable = reflect.TypeOf((*Scannable)(nil)).Elem()
+
+// checkColumns returns an error if i is a ColumnLister whose columns differ from the columns of rows.
+func checkColumns(rows *sql.Rows, i interface{}) error {
+	lister, ok := i.(ColumnLister)
+	if !ok {
+		return nil
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	want := lister.DBColumns()
+	if len(columns) != len(want) {
+		return fmt.Errorf("querier: result columns %v don't match %v", columns, want)
+	}
+	for i := range columns {
+		if columns[i] != want[i] {
+			return fmt.Errorf("querier: result columns %v don't match %v", columns, want)
+		}
+	}
+	return nil
+}
+
+// findScannable appends the rows to slice v, whose elements implement Scannable.
+func findScannable(rows *sql.Rows, v reflect.Value, elemType reflect.Type, elemIsPtr bool) error {
+	if err := checkColumns(rows, reflect.New(elemType).Interface()); err != nil {
+		return err
+	}
+	for rows.Next() {
+		element := reflect.New(elemType)
+		if err := element.Interface().(Scannable).ScanRow(rows); err != nil {
+			return err
+		}
+		if !elemIsPtr {
+			element = element.Elem()
+		}
+		v.Set(reflect.Append(v, element))
+	}
+	return rows.Err()
+}
@@ -0,0 +1,77 @@
+package querier
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+)
+
+type scannableUser struct {
+	ID   int64
+	Name string
+}
+
+func (u *scannableUser) ScanRow(rows *sql.Rows) error {
+	return rows.Scan(&u.ID, &u.Name)
+}
+
+func (*scannableUser) DBColumns() []string {
+	return []string{"id", "name"}
+}
+
+func scannableUsers(columns ...string) testHandler {
+	return func(string, []driver.Value) (*testRows, error) {
+		return &testRows{
+			columns: columns,
+			values:  [][]driver.Value{{int64(1), "foo"}, {int64(2), "bar"}},
+		}, nil
+	}
+}
+
+func TestFirstScannable(t *testing.T) {
+	db := openTestDB(t, scannableUsers("id", "name"))
+	defer db.Close()
+
+	var u scannableUser
+	if err := New(db, Default{}).Write("SELECT id, name FROM user").First(&u); err != nil {
+		t.Fatal(err)
+	}
+	if u.ID != 1 || u.Name != "foo" {
+		t.Errorf("got %+v", u)
+	}
+}
+
+func TestFindScannable(t *testing.T) {
+	db := openTestDB(t, scannableUsers("id", "name"))
+	defer db.Close()
+
+	var users []scannableUser
+	if err := New(db, Default{}).Write("SELECT id, name FROM user").Find(&users); err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 || users[0].Name != "foo" || users[1].Name != "bar" {
+		t.Errorf("got %+v", users)
+	}
+
+	var ptrs []*scannableUser
+	if err := New(db, Default{}).Write("SELECT id, name FROM user").Find(&ptrs); err != nil {
+		t.Fatal(err)
+	}
+	if len(ptrs) != 2 || ptrs[1].ID != 2 {
+		t.Errorf("got %+v", ptrs)
+	}
+}
+
+func TestScannableColumnMismatch(t *testing.T) {
+	db := openTestDB(t, scannableUsers("name", "id"))
+	defer db.Close()
+
+	var u scannableUser
+	if err := New(db, Default{}).Write("SELECT name, id FROM user").First(&u); err == nil {
+		t.Error("expected an error for First")
+	}
+	var users []scannableUser
+	if err := New(db, Default{}).Write("SELECT name, id FROM user").Find(&users); err == nil {
+		t.Error("expected an error for Find")
+	}
+}
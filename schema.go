@@ -0,0 +1,51 @@
+package querier
+
+import "strings"
+
+// SetSchema sets the schema that the table names of the builder helpers, e.g. InsertStruct and WriteTable, are
+// qualified with. Table names that are already qualified are written as is.
+func (q *Q) SetSchema(schema string) *Q {
+	q.schema = schema
+	return q
+}
+
+// Schema returns the schema of the Querier, see SetSchema. It's empty if table names aren't qualified. Dialects use it
+// to look up tables in the schema, e.g. in DBInfo.HasTable of the migrator.
+func (q *Q) Schema() string {
+	return q.schema
+}
+
+// Qualify returns table qualified with the schema of the Querier, e.g. analytics.events. table is returned as is if
+// the Querier has no schema or if it's already qualified.
+func (q *Q) Qualify(table string) string {
+	if q.schema == "" || strings.IndexByte(table, '.') >= 0 {
+		return table
+	}
+	return q.schema + "." + table
+}
+
+// WithSchema returns a DB whose queries qualify table names with schema, see Q.SetSchema. It shares the connections,
+// configuration, health and shutdown of db, so one application can address multiple schemas:
+//
+//	analytics := db.WithSchema("analytics")
+//	err := analytics.New().InsertStruct("events", &event).Exec() // INSERT INTO analytics.events ...
+func (db *DB) WithSchema(schema string) *DB {
+	return &DB{
+		DB:             db.DB,
+		d:              db.d,
+		logger:         db.logger,
+		columnMatching: db.columnMatching,
+		opts:           db.opts,
+		ctx:            db.ctx,
+		schema:         schema,
+		root:           db.rootDB(),
+	}
+}
+
+// rootDB returns the DB that db was derived from by WithSchema, or db itself. It keeps the health and shutdown state.
+func (db *DB) rootDB() *DB {
+	if db.root != nil {
+		return db.root
+	}
+	return db
+}
@@ -0,0 +1,56 @@
+package querier
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+)
+
+func TestSchema(t *testing.T) {
+	type event struct {
+		ID   int64  `db:"id,,pk"`
+		Name string `db:"name"`
+	}
+	tests := []struct {
+		q    *Q
+		want string
+	}{
+		{New(nil, Default{}).SetSchema("analytics").InsertStruct("events", &event{1, "a"}),
+			"INSERT INTO analytics.events (id, name) VALUES (?, ?)"},
+		{New(nil, Default{}).SetSchema("analytics").DeleteStruct("public.events", &event{1, "a"}),
+			"DELETE FROM public.events WHERE id = ?"},
+		{New(nil, Default{}).SetSchema("analytics").Write("SELECT * FROM").WriteTable("events"),
+			`SELECT * FROM "analytics"."events"`},
+		{New(nil, Default{}).InsertStruct("events", &event{1, "a"}),
+			"INSERT INTO events (id, name) VALUES (?, ?)"},
+	}
+	for _, tt := range tests {
+		if tt.q.String() != tt.want {
+			t.Errorf("String() = %q, want %q", tt.q.String(), tt.want)
+		}
+	}
+
+	var got []string
+	db := NewDB(openTestDB(t, func(query string, _ []driver.Value) (*testRows, error) {
+		got = append(got, query)
+		return nil, nil
+	}), Default{})
+	analytics := db.WithSchema("analytics")
+	if err := analytics.New().DeleteStruct("events", &event{ID: 1}).Exec(); err != nil {
+		t.Fatal(err)
+	}
+	if want := "DELETE FROM analytics.events WHERE id = ?"; len(got) != 1 || got[0] != want {
+		t.Errorf("executed %q, want %q", got, want)
+	}
+	if db.New().Schema() != "" {
+		t.Errorf("WithSchema() changed the schema of the DB it was derived from")
+	}
+
+	// The derived DB shares the shutdown of db.
+	if err := db.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := analytics.New().Write("DELETE FROM events").Exec(); err != ErrShutdown {
+		t.Errorf("Exec() after Shutdown = %v, want %v", err, ErrShutdown)
+	}
+}
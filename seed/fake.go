@@ -0,0 +1,196 @@
+package seed
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/semrekkers/querier"
+)
+
+// fakeTagKey is the key of the struct field tag with the kind of fake value of the field.
+const fakeTagKey = "fake"
+
+var (
+	firstNames = []string{"Alice", "Bob", "Carol", "Dave", "Eve", "Frank", "Grace", "Heidi", "Ivan", "Judy", "Mallory",
+		"Niaj", "Olivia", "Peggy", "Rupert", "Sybil", "Trent", "Victor", "Walter", "Yara"}
+	lastNames = []string{"Smith", "Jones", "Brown", "Taylor", "Wilson", "Evans", "Thomas", "Johnson", "Roberts",
+		"Walker", "Wright", "Robinson", "Thompson", "White", "Hughes", "Edwards", "Green", "Hall", "Wood", "Harris"}
+	words = []string{"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing", "elit", "sed", "do",
+		"eiusmod", "tempor", "incididunt", "ut", "labore", "et", "dolore", "magna", "aliqua", "enim"}
+	domains = []string{"example.com", "example.org", "example.net"}
+)
+
+// Faker generates fake values for the fields of structs by their fake tag, e.g. `fake:"email"`. The kinds are:
+//
+//	name, first_name, last_name   a person's name
+//	email, username               unique within the Faker
+//	word, sentence, paragraph     lorem ipsum text
+//	int(min,max), float(min,max)  a number from min to max
+//	bool                          true or false
+//	time                          a time within the past year
+//	uuid                          a version 7 UUID
+//	oneof(a,b,c)                  one of the values, converted to the type of the field
+//
+// Only fields with their zero value are set, so values set by the application are kept. It is safe for multiple
+// goroutines to call its methods.
+type Faker struct {
+	mu  sync.Mutex
+	r   *rand.Rand
+	seq int
+}
+
+// NewFaker returns a new Faker whose values are generated from seed, the same seed generates the same values.
+func NewFaker(seed int64) *Faker {
+	return &Faker{r: rand.New(rand.NewSource(seed))}
+}
+
+// Fill sets the fields of struct i, a pointer to a struct, that have a fake tag and their zero value to fake values.
+// The fields of embedded structs are filled as well. It returns an error for an unknown kind, or a kind whose value
+// can't be converted to the type of its field.
+func (f *Faker) Fill(i interface{}) error {
+	v := reflect.ValueOf(i)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("seed: %T is not a pointer to a struct", i)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.fill(v.Elem())
+}
+
+func (f *Faker) fill(v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf, fv := t.Field(i), v.Field(i)
+		if sf.Anonymous && fv.Kind() == reflect.Struct {
+			if err := f.fill(fv); err != nil {
+				return err
+			}
+			continue
+		}
+		kind, ok := sf.Tag.Lookup(fakeTagKey)
+		if !ok || sf.PkgPath != "" || !reflect.DeepEqual(fv.Interface(), reflect.Zero(sf.Type).Interface()) {
+			continue
+		}
+		value, err := f.value(kind)
+		if err != nil {
+			return fmt.Errorf("seed: field %s: %v", sf.Name, err)
+		}
+		if err = set(fv, value); err != nil {
+			return fmt.Errorf("seed: field %s: %v", sf.Name, err)
+		}
+	}
+	return nil
+}
+
+// value returns a fake value of kind.
+func (f *Faker) value(kind string) (interface{}, error) {
+	name, args := kind, []string(nil)
+	if i := strings.IndexByte(kind, '('); i >= 0 && strings.HasSuffix(kind, ")") {
+		name, args = kind[:i], strings.Split(kind[i+1:len(kind)-1], ",")
+	}
+	switch name {
+	case "name":
+		return f.pick(firstNames) + " " + f.pick(lastNames), nil
+	case "first_name":
+		return f.pick(firstNames), nil
+	case "last_name":
+		return f.pick(lastNames), nil
+	case "email":
+		f.seq++
+		return strings.ToLower(f.pick(firstNames)+"."+f.pick(lastNames)) + strconv.Itoa(f.seq) + "@" + f.pick(domains),
+			nil
+	case "username":
+		f.seq++
+		return strings.ToLower(f.pick(firstNames)) + strconv.Itoa(f.seq), nil
+	case "word":
+		return f.pick(words), nil
+	case "sentence":
+		return f.sentence(), nil
+	case "paragraph":
+		sentences := make([]string, 3+f.r.Intn(3))
+		for i := range sentences {
+			sentences[i] = f.sentence()
+		}
+		return strings.Join(sentences, " "), nil
+	case "int", "float":
+		if len(args) != 2 {
+			return nil, fmt.Errorf("%s needs a min and max, e.g. %s(1,100)", name, name)
+		}
+		lo, err := strconv.ParseFloat(strings.TrimSpace(args[0]), 64)
+		if err != nil {
+			return nil, err
+		}
+		hi, err := strconv.ParseFloat(strings.TrimSpace(args[1]), 64)
+		if err != nil {
+			return nil, err
+		}
+		if hi < lo {
+			return nil, fmt.Errorf("%s has a max below its min", kind)
+		}
+		if name == "int" {
+			return int64(lo) + f.r.Int63n(int64(hi)-int64(lo)+1), nil
+		}
+		return lo + f.r.Float64()*(hi-lo), nil
+	case "bool":
+		return f.r.Intn(2) == 1, nil
+	case "time":
+		return time.Now().Add(-time.Duration(f.r.Int63n(int64(365 * 24 * time.Hour)))).Truncate(time.Second), nil
+	case "uuid":
+		return querier.NewUUIDv7()
+	case "oneof":
+		if len(args) == 0 {
+			return nil, fmt.Errorf("oneof needs values, e.g. oneof(a,b)")
+		}
+		return strings.TrimSpace(args[f.r.Intn(len(args))]), nil
+	}
+	return nil, fmt.Errorf("unknown fake kind %q", kind)
+}
+
+func (f *Faker) pick(values []string) string {
+	return values[f.r.Intn(len(values))]
+}
+
+func (f *Faker) sentence() string {
+	s := make([]string, 4+f.r.Intn(8))
+	for i := range s {
+		s[i] = f.pick(words)
+	}
+	s[0] = strings.ToUpper(s[0][:1]) + s[0][1:]
+	return strings.Join(s, " ") + "."
+}
+
+// set sets v, or the value v points to, to value converted to its type. Strings are parsed for numeric and boolean
+// fields, e.g. of oneof.
+func set(v reflect.Value, value interface{}) error {
+	if v.Kind() == reflect.Ptr {
+		v.Set(reflect.New(v.Type().Elem()))
+		v = v.Elem()
+	}
+	if s, ok := value.(string); ok {
+		var err error
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			value, err = strconv.ParseInt(s, 10, 64)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			value, err = strconv.ParseUint(s, 10, 64)
+		case reflect.Float32, reflect.Float64:
+			value, err = strconv.ParseFloat(s, 64)
+		case reflect.Bool:
+			value, err = strconv.ParseBool(s)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	rv := reflect.ValueOf(value)
+	if !rv.Type().ConvertibleTo(v.Type()) || (rv.Kind() == reflect.String) != (v.Kind() == reflect.String) {
+		return fmt.Errorf("fake value of type %s can't be converted to %s", rv.Type(), v.Type())
+	}
+	v.Set(rv.Convert(v.Type()))
+	return nil
+}
@@ -0,0 +1,80 @@
+//go:build go1.18
+// +build go1.18
+
+// Package seed seeds databases with generated rows using Querier, e.g. for load testing and demo environments:
+//
+//	err := seed.N(db, 1000, func(i int) *User {
+//		return &User{Role: "member"}
+//	})
+//
+// The fields of the rows with a fake tag that have their zero value are set to fake values, see Faker:
+//
+//	type User struct {
+//		ID    string `db:"id,,pk,id=uuidv7"`
+//		Name  string `db:"name" fake:"name"`
+//		Email string `db:"email" fake:"email"`
+//		Age   int    `db:"age" fake:"int(18,90)"`
+//		Role  string `db:"role" fake:"oneof(admin,member)"`
+//	}
+package seed
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/semrekkers/querier"
+)
+
+// BatchSize is the number of rows that N inserts per transaction.
+const BatchSize = 100
+
+// N inserts n rows returned by fn, which is called with the index of the row, see NContext.
+func N[T any](db *querier.DB, n int, fn func(i int) *T) error {
+	return NContext(context.Background(), db, n, fn)
+}
+
+// NContext inserts n rows returned by fn, which is called with the index of the row. fn may be nil, the rows are new
+// T's then. The fields with a fake tag that have their zero value are set to fake values. The rows are inserted with
+// InsertStruct into the table of T's TableName method, in batches of BatchSize rows per transaction, or one by one if
+// the database doesn't support transactions.
+func NContext[T any](ctx context.Context, db *querier.DB, n int, fn func(i int) *T) error {
+	named, ok := interface{}(new(T)).(interface{ TableName() string })
+	if !ok {
+		return fmt.Errorf("seed: %T has no TableName method", new(T))
+	}
+	table := named.TableName()
+	faker := NewFaker(time.Now().UnixNano())
+	batch := make([]*querier.Q, 0, BatchSize)
+	for i := 0; i < n; i++ {
+		row := new(T)
+		if fn != nil {
+			row = fn(i)
+		}
+		if err := faker.Fill(row); err != nil {
+			return err
+		}
+		batch = append(batch, db.New().InsertStruct(table, row))
+		if len(batch) < BatchSize && i < n-1 {
+			continue
+		}
+		if err := insert(ctx, db, batch); err != nil {
+			return fmt.Errorf("seed: %s: %v", table, err)
+		}
+		batch = batch[:0]
+	}
+	return nil
+}
+
+func insert(ctx context.Context, db *querier.DB, batch []*querier.Q) error {
+	if querier.Supports(db.Dialect(), querier.FeatureTransactions) {
+		_, err := db.New().ExecBatchContext(ctx, batch, nil)
+		return err
+	}
+	for _, q := range batch {
+		if err := q.ExecContext(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
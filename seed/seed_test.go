@@ -0,0 +1,127 @@
+//go:build go1.18
+// +build go1.18
+
+package seed
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/semrekkers/querier"
+	"github.com/semrekkers/querier/internal/testdriver"
+)
+
+type Audit struct {
+	CreatedAt time.Time `db:"created_at" fake:"time"`
+}
+
+type user struct {
+	ID    string  `db:"id,,pk" fake:"uuid"`
+	Name  string  `db:"name" fake:"name"`
+	Email string  `db:"email" fake:"email"`
+	Age   int     `db:"age" fake:"int(18,90)"`
+	Score float64 `db:"score" fake:"float(0,1)"`
+	Role  string  `db:"role" fake:"oneof(admin,member)"`
+	Level *int    `db:"level" fake:"oneof(1,2,3)"`
+	Bio   string  `db:"bio"`
+	Audit
+}
+
+func (*user) TableName() string { return "user" }
+
+func TestFill(t *testing.T) {
+	var u user
+	if err := NewFaker(1).Fill(&u); err != nil {
+		t.Fatal(err)
+	}
+	if len(u.ID) != 36 || !strings.Contains(u.Name, " ") || !strings.Contains(u.Email, "@") {
+		t.Errorf("Fill() = %+v, want an ID, name and email", u)
+	}
+	if u.Age < 18 || u.Age > 90 || u.Score < 0 || u.Score > 1 {
+		t.Errorf("Fill() = %+v, want an age from 18 to 90 and a score from 0 to 1", u)
+	}
+	if u.Role != "admin" && u.Role != "member" || u.Level == nil || *u.Level < 1 || *u.Level > 3 {
+		t.Errorf("Fill() = %+v, want a role and level of oneof", u)
+	}
+	if u.Bio != "" || u.CreatedAt.IsZero() {
+		t.Errorf("Fill() = %+v, want no bio and the time of the embedded struct", u)
+	}
+
+	// Values set by the application are kept, the same seed generates the same values.
+	u2 := user{Role: "owner"}
+	if err := NewFaker(1).Fill(&u2); err != nil {
+		t.Fatal(err)
+	}
+	if u2.Role != "owner" || u2.Name != u.Name || u2.Age != u.Age {
+		t.Errorf("Fill() = %+v, want the role kept and the values of the seed", u2)
+	}
+}
+
+func TestFillErrors(t *testing.T) {
+	tests := []struct {
+		in      interface{}
+		wantErr string
+	}{
+		{user{}, "seed: seed.user is not a pointer to a struct"},
+		{&struct {
+			A string `fake:"color"`
+		}{}, `seed: field A: unknown fake kind "color"`},
+		{&struct {
+			A int `fake:"int(10)"`
+		}{}, "seed: field A: int needs a min and max, e.g. int(1,100)"},
+		{&struct {
+			A int `fake:"int(10,1)"`
+		}{}, "seed: field A: int(10,1) has a max below its min"},
+		{&struct {
+			A bool `fake:"time"`
+		}{}, "seed: field A: fake value of type time.Time can't be converted to bool"},
+		{&struct {
+			A int `fake:"oneof(a,a)"`
+		}{}, `seed: field A: strconv.ParseInt: parsing "a": invalid syntax`},
+	}
+	for _, tt := range tests {
+		if err := NewFaker(1).Fill(tt.in); err == nil || err.Error() != tt.wantErr {
+			t.Errorf("Fill(%T) = %v, want %s", tt.in, err, tt.wantErr)
+		}
+	}
+}
+
+func TestN(t *testing.T) {
+	var queries []string
+	inserted := 0
+	db := testdriver.Open(t, func(query string, args []driver.Value) (*testdriver.Result, error) {
+		queries = append(queries, query)
+		if strings.HasPrefix(query, "INSERT INTO user") {
+			inserted++
+		}
+		return &testdriver.Result{RowsAffected: 1}, nil
+	})
+
+	n := BatchSize + 1
+	err := N(querier.NewDB(db, querier.Default{}), n, func(i int) *user {
+		return &user{Role: "member"}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inserted != n {
+		t.Errorf("inserted %d rows, want %d", inserted, n)
+	}
+	// The rows are inserted in batches of BatchSize rows per transaction.
+	if begins := strings.Count(strings.Join(queries, "\n"), "BEGIN"); begins != 2 {
+		t.Errorf("inserted in %d transactions, want 2", begins)
+	}
+}
+
+func TestNWithoutTableName(t *testing.T) {
+	type row struct {
+		ID int `db:"id"`
+	}
+	err := NContext[row](context.Background(), nil, 1, nil)
+	if err == nil || !strings.Contains(err.Error(), "has no TableName method") {
+		t.Errorf("NContext() = %v, want an error about TableName", err)
+	}
+}
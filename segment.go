@@ -0,0 +1,97 @@
+package querier
+
+import "strings"
+
+// SegmentKind is the kind of a Segment.
+type SegmentKind int
+
+const (
+	// SegmentText is written by Write, Writef, Prepend, PreWrite, WriteNamed and BindStruct.
+	SegmentText SegmentKind = iota
+	// SegmentRaw is written by WriteRaw.
+	SegmentRaw
+	// SegmentIdent is written by WriteIdent and WriteTable.
+	SegmentIdent
+	// SegmentFields is written by WriteFields.
+	SegmentFields
+	// SegmentValues is written by WriteValues and WriteValueMap.
+	SegmentValues
+	// SegmentParams is added by AddParams.
+	SegmentParams
+	// SegmentQuery is the query of a composed Querier, e.g. by InsertFromSelect or Union.
+	SegmentQuery
+)
+
+// Segment is a structural piece of a query, see Q.Segments.
+type Segment struct {
+	Kind SegmentKind
+	// Clause is the clause keyword the text starts with, e.g. "WHERE" or "ORDER BY", if any.
+	Clause string
+	// Text is the written text, or the format of fields and values.
+	Text string
+	// Fields are the names of the written fields.
+	Fields []string
+	// Params are the parameters added by the segment.
+	Params []interface{}
+}
+
+// segment is a recorded Segment, its parameters are q.params[start:end].
+type segment struct {
+	kind       SegmentKind
+	text       string
+	fields     []Field
+	start, end int
+}
+
+// Segments returns the pieces the query was built from, in order, so tests and tools can assert on the structure of a
+// query rather than on its text, e.g.
+//
+//	for _, s := range q.Segments() {
+//		if s.Clause == "WHERE" { ... }
+//	}
+func (q *Q) Segments() []Segment {
+	segments := make([]Segment, len(q.segments))
+	for i, s := range q.segments {
+		segments[i] = Segment{
+			Kind:   s.kind,
+			Clause: leadingClause(s.text),
+			Text:   s.text,
+		}
+		if s.fields != nil {
+			segments[i].Fields = make([]string, len(s.fields))
+			for j := range s.fields {
+				segments[i].Fields[j] = s.fields[j].Name
+			}
+		}
+		if s.end > s.start {
+			segments[i].Params = append([]interface{}(nil), q.params[s.start:s.end]...)
+		}
+	}
+	return segments
+}
+
+// record records a segment whose parameters were added from offset start.
+func (q *Q) record(kind SegmentKind, text string, fields []Field, start int) {
+	q.segments = append(q.segments, segment{kind: kind, text: text, fields: fields, start: start, end: len(q.params)})
+}
+
+// leadingClause returns the clause keyword text starts with.
+func leadingClause(text string) string {
+	words := strings.Fields(strings.ToUpper(text))
+	for _, keyword := range clauseKeywords {
+		if len(words) < len(keyword) {
+			continue
+		}
+		match := true
+		for i := range keyword {
+			if words[i] != keyword[i] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return strings.Join(keyword, " ")
+		}
+	}
+	return ""
+}
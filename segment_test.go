@@ -0,0 +1,65 @@
+package querier
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSegments(t *testing.T) {
+	type user struct {
+		ID   int    `db:"id"`
+		Name string `db:"name"`
+	}
+	u := &user{ID: 1, Name: "foo"}
+	sub := New(nil, Default{}).Write("SELECT id FROM admin WHERE active = ?", true)
+	q := New(nil, Default{}).
+		Write("SELECT").
+		WriteFields("{name}", FieldSep, Fields(u).Select()...).
+		Write("FROM").
+		WriteIdent("user").
+		Write("WHERE id = ? AND").
+		AddParams(1).
+		WriteValueMap("{name} = {bindVar}", " AND ", Values(u), Fields(u).Only("name").Select()...).
+		Write("UNION").
+		WriteRaw(" ")
+	q.writeQ(sub)
+	q.Prepend("/* users */")
+
+	want := []Segment{
+		{Kind: SegmentText, Text: "/* users */"},
+		{Kind: SegmentText, Clause: "SELECT", Text: "SELECT"},
+		{Kind: SegmentFields, Text: "{name}", Fields: []string{"id", "name"}},
+		{Kind: SegmentText, Clause: "FROM", Text: "FROM"},
+		{Kind: SegmentIdent, Text: "user"},
+		{Kind: SegmentText, Clause: "WHERE", Text: "WHERE id = ? AND"},
+		{Kind: SegmentParams, Params: []interface{}{1}},
+		{Kind: SegmentValues, Text: "{name} = {bindVar}", Fields: []string{"name"}, Params: []interface{}{&u.Name}},
+		{Kind: SegmentText, Clause: "UNION", Text: "UNION"},
+		{Kind: SegmentRaw, Text: " "},
+		{Kind: SegmentQuery, Clause: "SELECT", Text: "SELECT id FROM admin WHERE active = ?", Params: []interface{}{true}},
+	}
+	if got := q.Segments(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got segments\n%+v\nwant\n%+v", got, want)
+	}
+
+	if got := q.Reset().Segments(); len(got) != 0 {
+		t.Errorf("got %d segments after Reset", len(got))
+	}
+}
+
+func TestSegmentsInsertStruct(t *testing.T) {
+	type user struct {
+		ID   int    `db:"id"`
+		Name string `db:"name"`
+	}
+	u := &user{ID: 1, Name: "foo"}
+	var clauses []string
+	for _, s := range New(nil, Default{}).InsertStruct("user", u).Segments() {
+		if s.Kind == SegmentFields || s.Kind == SegmentValues {
+			clauses = append(clauses, s.Text)
+		}
+	}
+	if want := []string{"{name}", "{bindVar}"}; !reflect.DeepEqual(clauses, want) {
+		t.Errorf("got %v, want %v", clauses, want)
+	}
+}
@@ -1,6 +1,7 @@
-package  querier
+package querier
 
 import (
+	"fmt"
 	"reflect"
 )
 
@@ -10,6 +11,38 @@ type Field struct {
 	Name string
 	// DataType is the field's data type.
 	DataType string
+	// Enum contains the allowed values if the field is an enum.
+	Enum []string
+	// Encrypted is true if the field is encrypted at rest.
+	Encrypted bool
+	// Sensitive is true if the field's value must not be logged.
+	Sensitive bool
+	// PrimaryKey is true if the field is part of the primary key.
+	PrimaryKey bool
+	// OmitEmpty is true if the field is left out of InsertStruct when it has its zero value.
+	OmitEmpty bool
+	// Computed is true if the field is computed by queries, e.g. an aggregate, rather than a column.
+	Computed bool
+	// Required is true if InsertStruct and UpdateStruct reject the field's zero value.
+	Required bool
+	// Generated is true if the column is generated by the database, so InsertStruct and UpdateStruct skip it.
+	Generated bool
+	// GeneratedAs is the expression of a generated column, from the field's generated tag, e.g. price * quantity. The
+	// migrator defines the column with it.
+	GeneratedAs string
+	// Default is the SQL expression that InsertStruct writes instead of the field's zero value, e.g. now() or
+	// DEFAULT. It's empty if the zero value is inserted.
+	Default string
+	// Sequence is the sequence that the field's values are taken from, its next value is the field's Default.
+	Sequence string
+	// IDGenerator is the name of the IDGenerator that InsertStruct generates the field's value with when it has its
+	// zero value, see RegisterIDGenerator.
+	IDGenerator string
+	// Unique is true if the field is part of a unique constraint, which the migrator creates.
+	Unique bool
+	// UniqueConstraint is the name of the field's unique constraint, empty if it's named after the table and column,
+	// see UniqueConstraintName.
+	UniqueConstraint string
 }
 
 // FieldSelector selects struct fields from a struct type and builds a Field slice.
@@ -73,12 +106,25 @@ func (s *FieldSelector) SetDialect(d Dialect) *FieldSelector {
 }
 
 func (s *FieldSelector) Select() []Field {
-	return makeFieldSlice(s.t, nil, s.d, s.filterSet, s.filterExclude)
+	return makeFieldSlice(s.t, nil, s.d, "", s.filterSet, s.filterExclude)
 }
 
 type ValueMap map[string]reflect.Value
 
+// Values returns the ValueMap of the struct i points to, e.g. to bind its fields with WriteValueMap. The fields of a
+// nil embedded struct pointer are missing from it, i isn't changed.
 func Values(i interface{}) ValueMap {
+	return mapValues(structValue(i), nil, "", false, nil)
+}
+
+// ScanValues is Values of a struct to scan or decode into, nil embedded struct pointers are allocated so their fields
+// are in the ValueMap.
+func ScanValues(i interface{}) ValueMap {
+	return makeValueMap(structValue(i), nil, "")
+}
+
+// structValue returns the struct i points to.
+func structValue(i interface{}) reflect.Value {
 	v := reflect.ValueOf(i)
 	if v.Kind() != reflect.Ptr {
 		panic("argument i is not a pointer")
@@ -87,7 +133,7 @@ func Values(i interface{}) ValueMap {
 	if v.Kind() != reflect.Struct {
 		panic("argument i is not a pointer to a struct")
 	}
-	return makeValueMap(v, nil)
+	return v
 }
 
 var ignore interface{}
@@ -126,7 +172,13 @@ func (m ValueMap) MapToFields(fields []Field, values []interface{}) []interface{
 	return values
 }
 
-func makeFieldSlice(t reflect.Type, fields []Field, d Dialect, filterSet map[string]struct{}, filterExclude bool) []Field {
+func makeFieldSlice(t reflect.Type, fields []Field, d Dialect, prefix string, filterSet map[string]struct{}, filterExclude bool) []Field {
+	return appendFields(t, fields, d, prefix, filterSet, filterExclude, nil)
+}
+
+// appendFields appends the fields of t to fields, path contains the structs that embed t.
+func appendFields(t reflect.Type, fields []Field, d Dialect, prefix string, filterSet map[string]struct{}, filterExclude bool, path []reflect.Type) []Field {
+	path = enterStruct(path, t)
 	numField := t.NumField()
 	if fields == nil {
 		fields = make([]Field, 0, numField)
@@ -134,17 +186,22 @@ func makeFieldSlice(t reflect.Type, fields []Field, d Dialect, filterSet map[str
 
 	for i := 0; i < numField; i++ {
 		cur := t.Field(i)
-		name, dataType, ignore, inlineStruct := extractFieldInfo(&cur, d)
+		fi := extractFieldInfo(&cur, d)
 
-		if ignore {
+		if fi.ignore {
 			// Skip this field.
 			continue
 		}
-		if inlineStruct {
+		if fi.inlineStruct {
 			// Flatten this inline struct.
-			fields = makeFieldSlice(cur.Type, fields, d, filterSet, filterExclude)
+			inlineType := cur.Type
+			if inlineType.Kind() == reflect.Ptr {
+				inlineType = inlineType.Elem()
+			}
+			fields = appendFields(inlineType, fields, d, prefix+fi.prefix, filterSet, filterExclude, path)
 			continue
 		}
+		name := prefix + fi.name
 		if filterSet != nil {
 			_, inSet := filterSet[name]
 			// If filter mode is include and field is in set then procceed.
@@ -157,17 +214,61 @@ func makeFieldSlice(t reflect.Type, fields []Field, d Dialect, filterSet map[str
 			}
 		}
 
-		fields = append(fields, Field{
-			Name:     name,
-			DataType: dataType,
+		field := Field{
+			Name:       name,
+			DataType:   fi.dataType,
+			Enum:       fi.tag.enumValues(),
+			Encrypted:  fi.tag.HasOption(tagOptionEncrypted),
+			Sensitive:  fi.tag.HasOption(tagOptionSensitive),
+			PrimaryKey: fi.tag.HasOption(tagOptionPrimaryKey),
+			OmitEmpty:  fi.tag.HasOption(tagOptionOmitEmpty),
+			Computed:   fi.tag.HasOption(tagOptionComputed),
+			Required:   fi.tag.HasOption(tagOptionRequired),
+			Generated:  fi.tag.HasOption(tagOptionGenerated),
+			Unique:     fi.tag.HasOption(tagOptionUnique),
+		}
+		if field.Generated {
+			field.GeneratedAs = cur.Tag.Get(tagOptionGenerated)
+		}
+		if fi.tag.HasOption(tagOptionDefault) {
+			field.Default = fi.tag.Options[tagOptionDefault]
+			if field.Default == "" {
+				field.Default = "DEFAULT"
+			}
+		}
+		field.IDGenerator = fi.tag.Options[tagOptionID]
+		field.UniqueConstraint = fi.tag.Options[tagOptionUnique]
+		if field.Sequence = fi.tag.Options[tagOptionSequence]; field.Sequence != "" && field.Default == "" {
+			field.Default = nextValue(d, field.Sequence)
+		}
+		if field.Encrypted && fi.tag.DataType == "" && d != nil {
+			// The field is stored as ciphertext.
+			field.DataType, _ = d.TypeMapper(reflectTypeByteSlice)
+		}
+		if field.Enum != nil && fi.tag.DataType == "" && d != nil {
+			field.DataType = enumDataType(d, name, field.DataType, field.Enum)
+		}
+		customTagOptions(&fi.tag, func(option TagOption, value string) {
+			if option.Field != nil {
+				option.Field(&field, value)
+			}
 		})
+		fields = append(fields, field)
 	}
 
 	return fields
 }
 
-func makeValueMap(v reflect.Value, values ValueMap) ValueMap {
+// makeValueMap returns the ValueMap of v to scan into, nil embedded struct pointers are allocated.
+func makeValueMap(v reflect.Value, values ValueMap, prefix string) ValueMap {
+	return mapValues(v, values, prefix, true, nil)
+}
+
+// mapValues adds the fields of struct v to values. Nil embedded struct pointers are allocated if alloc is true, their
+// fields are skipped otherwise. path contains the structs that embed v.
+func mapValues(v reflect.Value, values ValueMap, prefix string, alloc bool, path []reflect.Type) ValueMap {
 	t := v.Type()
+	path = enterStruct(path, t)
 	numField := t.NumField()
 	if values == nil {
 		values = make(ValueMap)
@@ -175,21 +276,51 @@ func makeValueMap(v reflect.Value, values ValueMap) ValueMap {
 
 	for i := 0; i < numField; i++ {
 		cur := t.Field(i)
-		name, _, ignore, inlineStruct := extractFieldInfo(&cur, nil)
+		fi := extractFieldInfo(&cur, nil)
 
-		if ignore {
+		if fi.ignore {
 			// Skip this field.
 			continue
 		}
 
 		fieldValue := v.Field(i)
-		if inlineStruct {
+		if fi.inlineStruct {
+			if fieldValue.Kind() == reflect.Ptr {
+				if fieldValue.IsNil() {
+					if !alloc {
+						continue
+					}
+					// Allocate the inline struct so it can be scanned into.
+					fieldValue.Set(reflect.New(fieldValue.Type().Elem()))
+				}
+				fieldValue = fieldValue.Elem()
+			}
 			// Flatten this inline struct.
-			makeValueMap(fieldValue, values)
+			mapValues(fieldValue, values, prefix+fi.prefix, alloc, path)
+		} else if fi.tag.HasOption(tagOptionEncrypted) {
+			// Encrypt and decrypt this field transparently.
+			name := prefix + fi.name
+			values[name] = reflect.ValueOf(&encryptedField{name: name, v: fieldValue}).Elem()
 		} else {
-			values[name] = fieldValue
+			customTagOptions(&fi.tag, func(option TagOption, value string) {
+				if option.Value != nil {
+					fieldValue = option.Value(fieldValue, value)
+				}
+			})
+			values[prefix+fi.name] = fieldValue
 		}
 	}
 
 	return values
 }
+
+// enterStruct returns path with t appended, it panics if t is in path already: a struct that embeds a pointer to itself
+// can't be flattened.
+func enterStruct(path []reflect.Type, t reflect.Type) []reflect.Type {
+	for _, embedder := range path {
+		if embedder == t {
+			panic(fmt.Sprintf("embedded struct %s embeds itself", t))
+		}
+	}
+	return append(path, t)
+}
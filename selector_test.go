@@ -1,6 +1,10 @@
-package  querier
+package querier
 
-import "testing"
+import (
+	"database/sql/driver"
+	"reflect"
+	"testing"
+)
 
 type fieldsModel struct {
 	ID        int
@@ -42,6 +46,181 @@ func TestFieldsExcept(t *testing.T) {
 	checkFieldSelection(t, s, want)
 }
 
+func TestFieldsInlinePrefix(t *testing.T) {
+	type address struct {
+		Street string
+		City   string
+	}
+	type model struct {
+		ID       int
+		Home     address `db:"home_,inline"`
+		Work     address `db:"work_,,inline"`
+		Squashed address `db:"ignored_,inline,squash"`
+	}
+
+	want := map[string]bool{
+		"ID":          false,
+		"home_Street": false,
+		"home_City":   false,
+		"work_Street": false,
+		"work_City":   false,
+		"Street":      false,
+		"City":        false,
+	}
+	checkFieldSelection(t, Fields(&model{}), want)
+
+	var m model
+	values := Values(&m)
+	if len(values) != len(want) {
+		t.Errorf("Values() mapped %d fields, want %d", len(values), len(want))
+	}
+	values["work_City"].SetString("Amsterdam")
+	if m.Work.City != "Amsterdam" {
+		t.Errorf("Values()[%q] is not mapped to Work.City", "work_City")
+	}
+}
+
+func TestFieldsInlinePointer(t *testing.T) {
+	type BaseModel struct {
+		ID        int
+		CreatedAt string
+	}
+	type model struct {
+		*BaseModel
+		Name string
+	}
+
+	want := map[string]bool{
+		"ID":        false,
+		"CreatedAt": false,
+		"Name":      false,
+	}
+	checkFieldSelection(t, Fields(&model{}), want)
+
+	// Binding doesn't change the struct.
+	var m model
+	values := Values(&m)
+	if m.BaseModel != nil {
+		t.Error("Values() allocated the embedded *BaseModel")
+	}
+	if _, ok := values["ID"]; ok || len(values) != 1 {
+		t.Errorf("Values() = %v, want only Name", values)
+	}
+
+	// Scanning allocates it.
+	values = makeValueMap(reflect.ValueOf(&m).Elem(), nil, "")
+	if m.BaseModel == nil {
+		t.Fatal("makeValueMap() did not allocate the embedded *BaseModel")
+	}
+	values["ID"].SetInt(42)
+	if m.ID != 42 {
+		t.Errorf("makeValueMap()[%q] is not mapped to BaseModel.ID", "ID")
+	}
+	if Values(&m)["ID"].Int() != 42 {
+		t.Errorf("Values()[%q] is not mapped to BaseModel.ID", "ID")
+	}
+}
+
+func TestFirstInlinePointer(t *testing.T) {
+	type BaseModel struct {
+		ID int64
+	}
+	type model struct {
+		*BaseModel
+		Name string
+	}
+	db := openTestDB(t, func(string, []driver.Value) (*testRows, error) {
+		return &testRows{columns: []string{"ID", "Name"}, values: [][]driver.Value{{int64(42), "foo"}}}, nil
+	})
+	defer db.Close()
+
+	var m model
+	if err := New(db, Default{}).Write("SELECT * FROM user").First(&m); err != nil {
+		t.Fatal(err)
+	}
+	if m.BaseModel == nil || m.ID != 42 || m.Name != "foo" {
+		t.Errorf("First() scanned %+v", m)
+	}
+}
+
+type linkedNode struct {
+	ID   int64
+	Next *linkedNode
+}
+
+type EmbeddingNode struct {
+	*EmbeddingNode
+	ID int64
+}
+
+func TestFieldsRecursive(t *testing.T) {
+	// A pointer to a struct that isn't embedded is a column, not flattened.
+	values := Values(&linkedNode{})
+	if _, ok := values["Next"]; !ok || len(values) != 2 {
+		t.Errorf("Values() = %v, want ID and Next", values)
+	}
+	fields := Fields(&linkedNode{}).SetDialect(nil).Select()
+	if len(fields) != 2 || fields[1].Name != "Next" {
+		t.Errorf("Select() = %v, want ID and Next", fields)
+	}
+
+	// The Dialect has no data type for it, and a struct embedding a pointer to itself can't be flattened.
+	for name, fn := range map[string]func(){
+		"Select linked":    func() { Fields(&linkedNode{}).Select() },
+		"Select embedding": func() { Fields(&EmbeddingNode{}).Select() },
+		"makeValueMap":     func() { makeValueMap(reflect.ValueOf(&EmbeddingNode{}).Elem(), nil, "") },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("%s did not panic", name)
+				}
+			}()
+			fn()
+		}()
+	}
+}
+
+type unexportedBase struct {
+	ID        int
+	createdAt string
+}
+
+type unexportedModel struct {
+	unexportedBase
+	*unexportedBase2
+	Name   string
+	secret string `db:"secret"`
+	count  int
+}
+
+type unexportedBase2 struct {
+	Version int
+}
+
+func TestFieldsUnexported(t *testing.T) {
+	want := map[string]bool{
+		"ID":   false,
+		"Name": false,
+	}
+	checkFieldSelection(t, Fields(&unexportedModel{}), want)
+
+	db := openTestDB(t, func(string, []driver.Value) (*testRows, error) {
+		return &testRows{
+			columns: []string{"ID", "Name", "secret", "count", "createdAt", "Version"},
+			values:  [][]driver.Value{{int64(1), "foo", "bar", int64(2), "now", int64(3)}},
+		}, nil
+	})
+	defer db.Close()
+	var m unexportedModel
+	if err := New(db, Default{}).Write("SELECT * FROM user").First(&m); err != nil {
+		t.Fatal(err)
+	}
+	if m.ID != 1 || m.Name != "foo" || m.secret != "" || m.count != 0 || m.createdAt != "" || m.unexportedBase2 != nil {
+		t.Errorf("First() = %+v, want only the exported fields scanned", m)
+	}
+}
+
 func checkFieldSelection(t *testing.T, s *FieldSelector, want map[string]bool) {
 	for _, field := range s.Select() {
 		alreadySelected, inSet := want[field.Name]
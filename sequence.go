@@ -0,0 +1,52 @@
+package querier
+
+import "context"
+
+// tagOptionSequence sets the sequence that InsertStruct takes the value of a field with its zero value from, e.g.
+// `db:"id,,pk,seq=orders_seq"`.
+const tagOptionSequence = "seq"
+
+// Sequencer can be implemented by a Dialect to use sequences with another syntax than the SQL standard's, e.g.
+// nextval('name') of PostgreSQL or name.NEXTVAL of Oracle. Dialects that don't implement it use the default, NEXT
+// VALUE FOR name.
+type Sequencer interface {
+	// NextValue returns an expression of the next value of sequence name.
+	NextValue(name string) string
+	// NextValueQuery returns a query of the next value of sequence name, e.g. SELECT name.NEXTVAL FROM dual.
+	NextValueQuery(name string) string
+}
+
+// NextValue returns NEXT VALUE FOR name.
+func (Default) NextValue(name string) string {
+	return "NEXT VALUE FOR " + name
+}
+
+// NextValueQuery returns SELECT NEXT VALUE FOR name.
+func (d Default) NextValueQuery(name string) string {
+	return "SELECT " + d.NextValue(name)
+}
+
+// nextValue returns the expression of the next value of sequence name using d.
+func nextValue(d Dialect, name string) string {
+	s, ok := d.(Sequencer)
+	if !ok {
+		s = Default{}
+	}
+	return s.NextValue(name)
+}
+
+// NextVal returns the next value of sequence name, e.g. to know the ID of a row before it's inserted:
+//
+//	id, err := db.NextVal(ctx, "orders_seq")
+//
+// Fields tagged with a sequence, e.g. `db:"id,,pk,seq=orders_seq"`, are inserted by InsertStruct with the next value
+// of their sequence when they have their zero value. The migrator creates the sequences of models.
+func (db *DB) NextVal(ctx context.Context, name string) (int64, error) {
+	s, ok := db.d.(Sequencer)
+	if !ok {
+		s = Default{}
+	}
+	var n int64
+	err := db.New().Write(s.NextValueQuery(name)).ScanContext(ctx, &n)
+	return n, err
+}
@@ -0,0 +1,53 @@
+package querier
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+)
+
+// pgSequenceDialect uses the sequence syntax of PostgreSQL.
+type pgSequenceDialect struct {
+	Default
+}
+
+func (pgSequenceDialect) NextValue(name string) string {
+	return "nextval('" + name + "')"
+}
+
+func (d pgSequenceDialect) NextValueQuery(name string) string {
+	return "SELECT " + d.NextValue(name)
+}
+
+func TestSequence(t *testing.T) {
+	type order struct {
+		ID   int64  `db:"id,,pk,seq=orders_seq"`
+		Name string `db:"name"`
+	}
+	q := New(nil, Default{}).InsertStruct("orders", &order{Name: "foo"})
+	if want := "INSERT INTO orders (name, id) VALUES (?, NEXT VALUE FOR orders_seq)"; q.String() != want {
+		t.Errorf("String() = %q, want %q", q.String(), want)
+	}
+	q = New(nil, pgSequenceDialect{}).InsertStruct("orders", &order{Name: "foo"})
+	if want := "INSERT INTO orders (name, id) VALUES (?, nextval('orders_seq'))"; q.String() != want {
+		t.Errorf("String() = %q, want %q", q.String(), want)
+	}
+	q = New(nil, Default{}).InsertStruct("orders", &order{ID: 7, Name: "foo"})
+	if want := "INSERT INTO orders (id, name) VALUES (?, ?)"; q.String() != want {
+		t.Errorf("String() = %q, want %q", q.String(), want)
+	}
+
+	var queries []string
+	db := NewDB(openTestDB(t, func(query string, _ []driver.Value) (*testRows, error) {
+		queries = append(queries, query)
+		return &testRows{columns: []string{"nextval"}, values: [][]driver.Value{{int64(42)}}}, nil
+	}), pgSequenceDialect{})
+	defer db.Close()
+	n, err := db.NextVal(context.Background(), "orders_seq")
+	if err != nil || n != 42 {
+		t.Errorf("NextVal() = %d, %v, want 42", n, err)
+	}
+	if len(queries) != 1 || queries[0] != "SELECT nextval('orders_seq')" {
+		t.Errorf("queries = %q", queries)
+	}
+}
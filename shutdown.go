@@ -0,0 +1,80 @@
+package querier
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrShutdown is returned when a query of a DB is executed after Shutdown has been called.
+var ErrShutdown = errors.New("database is shut down")
+
+// tracker tracks the executions of an Executor.
+type tracker interface {
+	// track starts an execution, the returned function must be called when it's finished.
+	track() (done func(), err error)
+}
+
+// track starts tracking an execution of the query if its Executor is a tracker. The execution of a named query also
+// takes a slot of its concurrency limit, see SetConcurrencyLimit.
+func (q *Q) track() (func(), error) {
+	done := func() {}
+	if t, ok := q.ex.(tracker); ok {
+		var err error
+		if done, err = t.track(); err != nil {
+			return nil, err
+		}
+	}
+	if q.name == "" {
+		return done, nil
+	}
+	ctx := q.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	release, err := acquireSlot(ctx, q.name)
+	if err != nil {
+		done()
+		return nil, err
+	}
+	return func() {
+		release()
+		done()
+	}, nil
+}
+
+func (db *DB) track() (func(), error) {
+	db = db.rootDB()
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.shutdown {
+		return nil, ErrShutdown
+	}
+	db.inflight.Add(1)
+	return db.inflight.Done, nil
+}
+
+// Shutdown stops executing new queries created by New, they return ErrShutdown. It waits until the queries in flight
+// have finished, or until ctx is done, and then closes the database. It returns the context's error if the queries
+// didn't finish in time.
+func (db *DB) Shutdown(ctx context.Context) error {
+	db = db.rootDB()
+	db.mu.Lock()
+	db.shutdown = true
+	db.mu.Unlock()
+
+	finished := make(chan struct{})
+	go func() {
+		db.inflight.Wait()
+		close(finished)
+	}()
+	var err error
+	select {
+	case <-finished:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	if closeErr := db.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
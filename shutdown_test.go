@@ -0,0 +1,73 @@
+package querier
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+)
+
+func TestShutdown(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	db := NewDB(openTestDB(t, func(string, []driver.Value) (*testRows, error) {
+		close(started)
+		<-release
+		return nil, nil
+	}), Default{})
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- db.New().Write("DELETE FROM user").Exec()
+	}()
+	<-started
+
+	shutdown := make(chan error, 1)
+	go func() {
+		shutdown <- db.Shutdown(context.Background())
+	}()
+	// Wait for Shutdown to stop accepting new queries.
+	for {
+		db.mu.Lock()
+		stopped := db.shutdown
+		db.mu.Unlock()
+		if stopped {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if err := db.New().Write("DELETE FROM user").Exec(); err != ErrShutdown {
+		t.Errorf("Exec() after Shutdown = %v, want %v", err, ErrShutdown)
+	}
+	select {
+	case err := <-shutdown:
+		t.Fatalf("Shutdown() returned %v before the query in flight finished", err)
+	default:
+	}
+
+	close(release)
+	if err := <-errc; err != nil {
+		t.Errorf("query in flight returned %v", err)
+	}
+	if err := <-shutdown; err != nil {
+		t.Errorf("Shutdown() = %v", err)
+	}
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	db := NewDB(openTestDB(t, func(string, []driver.Value) (*testRows, error) {
+		close(started)
+		<-release
+		return nil, nil
+	}), Default{})
+
+	go db.New().Write("DELETE FROM user").Exec()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := db.Shutdown(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Shutdown() = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,155 @@
+package querier
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// snapshot is the snapshot of a table, see Snapshot.
+type snapshot struct {
+	Table   string             `json:"table"`
+	Rows    [][]*recordedValue `json:"rows"`
+	Columns []string           `json:"columns"`
+}
+
+// Snapshot writes the rows of tables to dir, a JSON file per table named after the table, e.g. testdata/user.json, so
+// integration tests can reset the tables to known fixtures with Restore:
+//
+//	err := db.Snapshot(ctx, "testdata/fixtures", "user", "order")
+//
+// The values keep their type like the recordings of a Recorder, so a snapshot is portable between databases. The
+// rows are streamed to the files, the directory is created if needed.
+func (db *DB) Snapshot(ctx context.Context, dir string, tables ...string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for _, table := range tables {
+		if err := db.snapshotTable(ctx, filepath.Join(dir, table+".json"), table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (db *DB) snapshotTable(ctx context.Context, path, table string) (err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	if _, err = w.WriteString(`{"table":`); err != nil {
+		return err
+	}
+	if err = enc.Encode(table); err != nil {
+		return err
+	}
+	w.WriteString(`,"rows":[`)
+
+	var (
+		columns []string
+		dest    []interface{}
+	)
+	q := db.New()
+	err = q.Write("SELECT * FROM").WriteTable(table).ForEachContext(ctx, func(q *Q, rows *sql.Rows) error {
+		if columns == nil {
+			var err error
+			if columns, err = rows.Columns(); err != nil {
+				return err
+			}
+			dest = make([]interface{}, len(columns))
+		}
+		values := make([]interface{}, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+		row := make([]*recordedValue, len(values))
+		for i, v := range values {
+			row[i] = newRecordedValue(v)
+		}
+		if q.RowIndex() > 0 {
+			w.WriteByte(',')
+		}
+		return enc.Encode(row)
+	})
+	if err != nil {
+		return err
+	}
+	w.WriteString(`],"columns":`)
+	if err = enc.Encode(columns); err != nil {
+		return err
+	}
+	w.WriteString("}\n")
+	return w.Flush()
+}
+
+// Restore replaces the rows of tables with their snapshots in dir, see Snapshot. The rows are deleted in reverse order,
+// so tables referring to earlier ones go first, and the snapshots are inserted in order with multi-row INSERT
+// statements. This is done in one transaction if the database supports transactions.
+func (db *DB) Restore(ctx context.Context, dir string, tables ...string) (err error) {
+	snapshots := make([]snapshot, len(tables))
+	for i, table := range tables {
+		data, err := ioutil.ReadFile(filepath.Join(dir, table+".json"))
+		if err != nil {
+			return err
+		}
+		if err = json.Unmarshal(data, &snapshots[i]); err != nil {
+			return err
+		}
+	}
+
+	var ex Executor = db
+	if Supports(db.d, FeatureTransactions) {
+		tx, err := Begin(ctx, db.DB, nil)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err != nil {
+				tx.Rollback()
+			} else {
+				err = tx.Commit()
+			}
+		}()
+		ex = tx
+	}
+	q := New(ex, db.d).SetLogger(db.logger).SetSchema(db.schema)
+	for i := len(tables) - 1; i >= 0; i-- {
+		if err = q.New().Write("DELETE FROM").WriteTable(tables[i]).ExecContext(ctx); err != nil {
+			return err
+		}
+	}
+	for i, s := range snapshots {
+		rows := make([][]interface{}, len(s.Rows))
+		for j, row := range s.Rows {
+			rows[j] = make([]interface{}, len(row))
+			for k, v := range row {
+				rows[j][k] = v.value()
+			}
+		}
+		for len(rows) > 0 {
+			n := len(rows)
+			if n > DefaultTransferBatchSize {
+				n = DefaultTransferBatchSize
+			}
+			if err = insertRows(ctx, q.New(), tables[i], s.Columns, rows[:n]); err != nil {
+				return err
+			}
+			rows = rows[n:]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package querier
+
+import (
+	"context"
+	"database/sql/driver"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "querier")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	created := time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC)
+	var (
+		queries []string
+		args    [][]driver.Value
+	)
+	db := NewDB(openTestDB(t, func(query string, a []driver.Value) (*testRows, error) {
+		switch {
+		case strings.HasPrefix(query, `SELECT * FROM "user"`):
+			return &testRows{columns: []string{"id", "name", "created_at"}, values: [][]driver.Value{
+				{int64(1), "a", created}, {int64(2), nil, created},
+			}}, nil
+		case strings.HasPrefix(query, "SELECT"):
+			return &testRows{columns: []string{"id"}}, nil
+		}
+		queries, args = append(queries, query), append(args, a)
+		return nil, nil
+	}), Default{})
+
+	if err := db.Snapshot(context.Background(), dir, "user", "empty"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Restore(context.Background(), dir, "user", "empty"); err != nil {
+		t.Fatal(err)
+	}
+	wantQueries := []string{
+		`DELETE FROM "empty"`,
+		`DELETE FROM "user"`,
+		"INSERT INTO user (id, name, created_at) VALUES (?, ?, ?), (?, ?, ?)",
+	}
+	if !reflect.DeepEqual(queries, wantQueries) {
+		t.Errorf("executed %q, want %q", queries, wantQueries)
+	}
+	wantArgs := []driver.Value{int64(1), "a", created, int64(2), nil, created}
+	if len(args) != 3 || !reflect.DeepEqual(args[2], wantArgs) {
+		t.Errorf("args = %v, want %v", args, wantArgs)
+	}
+}
@@ -0,0 +1,134 @@
+package querier
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// GeomFromText is a format for WriteValues and WriteValueMap that binds geometry values, e.g. a Point, which are bound
+// as WKT.
+const GeomFromText = "ST_GeomFromText({bindVar})"
+
+const (
+	wkbPoint      = 1
+	wkbPointLen   = 21
+	ewkbSRIDFlag  = 0x20000000
+	mysqlSRIDLen  = 4
+	wktPointStart = "POINT("
+)
+
+var errInvalidPoint = errors.New("invalid point")
+
+// Point is a spatial point. It scans WKT, WKB, MySQL's internal geometry format and PostGIS' (hex) EWKB, and it is
+// bound as WKT; use GeomFromText as format to convert it to a geometry.
+type Point struct {
+	X, Y float64
+}
+
+// String returns the WKT of the point.
+func (p Point) String() string {
+	return wktPointStart + strconv.FormatFloat(p.X, 'f', -1, 64) + Space + strconv.FormatFloat(p.Y, 'f', -1, 64) + ")"
+}
+
+// Value implements driver.Valuer.
+func (p Point) Value() (driver.Value, error) {
+	return p.String(), nil
+}
+
+// Scan implements sql.Scanner.
+func (p *Point) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case []byte:
+		if len(src) == mysqlSRIDLen+wkbPointLen && !isEWKB(src) {
+			// MySQL prefixes the WKB with the SRID.
+			return p.UnmarshalWKB(src[mysqlSRIDLen:])
+		}
+		if len(src) > 0 && (src[0] == 0 || src[0] == 1) {
+			return p.UnmarshalWKB(src)
+		}
+		return p.scanText(string(src))
+	case string:
+		return p.scanText(src)
+	}
+	return fmt.Errorf("cannot scan %T into Point", src)
+}
+
+func (p *Point) scanText(s string) error {
+	if strings.HasPrefix(strings.ToUpper(s), wktPointStart) {
+		return p.UnmarshalWKT(s)
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return errInvalidPoint
+	}
+	return p.UnmarshalWKB(b)
+}
+
+// WKB returns the little endian WKB of the point.
+func (p Point) WKB() []byte {
+	b := make([]byte, wkbPointLen)
+	b[0] = 1
+	binary.LittleEndian.PutUint32(b[1:], wkbPoint)
+	binary.LittleEndian.PutUint64(b[5:], math.Float64bits(p.X))
+	binary.LittleEndian.PutUint64(b[13:], math.Float64bits(p.Y))
+	return b
+}
+
+// UnmarshalWKB decodes the WKB or EWKB b into the point.
+func (p *Point) UnmarshalWKB(b []byte) error {
+	if len(b) < wkbPointLen {
+		return errInvalidPoint
+	}
+	var order binary.ByteOrder = binary.LittleEndian
+	if b[0] == 0 {
+		order = binary.BigEndian
+	}
+	typ := order.Uint32(b[1:])
+	if typ&ewkbSRIDFlag != 0 {
+		// Skip the SRID of EWKB.
+		typ &^= ewkbSRIDFlag
+		b = append(b[:5:5], b[9:]...)
+	}
+	if typ != wkbPoint || len(b) != wkbPointLen {
+		return errInvalidPoint
+	}
+	p.X = math.Float64frombits(order.Uint64(b[5:]))
+	p.Y = math.Float64frombits(order.Uint64(b[13:]))
+	return nil
+}
+
+// isEWKB returns true if b starts with an EWKB header containing a SRID.
+func isEWKB(b []byte) bool {
+	switch b[0] {
+	case 0:
+		return binary.BigEndian.Uint32(b[1:])&ewkbSRIDFlag != 0
+	case 1:
+		return binary.LittleEndian.Uint32(b[1:])&ewkbSRIDFlag != 0
+	}
+	return false
+}
+
+// UnmarshalWKT decodes the WKT s, e.g. "POINT(1 2)", into the point.
+func (p *Point) UnmarshalWKT(s string) (err error) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(strings.ToUpper(s), wktPointStart) || !strings.HasSuffix(s, ")") {
+		return errInvalidPoint
+	}
+	coords := strings.Fields(s[len(wktPointStart) : len(s)-1])
+	if len(coords) != 2 {
+		return errInvalidPoint
+	}
+	if p.X, err = strconv.ParseFloat(coords[0], 64); err != nil {
+		return errInvalidPoint
+	}
+	if p.Y, err = strconv.ParseFloat(coords[1], 64); err != nil {
+		return errInvalidPoint
+	}
+	return nil
+}
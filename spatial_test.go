@@ -0,0 +1,55 @@
+package querier
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestPointScan(t *testing.T) {
+	want := Point{X: 4.5, Y: -52.25}
+	wkb := want.WKB()
+	ewkb := append([]byte{1, 1, 0, 0, 0x20, 0xe6, 0x10, 0, 0}, wkb[5:]...)
+
+	tests := []struct {
+		name string
+		in   interface{}
+	}{
+		{"WKT", "POINT(4.5 -52.25)"},
+		{"WKT bytes", []byte("POINT(4.5 -52.25)")},
+		{"WKB", wkb},
+		{"MySQL", append([]byte{0xe6, 0x10, 0, 0}, wkb...)},
+		{"EWKB", ewkb},
+		{"hex EWKB", hex.EncodeToString(ewkb)},
+	}
+
+	for _, tt := range tests {
+		var got Point
+		if err := got.Scan(tt.in); err != nil {
+			t.Errorf("Scan(%s) error: %v", tt.name, err)
+		} else if got != want {
+			t.Errorf("Scan(%s) = %v, want %v", tt.name, got, want)
+		}
+	}
+
+	var p Point
+	for _, in := range []interface{}{"POINT(1)", []byte{1, 2, 3}, 42} {
+		if err := p.Scan(in); err == nil {
+			t.Errorf("Scan(%v) expected error", in)
+		}
+	}
+}
+
+func TestPointValue(t *testing.T) {
+	v, err := Point{X: 1, Y: 2.5}.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != "POINT(1 2.5)" {
+		t.Errorf("Value() = %v, want %q", v, "POINT(1 2.5)")
+	}
+
+	q := New(nil, Default{}).WriteValues(GeomFromText, FieldSep, Point{X: 1, Y: 2})
+	if q.String() != "ST_GeomFromText(?)" {
+		t.Errorf("WriteValues(GeomFromText) = %q", q.String())
+	}
+}
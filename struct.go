@@ -0,0 +1,170 @@
+package querier
+
+import "reflect"
+
+const (
+	// tagOptionPrimaryKey marks a field as part of the primary key, e.g. `db:"id,,pk"`. UpdateStruct and DeleteStruct
+	// identify the row by its primary key fields.
+	tagOptionPrimaryKey = "pk"
+	// tagOptionOmitEmpty leaves a field out of InsertStruct when it has its zero value, e.g. `db:"created_at,omitempty"`,
+	// so the column's default is used.
+	tagOptionOmitEmpty = "omitempty"
+	// tagOptionComputed marks a field that is computed by queries, e.g. `db:"total_count,computed"` for an aggregate. It
+	// is scanned, but it is not a column, so InsertStruct, UpdateStruct and the migrator skip it.
+	tagOptionComputed = "computed"
+	// tagOptionGenerated marks a column generated by the database, e.g. `db:"total,,generated"`. It is scanned, but
+	// InsertStruct and UpdateStruct skip it. The expression can be set in the generated tag for the migrator, e.g.
+	// `generated:"price * quantity"`.
+	tagOptionGenerated = "generated"
+	// tagOptionDefault sets the SQL expression that InsertStruct writes for a field with its zero value, e.g.
+	// `db:"created_at,,default=now()"`. Without a value, DEFAULT is written, so the column's default is used.
+	tagOptionDefault = "default"
+)
+
+// InsertStruct writes an INSERT statement of the fields of struct i into table, e.g.
+//
+//	err := q.InsertStruct("user", &user).Exec()
+//
+// Fields with an IDGenerator that have their zero value are set to a new ID first.
+func (q *Q) InsertStruct(table string, i interface{}) *Q {
+	fields := q.Fields(i).Freeze().Fields()
+	values := Values(i)
+	if err := generateIDs(fields, values); err != nil {
+		q.fail(err)
+	}
+	if err := validateStruct(i, Columns(fields), values); err != nil {
+		q.fail(err)
+	}
+	fields, defaults := splitDefaults(writableColumns(fields), values)
+	fields = omitEmpty(fields, values)
+	columns := append(fields[:len(fields):len(fields)], defaults...)
+	q.Writef("INSERT INTO %s (", q.Qualify(table))
+	q.writeFormat("{name}", FieldSep, columns, len(columns))
+	q.record(SegmentFields, "{name}", columns, len(q.params))
+	q.WriteRaw(") VALUES (")
+	q.writeValueMap("{bindVar}", FieldSep, values, fields)
+	for j, field := range defaults {
+		if j > 0 || len(fields) > 0 {
+			q.WriteRaw(FieldSep)
+		}
+		q.WriteRaw(field.Default)
+	}
+	q.WriteRaw(")")
+	q.mapConstraints(table, columns)
+	q.audit(AuditInsert, table, i, fields, values)
+	return q
+}
+
+// UpdateStruct writes an UPDATE statement that sets the fields of struct i in table, of the row identified by the
+// primary key fields of i. Panics when i has no primary key fields.
+func (q *Q) UpdateStruct(table string, i interface{}) *Q {
+	fields := Columns(q.Fields(i).Freeze().Fields())
+	values := Values(i)
+	if err := validateStruct(i, fields, values); err != nil {
+		q.fail(err)
+	}
+	keys, rest := splitPrimaryKey(writableColumns(fields))
+	q.Writef("UPDATE %s SET", q.Qualify(table)).
+		WriteValueMap("{name} = {bindVar}", FieldSep, values, rest...).
+		Write("WHERE").
+		WriteValueMap("{name} = {bindVar}", " AND ", values, keys...)
+	q.mapConstraints(table, fields)
+	q.audit(AuditUpdate, table, i, fields, values)
+	return q
+}
+
+// DeleteStruct writes a DELETE statement of the row in table identified by the primary key fields of struct i. Panics
+// when i has no primary key fields.
+func (q *Q) DeleteStruct(table string, i interface{}) *Q {
+	fields := q.Fields(i).Freeze().Fields()
+	values := Values(i)
+	keys, _ := splitPrimaryKey(fields)
+	q.Writef("DELETE FROM %s WHERE", q.Qualify(table)).
+		WriteValueMap("{name} = {bindVar}", " AND ", values, keys...)
+	q.audit(AuditDelete, table, i, fields, values)
+	return q
+}
+
+// splitPrimaryKey splits fields into the primary key fields and the other fields. Panics when there are no primary
+// key fields.
+func splitPrimaryKey(fields []Field) (keys, rest []Field) {
+	for _, field := range fields {
+		if field.PrimaryKey {
+			keys = append(keys, field)
+		} else {
+			rest = append(rest, field)
+		}
+	}
+	if keys == nil {
+		panic("struct has no primary key fields")
+	}
+	return
+}
+
+// Columns returns fields without the computed fields, which aren't columns of the table. fields is returned as is if
+// it has no computed fields.
+func Columns(fields []Field) []Field {
+	computed := 0
+	for i := range fields {
+		if fields[i].Computed {
+			computed++
+		}
+	}
+	if computed == 0 {
+		return fields
+	}
+	result := make([]Field, 0, len(fields)-computed)
+	for _, field := range fields {
+		if !field.Computed {
+			result = append(result, field)
+		}
+	}
+	return result
+}
+
+// writableColumns returns the columns of fields that can be inserted and updated, without the computed and generated
+// fields.
+func writableColumns(fields []Field) []Field {
+	fields = Columns(fields)
+	result := fields[:0:0]
+	for _, field := range fields {
+		if !field.Generated {
+			result = append(result, field)
+		}
+	}
+	return result
+}
+
+// splitDefaults splits fields into the fields whose values are bound and the fields with a Default that have their
+// zero value.
+func splitDefaults(fields []Field, values ValueMap) (bound, defaults []Field) {
+	bound = fields[:0:0]
+	for _, field := range fields {
+		if field.Default != "" && isZeroValue(values[field.Name]) {
+			defaults = append(defaults, field)
+		} else {
+			bound = append(bound, field)
+		}
+	}
+	return
+}
+
+// omitEmpty returns fields without the OmitEmpty fields that have their zero value.
+func omitEmpty(fields []Field, values ValueMap) []Field {
+	result := fields[:0:0]
+	for _, field := range fields {
+		if field.OmitEmpty && isZeroValue(values[field.Name]) {
+			continue
+		}
+		result = append(result, field)
+	}
+	return result
+}
+
+// isZeroValue returns true if the value of a ValueMap has its zero value.
+func isZeroValue(value reflect.Value) bool {
+	if f, ok := value.Addr().Interface().(*encryptedField); ok {
+		value = f.v
+	}
+	return reflect.DeepEqual(value.Interface(), reflect.Zero(value.Type()).Interface())
+}
@@ -0,0 +1,195 @@
+package querier
+
+import (
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type structModel struct {
+	ID   int64  `db:"id,,pk"`
+	Name string `db:"name"`
+	Age  int    `db:"age"`
+}
+
+func TestStructStatements(t *testing.T) {
+	m := structModel{ID: 1, Name: "foo", Age: 42}
+	tests := []struct {
+		q          *Q
+		want       string
+		wantParams []interface{}
+	}{
+		{
+			New(nil, Default{}).InsertStruct("user", &m),
+			"INSERT INTO user (id, name, age) VALUES (?, ?, ?)",
+			[]interface{}{&m.ID, &m.Name, &m.Age},
+		},
+		{
+			New(nil, Default{}).UpdateStruct("user", &m),
+			"UPDATE user SET name = ?, age = ? WHERE id = ?",
+			[]interface{}{&m.Name, &m.Age, &m.ID},
+		},
+		{
+			New(nil, Default{}).DeleteStruct("user", &m),
+			"DELETE FROM user WHERE id = ?",
+			[]interface{}{&m.ID},
+		},
+	}
+	for _, tt := range tests {
+		if tt.q.String() != tt.want {
+			t.Errorf("String() = %q, want %q", tt.q.String(), tt.want)
+		}
+		if !reflect.DeepEqual(tt.q.Params(), tt.wantParams) {
+			t.Errorf("Params() = %v, want %v", tt.q.Params(), tt.wantParams)
+		}
+	}
+}
+
+func TestStructNoPrimaryKey(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("DeleteStruct() did not panic")
+		}
+	}()
+	New(nil, Default{}).DeleteStruct("enum_model", &enumModel{})
+}
+
+type omitEmptyModel struct {
+	ID   int64  `db:"id,omitempty"`
+	Name string `db:"name"`
+}
+
+func TestInsertStructOmitEmpty(t *testing.T) {
+	q := New(nil, Default{}).InsertStruct("user", &omitEmptyModel{Name: "foo"})
+	if want := "INSERT INTO user (name) VALUES (?)"; q.String() != want {
+		t.Errorf("String() = %q, want %q", q.String(), want)
+	}
+
+	q = New(nil, Default{}).InsertStruct("user", &omitEmptyModel{ID: 1, Name: "foo"})
+	if want := "INSERT INTO user (id, name) VALUES (?, ?)"; q.String() != want {
+		t.Errorf("String() = %q, want %q", q.String(), want)
+	}
+}
+
+func TestStructComputed(t *testing.T) {
+	type report struct {
+		ID         int64  `db:"id,,pk"`
+		Name       string `db:"name"`
+		TotalCount int64  `db:"total_count,computed"`
+	}
+	m := report{ID: 1, Name: "foo", TotalCount: 3}
+	if q, want := New(nil, Default{}).InsertStruct("user", &m), "INSERT INTO user (id, name) VALUES (?, ?)"; q.String() != want {
+		t.Errorf("String() = %q, want %q", q.String(), want)
+	}
+	if q, want := New(nil, Default{}).UpdateStruct("user", &m), "UPDATE user SET name = ? WHERE id = ?"; q.String() != want {
+		t.Errorf("String() = %q, want %q", q.String(), want)
+	}
+
+	db := openTestDB(t, func(string, []driver.Value) (*testRows, error) {
+		return &testRows{
+			columns: []string{"id", "name", "total_count"},
+			values:  [][]driver.Value{{int64(2), "bar", int64(7)}},
+		}, nil
+	})
+	defer db.Close()
+	var scanned report
+	err := New(db, Default{}).Write("SELECT u.id, u.name, COUNT(o.id) AS total_count FROM user u JOIN orders o").First(&scanned)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (report{ID: 2, Name: "bar", TotalCount: 7}); scanned != want {
+		t.Errorf("scanned %+v, want %+v", scanned, want)
+	}
+}
+
+type validatedModel struct {
+	ID    int64  `db:"id,,pk"`
+	Email string `db:"email,,required"`
+	Age   int    `db:"age"`
+}
+
+func (m *validatedModel) Validate() error {
+	if m.Age < 0 {
+		return FieldErrors{{Field: "age", Message: "must not be negative"}}
+	}
+	return nil
+}
+
+func TestStructValidation(t *testing.T) {
+	ex := &testExecutor{}
+	if err := New(ex, Default{}).InsertStruct("user", &validatedModel{Email: "a@example.com"}).Exec(); err != nil {
+		t.Errorf("Exec() of a valid struct = %v", err)
+	}
+
+	err := New(ex, Default{}).UpdateStruct("user", &validatedModel{ID: 1, Age: -1}).Exec()
+	want := FieldErrors{{Field: "email", Message: "is required"}, {Field: "age", Message: "must not be negative"}}
+	if !reflect.DeepEqual(err, want) || !errors.Is(err, ErrInvalidStruct) {
+		t.Errorf("Exec() of an invalid struct = %v, want %v", err, want)
+	}
+	if len(ex.queries) != 1 {
+		t.Errorf("executed %d statements, want 1", len(ex.queries))
+	}
+
+	errCustom := errors.New("custom")
+	SetStructValidator(func(i interface{}) error {
+		if _, ok := i.(*structModel); ok {
+			return errCustom
+		}
+		return nil
+	})
+	defer SetStructValidator(nil)
+	if err := New(ex, Default{}).InsertStruct("user", &structModel{}).Exec(); err != errCustom {
+		t.Errorf("Exec() with a struct validator = %v, want %v", err, errCustom)
+	}
+}
+
+func TestInsertStructDefault(t *testing.T) {
+	type defaultModel struct {
+		ID        int64     `db:"id,omitempty,default"`
+		Name      string    `db:"name"`
+		Score     int       `db:"score,,default=0"`
+		CreatedAt time.Time `db:"created_at,,default=now()"`
+	}
+	q := New(nil, Default{}).InsertStruct("user", &defaultModel{Name: "foo"})
+	if want := "INSERT INTO user (name, id, score, created_at) VALUES (?, DEFAULT, 0, now())"; q.String() != want {
+		t.Errorf("String() = %q, want %q", q.String(), want)
+	}
+	if len(q.Params()) != 1 {
+		t.Errorf("Params() = %v, want 1 parameter", q.Params())
+	}
+
+	m := defaultModel{ID: 1, Name: "foo", Score: 3, CreatedAt: time.Unix(0, 0)}
+	q = New(nil, Default{}).InsertStruct("user", &m)
+	if want := "INSERT INTO user (id, name, score, created_at) VALUES (?, ?, ?, ?)"; q.String() != want {
+		t.Errorf("String() = %q, want %q", q.String(), want)
+	}
+
+	q = New(nil, Default{}).InsertStruct("user", &struct {
+		CreatedAt time.Time `db:"created_at,,default=now()"`
+	}{})
+	if want := "INSERT INTO user (created_at) VALUES (now())"; q.String() != want {
+		t.Errorf("String() = %q, want %q", q.String(), want)
+	}
+}
+
+func TestStructGenerated(t *testing.T) {
+	type line struct {
+		ID       int64 `db:"id,,pk"`
+		Price    int   `db:"price"`
+		Quantity int   `db:"quantity"`
+		Total    int   `db:"total,,generated" generated:"price * quantity"`
+	}
+	m := line{ID: 1, Price: 2, Quantity: 3}
+	if q, want := New(nil, Default{}).InsertStruct("line", &m), "INSERT INTO line (id, price, quantity) VALUES (?, ?, ?)"; q.String() != want {
+		t.Errorf("String() = %q, want %q", q.String(), want)
+	}
+	if q, want := New(nil, Default{}).UpdateStruct("line", &m), "UPDATE line SET price = ?, quantity = ? WHERE id = ?"; q.String() != want {
+		t.Errorf("String() = %q, want %q", q.String(), want)
+	}
+	fields := Fields(&m).Only("total").Select()
+	if len(fields) != 1 || !fields[0].Generated || fields[0].GeneratedAs != "price * quantity" {
+		t.Errorf("Select() = %+v, want a generated field", fields)
+	}
+}
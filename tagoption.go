@@ -0,0 +1,65 @@
+package querier
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// TagOption handles a custom option of db struct field tags, see RegisterTagOption. Both functions are optional.
+type TagOption struct {
+	// Field is called for the selected fields with the option. It may change the field, e.g. its DataType, which is
+	// used by the migrator's DDL. value is the option's value, e.g. "3" for `db:"price,,money=3"`.
+	Field func(field *Field, value string)
+	// Value returns the value that replaces the struct field v in a ValueMap. Its address is bound as the parameter of
+	// the field and scanned into, so it must be addressable, e.g. reflect.ValueOf(&money{v}).Elem() of a type whose
+	// pointer implements sql.Scanner and driver.Valuer.
+	Value func(v reflect.Value, value string) reflect.Value
+}
+
+var (
+	tagOptionsMu sync.RWMutex
+	tagOptions   = make(map[string]TagOption)
+)
+
+// RegisterTagOption registers the handler of option name, so organization specific conventions can be expressed in
+// tags, e.g. `db:"price,,money=3"`. Options must be registered before the struct types using them are mapped,
+// usually in an init function. Panics when name is a built-in option or already registered.
+func RegisterTagOption(name string, option TagOption) {
+	tagOptionsMu.Lock()
+	defer tagOptionsMu.Unlock()
+	if isBuiltinTagOption(name) {
+		panic("tag option " + name + " is built-in")
+	}
+	if _, ok := tagOptions[name]; ok {
+		panic("tag option " + name + " already registered")
+	}
+	tagOptions[name] = option
+}
+
+// registeredTagOption returns the handler of option name.
+func registeredTagOption(name string) (TagOption, bool) {
+	tagOptionsMu.RLock()
+	defer tagOptionsMu.RUnlock()
+	option, ok := tagOptions[name]
+	return option, ok
+}
+
+// customTagOptions calls fn for the registered options of tag, in order of their names.
+func customTagOptions(tag *FieldInfo, fn func(option TagOption, value string)) {
+	if len(tag.Options) == 0 {
+		return
+	}
+	var names []string
+	for name := range tag.Options {
+		if !isBuiltinTagOption(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if option, ok := registeredTagOption(name); ok {
+			fn(option, tag.Options[name])
+		}
+	}
+}
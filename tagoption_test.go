@@ -0,0 +1,88 @@
+package querier
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// cents stores a float64 amount as an integer number of cents.
+type cents struct {
+	v reflect.Value
+}
+
+func (c *cents) Scan(src interface{}) error {
+	n, ok := src.(int64)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into cents", src)
+	}
+	c.v.SetFloat(float64(n) / 100)
+	return nil
+}
+
+func (c *cents) Value() (driver.Value, error) {
+	return int64(c.v.Float()*100 + 0.5), nil
+}
+
+func init() {
+	RegisterTagOption("cents", TagOption{
+		Field: func(field *Field, value string) {
+			field.DataType = "BIGINT NOT NULL"
+			if value == "nullable" {
+				field.DataType = "BIGINT NULL"
+			}
+		},
+		Value: func(v reflect.Value, _ string) reflect.Value {
+			return reflect.ValueOf(&cents{v}).Elem()
+		},
+	})
+}
+
+type tagOptionModel struct {
+	ID    int64   `db:"id"`
+	Price float64 `db:"price,cents"`
+	Tax   float64 `db:"tax,,cents=nullable"`
+}
+
+func TestTagOptionField(t *testing.T) {
+	got := Fields(&tagOptionModel{}).Select()
+	want := []Field{
+		{Name: "id", DataType: "BIGINT NOT NULL"},
+		{Name: "price", DataType: "BIGINT NOT NULL"},
+		{Name: "tax", DataType: "BIGINT NULL"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Select() = %v, want %v", got, want)
+	}
+}
+
+func TestTagOptionValue(t *testing.T) {
+	m := &tagOptionModel{ID: 1, Price: 12.34, Tax: 0.5}
+	q := New(nil, Default{}).InsertStruct("product", m)
+	var params []driver.Value
+	for _, param := range q.Params() {
+		if valuer, ok := param.(driver.Valuer); ok {
+			v, err := valuer.Value()
+			if err != nil {
+				t.Fatal(err)
+			}
+			params = append(params, v)
+		}
+	}
+	if want := []driver.Value{int64(1234), int64(50)}; !reflect.DeepEqual(params, want) {
+		t.Errorf("bound %v, want %v", params, want)
+	}
+
+	db := openTestDB(t, func(string, []driver.Value) (*testRows, error) {
+		return &testRows{columns: []string{"id", "price", "tax"}, values: [][]driver.Value{{int64(2), int64(999), int64(1)}}}, nil
+	})
+	defer db.Close()
+	var scanned tagOptionModel
+	if err := New(db, Default{}).Write("SELECT * FROM product").First(&scanned); err != nil {
+		t.Fatal(err)
+	}
+	if want := (tagOptionModel{ID: 2, Price: 9.99, Tax: 0.01}); scanned != want {
+		t.Errorf("scanned %+v, want %+v", scanned, want)
+	}
+}
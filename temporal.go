@@ -0,0 +1,54 @@
+package querier
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DateTruncer can be implemented by a Dialect to truncate timestamps. Dialects that don't implement it use the
+// default, DATE_TRUNC of PostgreSQL.
+type DateTruncer interface {
+	// DateTrunc returns an expression truncating expr to unit, one of second, minute, hour, day, week, month, quarter
+	// and year. It returns false if unit is unsupported.
+	DateTrunc(unit, expr string) (string, bool)
+}
+
+// DateTrunc returns DATE_TRUNC('unit', expr).
+func (Default) DateTrunc(unit, expr string) (string, bool) {
+	switch unit {
+	case "second", "minute", "hour", "day", "week", "month", "quarter", "year":
+		return "DATE_TRUNC('" + unit + "', " + expr + ")", true
+	}
+	return "", false
+}
+
+// DateTrunc returns an expression of the Dialect truncating expr to unit, e.g. for grouping by day:
+//
+//	day := q.DateTrunc("day", "created_at")
+//	q.Writef("SELECT %s, COUNT(*) FROM event GROUP BY %s", day, day)
+//
+// unit is one of second, minute, hour, day, week, month, quarter and year, an unsupported unit results in an error
+// when the query is executed.
+func (q *Q) DateTrunc(unit, expr string) string {
+	truncer, ok := q.d.(DateTruncer)
+	if !ok {
+		truncer = Default{}
+	}
+	trunc, ok := truncer.DateTrunc(strings.ToLower(unit), expr)
+	if !ok {
+		q.fail(fmt.Errorf("unsupported date truncation unit %q", unit))
+		return expr
+	}
+	return trunc
+}
+
+// WhereBetweenDates writes a condition selecting the values of column from from up to, but not including, to:
+//
+//	column >= ? AND column < ?
+//
+// The half-open range includes the whole last day of a range like [2020-01-01, 2020-02-01), regardless of the
+// precision of the column.
+func (q *Q) WhereBetweenDates(column string, from, to time.Time) *Q {
+	return q.WriteValues(column+" >= {bindVar}", "", from).WriteValues("AND "+column+" < {bindVar}", "", to)
+}
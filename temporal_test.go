@@ -0,0 +1,48 @@
+package querier
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type monthTruncDialect struct {
+	Default
+}
+
+func (monthTruncDialect) DateTrunc(unit, expr string) (string, bool) {
+	if unit != "month" {
+		return "", false
+	}
+	return "STRFTIME('%Y-%m-01', " + expr + ")", true
+}
+
+func TestDateTrunc(t *testing.T) {
+	q := New(&testExecutor{}, Default{})
+	day := q.DateTrunc("Day", "created_at")
+	q.Writef("SELECT %s, COUNT(*) FROM event GROUP BY %s", day, day)
+	if want := "SELECT DATE_TRUNC('day', created_at), COUNT(*) FROM event GROUP BY DATE_TRUNC('day', created_at)"; q.String() != want {
+		t.Errorf("String() = %q, want %q", q.String(), want)
+	}
+
+	q = New(&testExecutor{}, monthTruncDialect{})
+	if got, want := q.DateTrunc("month", "created_at"), "STRFTIME('%Y-%m-01', created_at)"; got != want {
+		t.Errorf("DateTrunc() = %q, want %q", got, want)
+	}
+	q.Writef("SELECT %s FROM event", q.DateTrunc("fortnight", "created_at"))
+	if err := q.Exec(); err == nil {
+		t.Error("Exec() with unsupported unit returned no error")
+	}
+}
+
+func TestWhereBetweenDates(t *testing.T) {
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	q := New(nil, numberedDialect{}).Write("SELECT * FROM event WHERE").WhereBetweenDates("created_at", from, to)
+	if want := "SELECT * FROM event WHERE created_at >= $1 AND created_at < $2"; q.String() != want {
+		t.Errorf("String() = %q, want %q", q.String(), want)
+	}
+	if want := []interface{}{from, to}; !reflect.DeepEqual(q.Params(), want) {
+		t.Errorf("Params() = %v, want %v", q.Params(), want)
+	}
+}
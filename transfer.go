@@ -0,0 +1,137 @@
+package querier
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// DefaultTransferBatchSize is the number of rows Transfer inserts per statement by default.
+const DefaultTransferBatchSize = 100
+
+// TransferOptions configures Transfer.
+type TransferOptions struct {
+	// Table is the table the rows are inserted into. It's qualified with the schema of the destination, see SetSchema.
+	Table string
+	// Fields selects the columns that are transferred, e.g. Fields(&User{}).Except("password"). The columns of the
+	// result are matched to the names of the fields, they can be renamed by the query with AS. Fields without a column
+	// in the result are an error. nil transfers all columns of the result.
+	Fields *FieldSelector
+	// BatchSize is the number of rows inserted per statement, DefaultTransferBatchSize if it's zero. It's limited by the
+	// number of bind variables the destination database accepts per statement.
+	BatchSize int
+}
+
+// Copier can be implemented by a Dialect to load rows in bulk, e.g. with COPY of PostgreSQL, instead of the multi-row
+// INSERT statements of Transfer.
+type Copier interface {
+	// CopyRows inserts rows, the values of columns, into table using ex.
+	CopyRows(ctx context.Context, ex Executor, table string, columns []string, rows [][]interface{}) error
+}
+
+// Transfer executes the query of src and streams its rows into a table of dst, which may be of another database and
+// Dialect, e.g. for ETL jobs or to copy production data to staging:
+//
+//	n, err := querier.Transfer(ctx, prod.New().Write("SELECT * FROM user WHERE active"), staging.New(),
+//		querier.TransferOptions{Table: "user", Fields: querier.Fields(&User{}).Except("password")})
+//
+// The rows are inserted in batches with multi-row INSERT statements, or with CopyRows if the Dialect of dst is a
+// Copier. The batches aren't inserted in one transaction, unless the Executor of dst is one. Transfer returns the
+// number of rows inserted, also when it fails.
+func Transfer(ctx context.Context, src, dst *Q, opts TransferOptions) (n int64, err error) {
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultTransferBatchSize
+	}
+	var (
+		columns []string
+		// index contains the index in the result of every column.
+		index []int
+		dest  []interface{}
+		batch [][]interface{}
+	)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := insertRows(ctx, dst.New(), opts.Table, columns, batch); err != nil {
+			return err
+		}
+		n += int64(len(batch))
+		batch = batch[:0]
+		return nil
+	}
+	err = src.ForEachContext(ctx, func(_ *Q, rows *sql.Rows) error {
+		if columns == nil {
+			result, err := rows.Columns()
+			if err != nil {
+				return err
+			}
+			if columns, index, err = transferColumns(result, opts.Fields); err != nil {
+				return err
+			}
+			dest = make([]interface{}, len(result))
+		}
+		values := make([]interface{}, len(dest))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+		row := make([]interface{}, len(index))
+		for i, j := range index {
+			row[i] = values[j]
+		}
+		if batch = append(batch, row); len(batch) == batchSize {
+			return flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = flush()
+	}
+	return n, err
+}
+
+// transferColumns returns the columns of the result that are transferred and their index in the result.
+func transferColumns(result []string, fields *FieldSelector) (columns []string, index []int, err error) {
+	if fields == nil {
+		index = make([]int, len(result))
+		for i := range index {
+			index[i] = i
+		}
+		return result, index, nil
+	}
+	positions := make(map[string]int, len(result))
+	for i, column := range result {
+		positions[column] = i
+	}
+	for _, field := range Columns(fields.Select()) {
+		i, ok := positions[field.Name]
+		if !ok {
+			return nil, nil, fmt.Errorf("querier: transfer field %s has no column in the result", field.Name)
+		}
+		columns, index = append(columns, field.Name), append(index, i)
+	}
+	return columns, index, nil
+}
+
+// insertRows inserts rows into table with q.
+func insertRows(ctx context.Context, q *Q, table string, columns []string, rows [][]interface{}) error {
+	if c, ok := q.d.(Copier); ok {
+		return c.CopyRows(ctx, q.ex, q.Qualify(table), columns, rows)
+	}
+	q.Writef("INSERT INTO %s (%s) VALUES", q.Qualify(table), strings.Join(columns, FieldSep))
+	q.writeSep()
+	start, paramStart := q.query.Len(), len(q.params)
+	for i, row := range rows {
+		if i > 0 {
+			q.writeText(FieldSep)
+		}
+		q.writeRowValue(row)
+	}
+	q.record(SegmentValues, string(q.query.Bytes()[start:]), nil, paramStart)
+	return q.ExecContext(ctx)
+}
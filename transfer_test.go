@@ -0,0 +1,57 @@
+package querier
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+)
+
+func TestTransfer(t *testing.T) {
+	type user struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+	src := NewDB(openTestDB(t, func(string, []driver.Value) (*testRows, error) {
+		return &testRows{columns: []string{"id", "password", "name"}, values: [][]driver.Value{
+			{int64(1), "x", "a"}, {int64(2), "y", "b"}, {int64(3), "z", "c"},
+		}}, nil
+	}), Default{})
+	var (
+		queries []string
+		args    [][]driver.Value
+	)
+	dst := NewDB(openTestDB(t, func(query string, a []driver.Value) (*testRows, error) {
+		queries, args = append(queries, query), append(args, a)
+		return nil, nil
+	}), numberedDialect{})
+
+	n, err := Transfer(context.Background(), src.New().Write("SELECT * FROM user"), dst.WithSchema("staging").New(),
+		TransferOptions{Table: "user", Fields: Fields(&user{}), BatchSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Errorf("Transfer() = %d, want 3", n)
+	}
+	wantQueries := []string{
+		"INSERT INTO staging.user (id, name) VALUES ($1, $2), ($3, $4)",
+		"INSERT INTO staging.user (id, name) VALUES ($1, $2)",
+	}
+	if !reflect.DeepEqual(queries, wantQueries) {
+		t.Errorf("executed %q, want %q", queries, wantQueries)
+	}
+	wantArgs := [][]driver.Value{{int64(1), "a", int64(2), "b"}, {int64(3), "c"}}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("args = %v, want %v", args, wantArgs)
+	}
+
+	type missing struct {
+		Email string `db:"email"`
+	}
+	_, err = Transfer(context.Background(), src.New().Write("SELECT * FROM user"), dst.New(),
+		TransferOptions{Table: "user", Fields: Fields(&missing{})})
+	if err == nil {
+		t.Error("Transfer() of a field without a column succeeded")
+	}
+}
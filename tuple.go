@@ -0,0 +1,194 @@
+package querier
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// WhereTuple writes a comparison of the columns cols with a row value, e.g. for composite keys:
+//
+//	q.Write("SELECT * FROM stock WHERE").WhereTuple([]string{"shop_id", "sku"}, "IN", []interface{}{
+//		[]interface{}{1, "A-1"},
+//		[]interface{}{2, "B-7"},
+//	})
+//
+// writes (shop_id, sku) IN ((?, ?), (?, ?)). For IN and NOT IN every value is a []interface{} tuple, for =, <>, <,
+// <=, > and >= values is one tuple. Row values are written as ANDed and ORed column comparisons when the Dialect
+// doesn't support FeatureRowValues, e.g. for SQL Server. Tuples of the wrong length result in an error when the query
+// is executed.
+func (q *Q) WhereTuple(cols []string, op string, values []interface{}) *Q {
+	if len(cols) == 0 {
+		q.fail(errors.New("tuple comparison without columns"))
+		return q
+	}
+	op = strings.ToUpper(op)
+	q.writeSep()
+	start := q.query.Len()
+	paramStart := len(q.params)
+	var err error
+	switch op {
+	case "IN", "NOT IN":
+		err = q.writeTupleIn(cols, op, values)
+	case "=", "<>", "<", "<=", ">", ">=":
+		if len(values) != len(cols) {
+			err = fmt.Errorf("tuple %v doesn't match columns %v", values, cols)
+		} else {
+			q.writeTupleCompare(cols, op, values)
+		}
+	default:
+		err = fmt.Errorf("unsupported tuple operator %q", op)
+	}
+	if err != nil {
+		q.fail(err)
+	}
+	q.record(SegmentValues, string(q.query.Bytes()[start:]), nil, paramStart)
+	return q
+}
+
+func (q *Q) writeTupleIn(cols []string, op string, values []interface{}) error {
+	tuples := make([][]interface{}, len(values))
+	for i, v := range values {
+		tuple, ok := v.([]interface{})
+		if !ok || len(tuple) != len(cols) {
+			return fmt.Errorf("tuple %v doesn't match columns %v", v, cols)
+		}
+		tuples[i] = tuple
+	}
+	if len(tuples) == 0 {
+		// Nothing is IN an empty list.
+		if op == "IN" {
+			q.writeText("1 = 0")
+		} else {
+			q.writeText("1 = 1")
+		}
+		return nil
+	}
+
+	if Supports(q.d, FeatureRowValues) {
+		q.writeText("(" + strings.Join(cols, ", ") + ") " + op + " (")
+		for i, tuple := range tuples {
+			if i > 0 {
+				q.writeText(", ")
+			}
+			q.writeRowValue(tuple)
+		}
+		q.writeText(")")
+		return nil
+	}
+
+	if op == "NOT IN" {
+		q.writeText("NOT ")
+	}
+	q.writeText("(")
+	for i, tuple := range tuples {
+		if i > 0 {
+			q.writeText(" OR ")
+		}
+		q.writeTupleCompare(cols, "=", tuple)
+	}
+	q.writeText(")")
+	return nil
+}
+
+// writeRowValue writes (?, ?, ...) for the values of tuple.
+func (q *Q) writeRowValue(tuple []interface{}) {
+	q.writeText("(")
+	for i, v := range tuple {
+		if i > 0 {
+			q.writeText(", ")
+		}
+		q.writeParam(v)
+	}
+	q.writeText(")")
+}
+
+// writeTupleCompare writes a comparison of cols with tuple.
+func (q *Q) writeTupleCompare(cols []string, op string, tuple []interface{}) {
+	if Supports(q.d, FeatureRowValues) {
+		q.writeText("(" + strings.Join(cols, ", ") + ") " + op + " ")
+		q.writeRowValue(tuple)
+		return
+	}
+
+	switch op {
+	case "=", "<>":
+		// (a = ? AND b = ?), or (a <> ? OR b <> ?).
+		join := " AND "
+		if op == "<>" {
+			join = " OR "
+		}
+		q.writeText("(")
+		for i, col := range cols {
+			if i > 0 {
+				q.writeText(join)
+			}
+			q.writeText(col + " " + op + " ")
+			q.writeParam(tuple[i])
+		}
+		q.writeText(")")
+	default:
+		// Lexicographic: (a < ? OR (a = ? AND b < ?)), only the last column uses op with its equality, e.g. <=.
+		strict := op[:1]
+		q.writeText("(")
+		for i := range cols {
+			if i > 0 {
+				q.writeText(" OR ")
+			}
+			q.writeText("(")
+			for j := 0; j < i; j++ {
+				q.writeText(cols[j] + " = ")
+				q.writeParam(tuple[j])
+				q.writeText(" AND ")
+			}
+			colOp := strict
+			if i == len(cols)-1 {
+				colOp = op
+			}
+			q.writeText(cols[i] + " " + colOp + " ")
+			q.writeParam(tuple[i])
+			q.writeText(")")
+		}
+		q.writeText(")")
+	}
+}
+
+// writeParam writes the next bind variable and adds v as its parameter.
+func (q *Q) writeParam(v interface{}) {
+	q.writeBindVar(q.nextBindVar())
+	q.params = append(q.params, v)
+}
+
+// In writes IN with a list of bind variables for the elements of the slice values, e.g.
+//
+//	q.Write("SELECT * FROM user WHERE id").In(ids)
+//
+// writes id IN (?, ?, ?). An empty slice writes IN (NULL), which matches nothing. More values than the MaxIn of the
+// Options result in an *InTooLargeError. Panics when values isn't a slice.
+func (q *Q) In(values interface{}) *Q {
+	v := reflect.ValueOf(values)
+	if v.Kind() != reflect.Slice {
+		panic("argument values is not a slice")
+	}
+	q.writeSep()
+	start := q.query.Len()
+	paramStart := len(q.params)
+	if max := q.opts.MaxIn; max > 0 && v.Len() > max {
+		q.fail(&InTooLargeError{Values: v.Len(), Max: max})
+	}
+	if v.Len() == 0 {
+		q.writeText("IN (NULL)")
+	} else {
+		q.writeText("IN (")
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				q.writeText(", ")
+			}
+			q.writeParam(v.Index(i).Interface())
+		}
+		q.writeText(")")
+	}
+	q.record(SegmentValues, string(q.query.Bytes()[start:]), nil, paramStart)
+	return q
+}
@@ -0,0 +1,71 @@
+package querier
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWhereTuple(t *testing.T) {
+	cols := []string{"a", "b"}
+	in := []interface{}{[]interface{}{1, 2}, []interface{}{3, 4}}
+	tests := []struct {
+		d          Dialect
+		op         string
+		values     []interface{}
+		want       string
+		wantParams []interface{}
+	}{
+		{Default{}, "IN", in, "(a, b) IN ((?, ?), (?, ?))", []interface{}{1, 2, 3, 4}},
+		{Default{}, "not in", in, "(a, b) NOT IN ((?, ?), (?, ?))", []interface{}{1, 2, 3, 4}},
+		{Default{}, ">", []interface{}{1, 2}, "(a, b) > (?, ?)", []interface{}{1, 2}},
+		{Default{}, "IN", nil, "1 = 0", nil},
+		{analyticsDialect{}, "IN", in, "((a = ? AND b = ?) OR (a = ? AND b = ?))", []interface{}{1, 2, 3, 4}},
+		{analyticsDialect{}, "NOT IN", in, "NOT ((a = ? AND b = ?) OR (a = ? AND b = ?))", []interface{}{1, 2, 3, 4}},
+		{analyticsDialect{}, "NOT IN", nil, "1 = 1", nil},
+		{analyticsDialect{}, "<>", []interface{}{1, 2}, "(a <> ? OR b <> ?)", []interface{}{1, 2}},
+		{analyticsDialect{}, ">=", []interface{}{1, 2}, "((a > ?) OR (a = ? AND b >= ?))", []interface{}{1, 1, 2}},
+	}
+	for _, tt := range tests {
+		q := New(nil, tt.d).WhereTuple(cols, tt.op, tt.values)
+		if q.String() != tt.want {
+			t.Errorf("WhereTuple(%s) = %q, want %q", tt.op, q.String(), tt.want)
+		}
+		if !reflect.DeepEqual(q.Params(), tt.wantParams) {
+			t.Errorf("WhereTuple(%s) params = %v, want %v", tt.op, q.Params(), tt.wantParams)
+		}
+	}
+
+	q := New(nil, numberedDialect{}).Write("SELECT * FROM t WHERE").WhereTuple(cols, "=", []interface{}{1, 2})
+	if want := "SELECT * FROM t WHERE (a, b) = ($1, $2)"; q.String() != want {
+		t.Errorf("String() = %q, want %q", q.String(), want)
+	}
+}
+
+func TestWhereTupleErrors(t *testing.T) {
+	cols := []string{"a", "b"}
+	for _, q := range []*Q{
+		New(&testExecutor{}, Default{}).Write("SELECT * FROM t WHERE").WhereTuple(cols, "IN", []interface{}{[]interface{}{1}}),
+		New(&testExecutor{}, Default{}).Write("SELECT * FROM t WHERE").WhereTuple(cols, "IN", []interface{}{1, 2}),
+		New(&testExecutor{}, Default{}).Write("SELECT * FROM t WHERE").WhereTuple(cols, "=", []interface{}{1}),
+		New(&testExecutor{}, Default{}).Write("SELECT * FROM t WHERE").WhereTuple(cols, "LIKE", []interface{}{1, 2}),
+	} {
+		if err := q.Exec(); err == nil {
+			t.Errorf("Exec() of %q returned no error", q.String())
+		}
+	}
+}
+
+func TestIn(t *testing.T) {
+	q := New(nil, numberedDialect{}).Write("SELECT * FROM t WHERE id").In([]int64{1, 2})
+	if want := "SELECT * FROM t WHERE id IN ($1, $2)"; q.String() != want {
+		t.Errorf("String() = %q, want %q", q.String(), want)
+	}
+	if want := []interface{}{int64(1), int64(2)}; !reflect.DeepEqual(q.Params(), want) {
+		t.Errorf("Params() = %v, want %v", q.Params(), want)
+	}
+
+	q = New(nil, Default{}).Write("SELECT * FROM t WHERE id").In([]string{})
+	if want := "SELECT * FROM t WHERE id IN (NULL)"; q.String() != want {
+		t.Errorf("String() = %q, want %q", q.String(), want)
+	}
+}
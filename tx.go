@@ -0,0 +1,76 @@
+package querier
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+)
+
+// ErrAfterCommitTx is returned when a query with AfterCommit is executed by a *sql.Tx, which can't run functions after
+// it has been committed. Use a *Tx instead, see Begin.
+var ErrAfterCommitTx = errors.New("AfterCommit requires a *Tx instead of a *sql.Tx")
+
+// Tx is a transaction that can run functions after it has been committed. It can be used as an Executor.
+type Tx struct {
+	*sql.Tx
+
+	mu          sync.Mutex
+	afterCommit []func()
+}
+
+// Begin starts a transaction.
+func Begin(ctx context.Context, db *sql.DB, opts *sql.TxOptions) (*Tx, error) {
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{Tx: tx}, nil
+}
+
+// AfterCommit adds fn to the functions that run, in order, after the transaction has been committed successfully.
+// They don't run when the transaction is rolled back.
+func (tx *Tx) AfterCommit(fn func()) {
+	tx.mu.Lock()
+	tx.afterCommit = append(tx.afterCommit, fn)
+	tx.mu.Unlock()
+}
+
+// Commit commits the transaction and runs the functions added by AfterCommit.
+func (tx *Tx) Commit() error {
+	if err := tx.Tx.Commit(); err != nil {
+		return err
+	}
+	tx.mu.Lock()
+	fns := tx.afterCommit
+	tx.afterCommit = nil
+	tx.mu.Unlock()
+	for _, fn := range fns {
+		fn()
+	}
+	return nil
+}
+
+// Rollback aborts the transaction and discards the functions added by AfterCommit.
+func (tx *Tx) Rollback() error {
+	tx.mu.Lock()
+	tx.afterCommit = nil
+	tx.mu.Unlock()
+	return tx.Tx.Rollback()
+}
+
+// AfterCommit runs fn after the query's changes have been committed, e.g. to invalidate caches. If the query's
+// Executor is a *Tx, fn is added to the transaction's AfterCommit functions when the query executes successfully.
+// Otherwise the changes are committed by executing the query, so fn runs when the query executes successfully. A
+// *sql.Tx can't run fn after its commit, so the query isn't executed and returns ErrAfterCommitTx. Executors that wrap
+// a *sql.Tx aren't detected.
+func (q *Q) AfterCommit(fn func()) *Q {
+	q.afterCommit = true
+	return q.DeferSuccess(func(q *Q) {
+		if tx, ok := q.ex.(*Tx); ok {
+			tx.AfterCommit(fn)
+		} else {
+			fn()
+		}
+	})
+}
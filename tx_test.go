@@ -0,0 +1,81 @@
+package querier
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestAfterCommit(t *testing.T) {
+	db := openTestDB(t, func(string, []driver.Value) (*testRows, error) {
+		return nil, nil
+	})
+	defer db.Close()
+
+	var calls []string
+	tx, err := Begin(context.Background(), db, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = New(tx, Default{}).Write("DELETE FROM user").AfterCommit(func() { calls = append(calls, "delete") }).Exec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.AfterCommit(func() { calls = append(calls, "tx") })
+	if calls != nil {
+		t.Fatalf("functions ran before commit: %v", calls)
+	}
+	if err = tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"delete", "tx"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("ran %v, want %v", calls, want)
+	}
+
+	calls = nil
+	tx, err = Begin(context.Background(), db, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.AfterCommit(func() { calls = append(calls, "tx") })
+	if err = tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if calls != nil {
+		t.Errorf("functions ran after rollback: %v", calls)
+	}
+
+	// Without a transaction the function runs after the query executed.
+	err = New(db, Default{}).Write("DELETE FROM user").AfterCommit(func() { calls = append(calls, "db") }).Exec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"db"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("ran %v, want %v", calls, want)
+	}
+}
+
+func TestAfterCommitSQLTx(t *testing.T) {
+	var queries []string
+	db := openTestDB(t, func(query string, _ []driver.Value) (*testRows, error) {
+		queries = append(queries, query)
+		return nil, nil
+	})
+	defer db.Close()
+
+	sqlTx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlTx.Rollback()
+	var ran bool
+	err = New(sqlTx, Default{}).Write("DELETE FROM user").AfterCommit(func() { ran = true }).Exec()
+	if !errors.Is(err, ErrAfterCommitTx) {
+		t.Errorf("Exec() = %v, want %v", err, ErrAfterCommitTx)
+	}
+	if ran || len(queries) != 0 {
+		t.Errorf("executed %q, ran the function: %v", queries, ran)
+	}
+}
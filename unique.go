@@ -0,0 +1,72 @@
+package querier
+
+import (
+	"errors"
+	"strings"
+)
+
+// tagOptionUnique marks a field as part of a unique constraint, e.g. `db:"email,,unique"`. The constraint can be named,
+// e.g. `db:"email,,unique=user_email"`, fields with the same name form a composite constraint.
+const tagOptionUnique = "unique"
+
+// UniqueConstraintName returns the name of the unique constraint of field in table: its UniqueConstraint, or
+// table_column_key if it has none, like PostgreSQL names them. The schema of a qualified table is left out.
+func UniqueConstraintName(table string, field Field) string {
+	if field.UniqueConstraint != "" {
+		return field.UniqueConstraint
+	}
+	return unqualified(table) + "_" + field.Name + "_key"
+}
+
+// unqualified returns table without its schema.
+func unqualified(table string) string {
+	return table[strings.LastIndexByte(table, '.')+1:]
+}
+
+// UniqueConstraints returns the names of the unique constraints of fields in table with the names of their fields, in
+// the order of the fields. The migrator creates them.
+func UniqueConstraints(table string, fields []Field) (names []string, columns map[string][]string) {
+	columns = make(map[string][]string)
+	for _, field := range fields {
+		if !field.Unique {
+			continue
+		}
+		name := UniqueConstraintName(table, field)
+		if _, ok := columns[name]; !ok {
+			names = append(names, name)
+		}
+		columns[name] = append(columns[name], field.Name)
+	}
+	return names, columns
+}
+
+// mapConstraints sets the constraints that InsertStruct and UpdateStruct map unique violations back to the fields
+// of, see UniqueViolation.Fields.
+func (q *Q) mapConstraints(table string, fields []Field) {
+	_, q.constraints = UniqueConstraints(table, fields)
+	table = unqualified(table)
+	for _, field := range fields {
+		if field.Unique && field.UniqueConstraint == "" {
+			// MySQL names the index of a unique column after the column.
+			if _, ok := q.constraints[field.Name]; !ok {
+				q.constraints[field.Name] = []string{field.Name}
+			}
+		}
+		if field.PrimaryKey {
+			// The primary key as named by PostgreSQL and MySQL.
+			q.constraints[table+"_pkey"] = append(q.constraints[table+"_pkey"], field.Name)
+			q.constraints["PRIMARY"] = append(q.constraints["PRIMARY"], field.Name)
+		}
+	}
+}
+
+// translateError translates err with the DialectErrors of the Dialect, and maps the constraint of a unique violation
+// to the fields of the statement.
+func (q *Q) translateError(err error) error {
+	err = translateError(q.d, err)
+	var unique *UniqueViolation
+	if q.constraints != nil && errors.As(err, &unique) && unique.Fields == nil {
+		unique.Fields = q.constraints[unique.Constraint]
+	}
+	return err
+}
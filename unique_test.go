@@ -0,0 +1,50 @@
+package querier
+
+import (
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestUniqueConstraints(t *testing.T) {
+	type account struct {
+		ID     int64  `db:"id,,pk"`
+		Email  string `db:"email,,unique"`
+		Tenant int64  `db:"tenant,,unique=account_tenant_slug"`
+		Slug   string `db:"slug,,unique=account_tenant_slug"`
+	}
+	names, columns := UniqueConstraints("public.account", Fields(&account{}).Select())
+	if want := []string{"account_email_key", "account_tenant_slug"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("UniqueConstraints() names = %v, want %v", names, want)
+	}
+	if want := []string{"tenant", "slug"}; !reflect.DeepEqual(columns["account_tenant_slug"], want) {
+		t.Errorf("UniqueConstraints() columns = %v, want %v", columns["account_tenant_slug"], want)
+	}
+}
+
+func TestUniqueViolationFields(t *testing.T) {
+	type user struct {
+		ID    int64  `db:"id,,pk"`
+		Email string `db:"email,,unique=user_email"`
+	}
+	sqlDB := openTestDB(t, func(string, []driver.Value) (*testRows, error) {
+		return nil, errors.New("duplicate entry")
+	})
+
+	err := New(sqlDB, uniqueDialect{}).InsertStruct("user", &user{1, "a@example.com"}).Exec()
+	var unique *UniqueViolation
+	if !errors.As(err, &unique) || !reflect.DeepEqual(unique.Fields, []string{"email"}) {
+		t.Fatalf("InsertStruct().Exec() = %v, want a *UniqueViolation of field email", err)
+	}
+	want := FieldErrors{{Field: "email", Message: "is already taken"}}
+	if !reflect.DeepEqual(unique.FieldErrors(), want) {
+		t.Errorf("FieldErrors() = %v, want %v", unique.FieldErrors(), want)
+	}
+
+	// Without the struct the fields are unknown.
+	err = New(sqlDB, uniqueDialect{}).Write("INSERT INTO user (email) VALUES (?)", "a@example.com").Exec()
+	if !errors.As(err, &unique) || unique.Fields != nil || unique.FieldErrors() != nil {
+		t.Errorf("Exec() = %v, want a *UniqueViolation without fields", err)
+	}
+}
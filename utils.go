@@ -8,6 +8,10 @@ import (
 
 const (
 	structFieldTagKey = "db"
+
+	// Tag options.
+	tagOptionInline = "inline"
+	tagOptionSquash = "squash"
 )
 
 // AppendToStringSlice returns a ScanFunc that will append any result of the first column of the query to slice s. Panics when s is invalid.
@@ -25,37 +29,150 @@ func AppendToStringSlice(s *[]string) ScanFunc {
 	}
 }
 
-// extractField returns info about a StructField.
-func extractFieldInfo(field *reflect.StructField, d Dialect) (name, dataType string, ignore, inlineStruct bool) {
-	name = field.Name
-	tagParts := strings.SplitN(field.Tag.Get(structFieldTagKey), ",", 2)
+// FieldInfo is a parsed db struct field tag of the form "name,dataType,option,option=value".
+type FieldInfo struct {
+	// Name is the column name, empty if the field's name is used.
+	Name string
+	// DataType is the data type, empty if it's mapped by the Dialect.
+	DataType string
+	// Options maps the options to their values, which are empty for options without a value.
+	Options map[string]string
+}
+
+// ParseFieldTag parses the value of a db struct field tag, e.g. `db:"price,DECIMAL(10,2),sensitive"`. Commas inside
+// parentheses are part of the data type. A known option in place of the data type is an option, so tags of the form
+// "name,option" as used by sqlx are parsed as expected. Dialects and tools can use it to interpret tags like the
+// FieldSelector does.
+func ParseFieldTag(tag string) (ft FieldInfo) {
+	parts := splitTopLevel(tag, ',')
+	ft.Name = parts[0]
+	options := 2
+	if len(parts) > 1 {
+		if isTagOption(parts[1]) {
+			options = 1
+		} else {
+			ft.DataType = parts[1]
+		}
+	}
+	if len(parts) <= options {
+		return
+	}
+	for _, opt := range parts[options:] {
+		if opt == "" {
+			continue
+		}
+		if ft.Options == nil {
+			ft.Options = make(map[string]string)
+		}
+		kv := strings.SplitN(opt, "=", 2)
+		if len(kv) == 2 {
+			ft.Options[kv[0]] = kv[1]
+		} else {
+			ft.Options[kv[0]] = ""
+		}
+	}
+	return
+}
+
+// isTagOption returns true if opt is a built-in or registered tag option.
+func isTagOption(opt string) bool {
+	name := strings.SplitN(opt, "=", 2)[0]
+	if isBuiltinTagOption(name) {
+		return true
+	}
+	_, ok := registeredTagOption(name)
+	return ok
+}
+
+// isBuiltinTagOption returns true if name is a built-in tag option.
+func isBuiltinTagOption(name string) bool {
+	switch name {
+	case tagOptionInline, tagOptionSquash, tagOptionEnum, tagOptionEncrypted, tagOptionSensitive, tagOptionPrimaryKey,
+		tagOptionOmitEmpty, tagOptionComputed, tagOptionRequired, tagOptionDefault, tagOptionGenerated,
+		tagOptionSequence, tagOptionID, tagOptionUnique:
+		return true
+	}
+	return false
+}
+
+// HasOption returns true if the tag has option opt.
+func (ft *FieldInfo) HasOption(opt string) bool {
+	_, ok := ft.Options[opt]
+	return ok
+}
+
+// splitTopLevel splits s on sep, ignoring any sep inside parentheses.
+func splitTopLevel(s string, sep byte) (parts []string) {
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+			}
+		case sep:
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// fieldInfo contains info about a StructField.
+type fieldInfo struct {
+	name, dataType string
+	// prefix must be prepended to the names of an inline struct's fields.
+	prefix               string
+	ignore, inlineStruct bool
+	tag                  FieldInfo
+}
 
-	if tagParts[0] != "" {
+// extractFieldInfo returns info about a StructField.
+func extractFieldInfo(field *reflect.StructField, d Dialect) (fi fieldInfo) {
+	if field.PkgPath != "" && !(field.Anonymous && field.Type.Kind() == reflect.Struct && !isColumnType(field.Type)) {
+		// Unexported fields can't be scanned into or bound, but the exported fields of an embedded struct can.
+		fi.ignore = true
+		return
+	}
+	fi.name = field.Name
+	fi.tag = ParseFieldTag(field.Tag.Get(structFieldTagKey))
+
+	if fi.tag.Name != "" {
 		// A field name is set in the field tag, use this as the field name.
-		name = tagParts[0]
+		fi.name = fi.tag.Name
 	}
-	if name == "-" {
+	if fi.name == "-" {
 		// Name equals "-", ignore this field.
-		ignore = true
+		fi.ignore = true
 		return
 	}
 
-	if len(tagParts) == 2 {
-		// A datatype is set in the field tag, use this as the field's data type.
-		dataType = tagParts[1]
-	}
-	if dataType == "" {
-		if field.Type.Kind() == reflect.Struct {
-			receiver := reflect.PtrTo(field.Type)
-			if field.Type != reflectTypeTime && !receiver.Implements(reflectTypeScanner) {
+	// A datatype may be set in the field tag, use this as the field's data type.
+	fi.dataType = fi.tag.DataType
+	if fi.dataType == "" {
+		t := field.Type
+		if t.Kind() == reflect.Ptr && field.Anonymous {
+			// Embedded pointers to structs, e.g. *BaseModel, are flattened as well.
+			t = t.Elem()
+		}
+		if t.Kind() == reflect.Struct {
+			if !isColumnType(t) {
 				// This field is an inline struct.
-				inlineStruct = true
+				fi.inlineStruct = true
+				if fi.tag.HasOption(tagOptionInline) && !fi.tag.HasOption(tagOptionSquash) {
+					// Prefix the inline struct's fields with the tag name.
+					fi.prefix = fi.tag.Name
+				}
 				return
 			}
 		}
 		if d != nil {
 			var ok bool
-			dataType, ok = d.TypeMapper(field.Type)
+			fi.dataType, ok = d.TypeMapper(field.Type)
 			if !ok {
 				panic("invalid type of struct field")
 			}
@@ -64,3 +181,16 @@ func extractFieldInfo(field *reflect.StructField, d Dialect) (name, dataType str
 
 	return
 }
+
+// isColumnType returns true if t is scanned and bound as a single column. This is the case for time.Time and for types
+// implementing sql.Scanner or driver.Valuer, through either value or pointer receivers.
+func isColumnType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == reflectTypeTime {
+		return true
+	}
+	ptr := reflect.PtrTo(t)
+	return ptr.Implements(reflectTypeScanner) || ptr.Implements(reflectTypeValuer)
+}
@@ -1,8 +1,11 @@
-package  querier
+package querier
 
 import (
+	"database/sql"
+	"database/sql/driver"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestExtractFieldInfo(t *testing.T) {
@@ -13,7 +16,12 @@ func TestExtractFieldInfo(t *testing.T) {
 		Nationality string `db:",VARCHAR(255) NULL"`
 		Other       string `db:"other,INT"`
 		Complex     struct{}
-		Ignored     string `db:"-"`
+		Ignored     string   `db:"-"`
+		Price       float64  `db:"price,DECIMAL(10,2) NOT NULL"`
+		Address     struct{} `db:"addr_,,inline"`
+		Squashed    struct{} `db:"sq_,,inline,squash"`
+		Pointer     *struct{}
+		Short       struct{} `db:"short_,inline"`
 	}{})
 
 	tests := []struct {
@@ -21,31 +29,135 @@ func TestExtractFieldInfo(t *testing.T) {
 		inDialect        Dialect
 		wantName         string
 		wantDataType     string
+		wantPrefix       string
 		wantIgnore       bool
 		wantInlineStruct bool
 	}{
-		{model.Field(0), nil, "ID", "", false, false},
-		{model.Field(1), Default{}, "Username", "VARCHAR(255) NOT NULL", false, false},
-		{model.Field(2), nil, "age", "", false, false},
-		{model.Field(3), nil, "Nationality", "VARCHAR(255) NULL", false, false},
-		{model.Field(4), nil, "other", "INT", false, false},
-		{model.Field(5), nil, "Complex", "", false, true},
-		{model.Field(6), nil, "-", "", true, false},
+		{model.Field(0), nil, "ID", "", "", false, false},
+		{model.Field(1), Default{}, "Username", "VARCHAR(255) NOT NULL", "", false, false},
+		{model.Field(2), nil, "age", "", "", false, false},
+		{model.Field(3), nil, "Nationality", "VARCHAR(255) NULL", "", false, false},
+		{model.Field(4), nil, "other", "INT", "", false, false},
+		{model.Field(5), nil, "Complex", "", "", false, true},
+		{model.Field(6), nil, "-", "", "", true, false},
+		{model.Field(7), nil, "price", "DECIMAL(10,2) NOT NULL", "", false, false},
+		{model.Field(8), nil, "addr_", "", "addr_", false, true},
+		{model.Field(9), nil, "sq_", "", "", false, true},
+		{model.Field(10), nil, "Pointer", "", "", false, false},
+		{model.Field(11), nil, "short_", "", "short_", false, true},
 	}
 
 	for _, tt := range tests {
-		gotName, gotDataType, gotIgnore, gotInlineStruct := extractFieldInfo(&tt.inField, tt.inDialect)
-		if gotName != tt.wantName {
-			t.Errorf("extractFieldInfo() name = %q, want %q", gotName, tt.wantName)
+		got := extractFieldInfo(&tt.inField, tt.inDialect)
+		if got.name != tt.wantName {
+			t.Errorf("extractFieldInfo() name = %q, want %q", got.name, tt.wantName)
 		}
-		if gotDataType != tt.wantDataType {
-			t.Errorf("extractFieldInfo() dataType = %q, want %q", gotDataType, tt.wantDataType)
+		if got.dataType != tt.wantDataType {
+			t.Errorf("extractFieldInfo() dataType = %q, want %q", got.dataType, tt.wantDataType)
 		}
-		if gotIgnore != tt.wantIgnore {
-			t.Errorf("extractFieldInfo() ignore = %v, want %v", gotIgnore, tt.wantIgnore)
+		if got.prefix != tt.wantPrefix {
+			t.Errorf("extractFieldInfo() prefix = %q, want %q", got.prefix, tt.wantPrefix)
 		}
-		if gotInlineStruct != tt.wantInlineStruct {
-			t.Errorf("extractFieldInfo() inlineStruct = %v, want %v", gotInlineStruct, tt.wantInlineStruct)
+		if got.ignore != tt.wantIgnore {
+			t.Errorf("extractFieldInfo() ignore = %v, want %v", got.ignore, tt.wantIgnore)
+		}
+		if got.inlineStruct != tt.wantInlineStruct {
+			t.Errorf("extractFieldInfo() inlineStruct = %v, want %v", got.inlineStruct, tt.wantInlineStruct)
+		}
+	}
+}
+
+func TestParseFieldTag(t *testing.T) {
+	tests := []struct {
+		in           string
+		wantName     string
+		wantDataType string
+		wantOptions  map[string]string
+	}{
+		{"", "", "", nil},
+		{"name", "name", "", nil},
+		{"name,INT", "name", "INT", nil},
+		{",DECIMAL(10,2) NULL", "", "DECIMAL(10,2) NULL", nil},
+		{"addr_,,inline", "addr_", "", map[string]string{"inline": ""}},
+		{"addr_,inline", "addr_", "", map[string]string{"inline": ""}},
+		{"sq_,inline,squash", "sq_", "", map[string]string{"inline": "", "squash": ""}},
+		{"status,,opt,key=a;b", "status", "", map[string]string{"opt": "", "key": "a;b"}},
+		{"created_at,omitempty", "created_at", "", map[string]string{"omitempty": ""}},
+		{"id,pk,omitempty", "id", "", map[string]string{"pk": "", "omitempty": ""}},
+		{"text,text", "text", "text", nil},
+	}
+
+	for _, tt := range tests {
+		got := ParseFieldTag(tt.in)
+		if got.Name != tt.wantName {
+			t.Errorf("ParseFieldTag(%q) name = %q, want %q", tt.in, got.Name, tt.wantName)
+		}
+		if got.DataType != tt.wantDataType {
+			t.Errorf("ParseFieldTag(%q) dataType = %q, want %q", tt.in, got.DataType, tt.wantDataType)
+		}
+		if !reflect.DeepEqual(got.Options, tt.wantOptions) {
+			t.Errorf("ParseFieldTag(%q) options = %v, want %v", tt.in, got.Options, tt.wantOptions)
+		}
+	}
+}
+
+// testDecimal mimics decimal.Decimal: a struct with a value receiver Valuer and a pointer receiver Scanner.
+type testDecimal struct {
+	value int64
+	exp   int32
+}
+
+func (d testDecimal) Value() (driver.Value, error) { return nil, nil }
+func (d *testDecimal) Scan(interface{}) error      { return nil }
+
+// testValuer is a struct that implements driver.Valuer only.
+type testValuer struct{ v string }
+
+func (v testValuer) Value() (driver.Value, error) { return v.v, nil }
+
+// testUUID mimics uuid.UUID: an array with a value receiver Valuer and a pointer receiver Scanner.
+type testUUID [16]byte
+
+func (u testUUID) Value() (driver.Value, error) { return u[:], nil }
+func (u *testUUID) Scan(interface{}) error      { return nil }
+
+func TestIsColumnType(t *testing.T) {
+	tests := []struct {
+		in   interface{}
+		want bool
+	}{
+		{time.Time{}, true},
+		{&time.Time{}, true},
+		{sql.NullString{}, true},
+		{&sql.NullString{}, true},
+		{testDecimal{}, true},
+		{&testDecimal{}, true},
+		{testValuer{}, true},
+		{&testValuer{}, true},
+		{testUUID{}, true},
+		{struct{}{}, false},
+		{&struct{ ID int }{}, false},
+	}
+
+	for _, tt := range tests {
+		if got := isColumnType(reflect.TypeOf(tt.in)); got != tt.want {
+			t.Errorf("isColumnType(%T) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestExtractFieldInfoCustomTypes(t *testing.T) {
+	model := reflect.TypeOf(struct {
+		Price    testDecimal  `db:",DECIMAL(10,2) NOT NULL"`
+		Discount *testDecimal `db:",DECIMAL(10,2) NULL"`
+		Label    testValuer
+		UUID     testUUID `db:",BINARY(16) NOT NULL"`
+	}{})
+
+	for i := 0; i < model.NumField(); i++ {
+		field := model.Field(i)
+		if fi := extractFieldInfo(&field, nil); fi.inlineStruct {
+			t.Errorf("extractFieldInfo(%s) inlineStruct = true, want false", field.Name)
 		}
 	}
 }
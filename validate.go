@@ -0,0 +1,188 @@
+package querier
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// tagOptionRequired marks a field whose zero value is rejected by InsertStruct and UpdateStruct, e.g.
+// `db:"email,,required"`.
+const tagOptionRequired = "required"
+
+// ErrInvalidStruct means that a struct failed validation by InsertStruct or UpdateStruct. The error returned is
+// FieldErrors, unless a Validator returned another error.
+var ErrInvalidStruct = errors.New("invalid struct")
+
+// ValidationError describes a mistake in a query found by Validate.
+type ValidationError struct {
+	// Offset is the byte offset of the mistake in the query, or -1 if it applies to the whole query.
+	Offset  int
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Offset < 0 {
+		return "invalid query: " + e.Message
+	}
+	return fmt.Sprintf("invalid query at offset %d: %s", e.Offset, e.Message)
+}
+
+// Validate checks the query for common mistakes before it's executed. It returns the error that occurred while
+// building the query, if any, or a *ValidationError if the query:
+//
+//   - is empty;
+//   - has an unterminated string literal, quoted identifier or comment;
+//   - has unbalanced parentheses;
+//   - has an unknown placeholder, e.g. {bindvar} instead of {bindVar};
+//   - has a different number of bind variables than parameters, if its bind variables are tracked, see
+//     ParamMismatchError.
+//
+// String literals, quoted identifiers and comments are not checked.
+func (q *Q) Validate() error {
+	if q.buildErr != nil {
+		return q.buildErr
+	}
+	query := q.query.String()
+	if query == "" {
+		return &ValidationError{Offset: -1, Message: "query is empty"}
+	}
+
+	var open []int
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end := skipQuoted(query, i)
+			if end == len(query) && (end-i < 2 || query[end-1] != c) {
+				return &ValidationError{Offset: i, Message: "unterminated quoted string"}
+			}
+			i = end
+			continue
+		case c == '-' && i+1 < len(query) && query[i+1] == '-':
+			i = indexFrom(query, "\n", i)
+			continue
+		case c == '/' && i+1 < len(query) && query[i+1] == '*':
+			end := indexFrom(query, "*/", i+2)
+			if end == len(query) {
+				return &ValidationError{Offset: i, Message: "unterminated comment"}
+			}
+			i = end + 2
+			continue
+		case c == '(':
+			open = append(open, i)
+		case c == ')':
+			if len(open) == 0 {
+				return &ValidationError{Offset: i, Message: "unbalanced closing parenthesis"}
+			}
+			open = open[:len(open)-1]
+		case c == '{':
+			if end := indexFrom(query, "}", i); end < len(query) && isPlaceholderName(query[i+1:end]) {
+				return &ValidationError{Offset: i, Message: fmt.Sprintf("unknown placeholder %s", query[i:end+1])}
+			}
+		}
+		i++
+	}
+	if len(open) > 0 {
+		return &ValidationError{Offset: open[len(open)-1], Message: "unterminated parenthesis"}
+	}
+	if err := q.checkBuild(); err != nil {
+		return &ValidationError{Offset: -1, Message: err.Error()}
+	}
+	return nil
+}
+
+// isPlaceholderName returns true if s looks like the name of a placeholder, e.g. "bindvar".
+func isPlaceholderName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isNameByte(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Validator is implemented by structs that validate themselves. InsertStruct and UpdateStruct call Validate before they
+// write the statement, which isn't executed if it returns an error. Validate may return FieldErrors to report
+// mistakes per field.
+type Validator interface {
+	Validate() error
+}
+
+// FieldError describes an invalid field of a struct.
+type FieldError struct {
+	// Field is the name of the field.
+	Field   string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// FieldErrors contains the invalid fields of a struct, see Validator.
+type FieldErrors []FieldError
+
+func (e FieldErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i := range e {
+		msgs[i] = e[i].Error()
+	}
+	return "invalid struct: " + strings.Join(msgs, "; ")
+}
+
+// Is returns true if target is ErrInvalidStruct.
+func (e FieldErrors) Is(target error) bool {
+	return target == ErrInvalidStruct
+}
+
+var (
+	structValidatorMu sync.RWMutex
+	structValidator   func(i interface{}) error
+)
+
+// SetStructValidator sets a function that validates the structs of InsertStruct and UpdateStruct after their own
+// Validate method, e.g. to use the validate tags of a validation package. nil removes it.
+func SetStructValidator(fn func(i interface{}) error) {
+	structValidatorMu.Lock()
+	defer structValidatorMu.Unlock()
+	structValidator = fn
+}
+
+// validateStruct validates struct i with the fields and values of the statement. The required fields are checked
+// first, the errors of the validators are merged with theirs if they are FieldErrors.
+func validateStruct(i interface{}, fields []Field, values ValueMap) error {
+	var errs FieldErrors
+	for _, field := range fields {
+		if field.Required && isZeroValue(values[field.Name]) {
+			errs = append(errs, FieldError{Field: field.Name, Message: "is required"})
+		}
+	}
+	validators := make([]func() error, 0, 2)
+	if v, ok := i.(Validator); ok {
+		validators = append(validators, v.Validate)
+	}
+	structValidatorMu.RLock()
+	fn := structValidator
+	structValidatorMu.RUnlock()
+	if fn != nil {
+		validators = append(validators, func() error { return fn(i) })
+	}
+	for _, validate := range validators {
+		err := validate()
+		var fieldErrs FieldErrors
+		if errors.As(err, &fieldErrs) {
+			errs = append(errs, fieldErrs...)
+		} else if err != nil {
+			return err
+		}
+	}
+	if errs != nil {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package querier
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		q          *Q
+		wantOffset int
+	}{
+		{New(nil, Default{}).Write("SELECT * FROM user WHERE id = ? AND name = '?(' -- ?(", 1), 0},
+		{New(nil, Default{}).Write("SELECT * FROM user WHERE id IN (?, ?)", 1, 2).Write("/* ) */"), 0},
+		{New(nil, numberedDialect{}).Write("SELECT * FROM user WHERE id = $1"), 0},
+		{New(nil, Default{}), -1},
+		{New(nil, Default{}).Write("SELECT 'foo"), 7},
+		{New(nil, Default{}).Write("SELECT 1 /* comment"), 9},
+		{New(nil, Default{}).Write("SELECT (1 + (2)"), 7},
+		{New(nil, Default{}).Write("SELECT 1)"), 8},
+		{New(nil, Default{}).Write("SELECT id FROM user WHERE id = {bindvar}"), 31},
+		{New(nil, Default{}).Write("SELECT * FROM user WHERE id = ?"), -1},
+		{New(nil, Default{}).Write("SELECT * FROM user", 1), -1},
+	}
+	for i, tt := range tests {
+		err := tt.q.Validate()
+		if i < 3 {
+			if err != nil {
+				t.Errorf("Validate() of %q = %v, want nil", tt.q.String(), err)
+			}
+			continue
+		}
+		verr, ok := err.(*ValidationError)
+		if !ok {
+			t.Errorf("Validate() of %q = %v, want a *ValidationError", tt.q.String(), err)
+			continue
+		}
+		if verr.Offset != tt.wantOffset {
+			t.Errorf("Validate() of %q offset = %d, want %d (%v)", tt.q.String(), verr.Offset, tt.wantOffset, err)
+		}
+	}
+
+	errBuild := errors.New("build error")
+	q := New(nil, Default{}).Write("SELECT 1")
+	q.fail(errBuild)
+	if err := q.Validate(); err != errBuild {
+		t.Errorf("Validate() = %v, want %v", err, errBuild)
+	}
+}